@@ -0,0 +1,413 @@
+// Package cassette records the HTTP requests of the Client to files and replays them offline.
+// It is meant for tests that should not call the live API on every run
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	whoisapi "github.com/whois-api-llc/whois-api-go"
+)
+
+// Mode defines whether the Recorder calls the real server or the cassette
+type Mode int
+
+const (
+	// ModeReplay serves the recorded responses, nothing is sent over the network
+	ModeReplay Mode = iota
+
+	// ModeRecord sends the requests with Transport and keeps the interactions for Save
+	ModeRecord
+)
+
+// Matching defines how a request is matched with the recorded ones
+type Matching int
+
+const (
+	// MatchStrict requires the same method, path and query parameters,
+	// and every interaction is replayed once in the order of recording
+	MatchStrict Matching = iota
+
+	// MatchLenient requires the same method and path, and the parameters of the recorded request
+	// to have the same values. Other parameters are ignored and interactions are replayed any number of times
+	MatchLenient
+)
+
+const (
+	// apiKeyParam is the query parameter and the JSON body field with the API key
+	apiKeyParam = "apiKey"
+
+	// authHeader is the header with the API key
+	authHeader = "X-Authentication-Token"
+
+	// redactedValue replaces the API keys in cassettes
+	redactedValue = "REDACTED"
+)
+
+// ErrNoInteraction is returned in replay mode when no recorded interaction matches the request
+var ErrNoInteraction = errors.New("no matching interaction in cassette")
+
+// Params is used to create Recorder
+type Params struct {
+	// Path is the cassette file
+	Path string
+
+	// Mode defines whether the requests are recorded or replayed. ModeReplay is used by default
+	Mode Mode
+
+	// Matching defines how the requests are matched in replay mode. MatchStrict is used by default
+	Matching Matching
+
+	// Transport sends the requests in record mode. http.DefaultTransport is used if nil
+	Transport http.RoundTripper
+
+	// Secrets are scrubbed from the cassette in addition to the API keys found in the requests
+	Secrets []string
+}
+
+// Request is the recorded request with the API key scrubbed
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// Response is the recorded response
+type Response struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// Interaction is the recorded request and its response
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Cassette is the content of the cassette file
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Recorder is the http.RoundTripper recording and replaying interactions.
+// Use it as the Transport of ClientParams.HTTPClient
+type Recorder struct {
+	params Params
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+	secrets  []string
+}
+
+var _ http.RoundTripper = &Recorder{}
+
+// New creates Recorder with specified parameters. In replay mode the cassette file is loaded
+func New(params Params) (*Recorder, error) {
+	if params.Path == "" {
+		return nil, fmt.Errorf("cannot create recorder: empty cassette path")
+	}
+	if params.Transport == nil {
+		params.Transport = http.DefaultTransport
+	}
+
+	r := &Recorder{params: params}
+	for _, s := range params.Secrets {
+		r.addSecret(s)
+	}
+
+	if params.Mode == ModeReplay {
+		b, err := os.ReadFile(params.Path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read cassette: %w", err)
+		}
+		if err := json.Unmarshal(b, &r.cassette); err != nil {
+			return nil, fmt.Errorf("cannot parse cassette %s: %w", params.Path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+
+	return r, nil
+}
+
+// Client returns the http.Client using the Recorder as its Transport
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Interactions returns the recorded or the loaded interactions
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Interaction(nil), r.cassette.Interactions...)
+}
+
+// RoundTrip records or replays the request
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.params.Mode == ModeRecord {
+		return r.record(req, body)
+	}
+	return r.replay(req, body)
+}
+
+// Save writes the recorded interactions to the cassette file. It does nothing in replay mode
+func (r *Recorder) Save() error {
+	if r.params.Mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	b, err := json.MarshalIndent(&r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("cannot marshal cassette: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.params.Path), 0o755); err != nil {
+		return fmt.Errorf("cannot create cassette directory: %w", err)
+	}
+	if err := os.WriteFile(r.params.Path, append(b, '\n'), 0o644); err != nil {
+		return fmt.Errorf("cannot write cassette: %w", err)
+	}
+	return nil
+}
+
+// record sends the request and keeps the scrubbed interaction
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	if body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	resp, err := r.params.Transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read response: %w", err)
+	}
+
+	r.mu.Lock()
+	for _, key := range apiKeys(req, body) {
+		r.addSecret(key)
+	}
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    r.scrub(req.URL.String()),
+			Header: r.scrubHeader(req.Header),
+			Body:   r.scrub(string(body)),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     r.scrubHeader(resp.Header),
+			Body:       r.scrub(string(respBody)),
+		},
+	})
+	r.mu.Unlock()
+
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	resp.ContentLength = int64(len(respBody))
+	return resp, nil
+}
+
+// replay returns the response of the matching interaction
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	params := requestParams(req.URL, body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	found := -1
+	for i, in := range r.cassette.Interactions {
+		if r.params.Matching == MatchStrict && r.used[i] {
+			continue
+		}
+		if !r.matches(in.Request, req, params) {
+			continue
+		}
+		if found < 0 {
+			found = i
+		}
+		// lenient matching prefers the interactions that weren't replayed yet
+		if !r.used[i] {
+			found = i
+			break
+		}
+	}
+	if found < 0 {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, req.Method, whoisapi.RedactURL(req.URL))
+	}
+	r.used[found] = true
+
+	rec := r.cassette.Interactions[found].Response
+	header := rec.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.StatusCode, http.StatusText(rec.StatusCode)),
+		StatusCode:    rec.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(rec.Body)),
+		ContentLength: int64(len(rec.Body)),
+		Request:       req,
+	}, nil
+}
+
+// matches reports whether the recorded request matches the request with the parameters
+func (r *Recorder) matches(rec Request, req *http.Request, params url.Values) bool {
+	if rec.Method != req.Method {
+		return false
+	}
+	u, err := url.Parse(rec.URL)
+	if err != nil || u.Path != req.URL.Path {
+		return false
+	}
+
+	recorded := requestParams(u, []byte(rec.Body))
+	if r.params.Matching == MatchStrict {
+		return equalValues(recorded, params)
+	}
+	for k, v := range recorded {
+		if !equalStrings(v, params[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+// addSecret adds the string scrubbed from the interactions
+func (r *Recorder) addSecret(s string) {
+	if s == "" || s == redactedValue {
+		return
+	}
+	for _, known := range r.secrets {
+		if known == s {
+			return
+		}
+	}
+	r.secrets = append(r.secrets, s)
+}
+
+// scrub replaces the secrets in the string
+func (r *Recorder) scrub(s string) string {
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, redactedValue)
+	}
+	return s
+}
+
+// scrubHeader returns the copy of the header with the secrets replaced
+func (r *Recorder) scrubHeader(h http.Header) http.Header {
+	if len(h) == 0 {
+		return nil
+	}
+	scrubbed := make(http.Header, len(h))
+	for k, vs := range h {
+		for _, v := range vs {
+			scrubbed.Add(k, r.scrub(v))
+		}
+	}
+	if scrubbed.Get(authHeader) != "" {
+		scrubbed.Set(authHeader, redactedValue)
+	}
+	return scrubbed
+}
+
+// readBody reads the request body and restores it for the transport
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	b, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read request body: %w", err)
+	}
+	if err := req.Body.Close(); err != nil {
+		return nil, fmt.Errorf("cannot close request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(b))
+	return b, nil
+}
+
+// apiKeys returns the API keys sent in the query, the header and the JSON body
+func apiKeys(req *http.Request, body []byte) []string {
+	keys := req.URL.Query()[apiKeyParam]
+	if key := req.Header.Get(authHeader); key != "" {
+		keys = append(keys, key)
+	}
+	var payload map[string]interface{}
+	if json.Unmarshal(body, &payload) == nil {
+		if key, ok := payload[apiKeyParam].(string); ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// requestParams returns the query parameters and the fields of the JSON body without the API key
+func requestParams(u *url.URL, body []byte) url.Values {
+	params := u.Query()
+	var payload map[string]interface{}
+	if json.Unmarshal(body, &payload) == nil {
+		for k, v := range payload {
+			params.Add(k, fmt.Sprint(v))
+		}
+	}
+	params.Del(apiKeyParam)
+	return params
+}
+
+// equalValues reports whether the parameters are the same regardless of the order of the names
+func equalValues(a, b url.Values) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if !equalStrings(v, b[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+// equalStrings reports whether the values are the same regardless of their order
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = append([]string(nil), a...), append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,232 @@
+package cassette
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	whoisapi "github.com/whois-api-llc/whois-api-go"
+)
+
+const apiKey = "at_LoremIpsumDolorSitAmetConsect"
+
+const whoisResponse = `{"WhoisRecord":{"domainName":"whoisxmlapi.com","registrarName":"GoDaddy.com, LLC",` +
+	`"header":"queried with ` + apiKey + `"}}`
+
+// newServer returns the API server stub counting the requests
+func newServer(calls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(whoisResponse))
+	}))
+}
+
+// newClient returns the Whois API client using the recorder
+func newClient(rec *Recorder, baseURL string, mode whoisapi.AuthMode) *whoisapi.Client {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		panic(err)
+	}
+	u.Path = "/whoisserver/WhoisService"
+	return whoisapi.NewClient(apiKey, whoisapi.ClientParams{
+		HTTPClient:   rec.Client(),
+		WhoisBaseURL: u,
+		AuthMode:     mode,
+	})
+}
+
+// record records the lookups of the names to the cassette
+func record(t *testing.T, path string, mode whoisapi.AuthMode, names ...string) {
+	t.Helper()
+
+	var calls int32
+	server := newServer(&calls)
+	defer server.Close()
+
+	rec, err := New(Params{Path: path, Mode: ModeRecord})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := newClient(rec, server.URL, mode)
+	for _, name := range names {
+		if _, _, err := client.Data(context.Background(), name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if int(calls) != len(names) {
+		t.Errorf("calls got = %d, want %d", calls, len(names))
+	}
+}
+
+// TestRecordReplay tests that the recorded lookups are replayed offline without the API key
+func TestRecordReplay(t *testing.T) {
+	modes := []struct {
+		name string
+		mode whoisapi.AuthMode
+	}{
+		{"query", whoisapi.AuthModeQuery},
+		{"header", whoisapi.AuthModeHeader},
+		{"body", whoisapi.AuthModeBody},
+	}
+
+	for _, tt := range modes {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "testdata", "whois.json")
+			record(t, path, tt.mode, "whoisxmlapi.com")
+
+			b, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(b), apiKey) {
+				t.Errorf("cassette contains the API key:\n%s", b)
+			}
+			if !strings.Contains(string(b), redactedValue) {
+				t.Errorf("cassette doesn't contain %s:\n%s", redactedValue, b)
+			}
+
+			rec, err := New(Params{Path: path})
+			if err != nil {
+				t.Fatal(err)
+			}
+			// nothing listens on the replayed host
+			client := newClient(rec, "http://127.0.0.1:1", tt.mode)
+			whoisRecord, resp, err := client.Data(context.Background(), "whoisxmlapi.com")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if whoisRecord.RegistrarName != "GoDaddy.com, LLC" || resp.StatusCode != http.StatusOK {
+				t.Errorf("Data() got = %+v, %d", whoisRecord, resp.StatusCode)
+			}
+			if whoisRecord.Header != "queried with "+redactedValue {
+				t.Errorf("Header got = %q", whoisRecord.Header)
+			}
+		})
+	}
+}
+
+// TestMatching tests the strict and lenient matching of the replayed requests
+func TestMatching(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "whois.json")
+	record(t, path, whoisapi.AuthModeQuery, "whoisxmlapi.com")
+
+	tests := []struct {
+		name     string
+		matching Matching
+		lookups  []func(*whoisapi.Client) error
+		wantErr  []bool
+	}{
+		{
+			name:     "strict replays once",
+			matching: MatchStrict,
+			lookups:  []func(*whoisapi.Client) error{lookup("whoisxmlapi.com"), lookup("whoisxmlapi.com")},
+			wantErr:  []bool{false, true},
+		},
+		{
+			name:     "strict extra parameter",
+			matching: MatchStrict,
+			lookups:  []func(*whoisapi.Client) error{lookup("whoisxmlapi.com", whoisapi.OptionThinWhois(1))},
+			wantErr:  []bool{true},
+		},
+		{
+			name:     "strict other name",
+			matching: MatchStrict,
+			lookups:  []func(*whoisapi.Client) error{lookup("example.com")},
+			wantErr:  []bool{true},
+		},
+		{
+			name:     "lenient replays again",
+			matching: MatchLenient,
+			lookups:  []func(*whoisapi.Client) error{lookup("whoisxmlapi.com"), lookup("whoisxmlapi.com")},
+			wantErr:  []bool{false, false},
+		},
+		{
+			name:     "lenient extra parameter",
+			matching: MatchLenient,
+			lookups:  []func(*whoisapi.Client) error{lookup("whoisxmlapi.com", whoisapi.OptionThinWhois(1))},
+			wantErr:  []bool{false},
+		},
+		{
+			name:     "lenient other name",
+			matching: MatchLenient,
+			lookups:  []func(*whoisapi.Client) error{lookup("example.com")},
+			wantErr:  []bool{true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, err := New(Params{Path: path, Matching: tt.matching})
+			if err != nil {
+				t.Fatal(err)
+			}
+			client := newClient(rec, "http://127.0.0.1:1", whoisapi.AuthModeQuery)
+			for i, lookup := range tt.lookups {
+				err := lookup(client)
+				if (err != nil) != tt.wantErr[i] {
+					t.Fatalf("lookup %d error = %v, wantErr %v", i, err, tt.wantErr[i])
+				}
+				if err != nil && !errors.Is(err, ErrNoInteraction) {
+					t.Errorf("lookup %d error = %v, want ErrNoInteraction", i, err)
+				}
+				if err != nil && strings.Contains(err.Error(), apiKey) {
+					t.Errorf("lookup %d error contains the API key: %v", i, err)
+				}
+			}
+		})
+	}
+}
+
+// lookup returns the lookup of the name with the options
+func lookup(name string, opts ...whoisapi.Option) func(*whoisapi.Client) error {
+	return func(c *whoisapi.Client) error {
+		_, _, err := c.Data(context.Background(), name, opts...)
+		return err
+	}
+}
+
+// TestNew tests the creation errors and the secrets
+func TestNew(t *testing.T) {
+	if _, err := New(Params{}); err == nil {
+		t.Error("New() with empty path error = nil")
+	}
+	if _, err := New(Params{Path: filepath.Join(t.TempDir(), "missing.json")}); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("New() with missing cassette error = %v, want os.ErrNotExist", err)
+	}
+
+	var calls int32
+	server := newServer(&calls)
+	defer server.Close()
+
+	rec, err := New(Params{Path: filepath.Join(t.TempDir(), "whois.json"), Mode: ModeRecord, Secrets: []string{"GoDaddy"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := newClient(rec, server.URL, whoisapi.AuthModeQuery)
+	if _, _, err := client.Data(context.Background(), "whoisxmlapi.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	interactions := rec.Interactions()
+	if len(interactions) != 1 {
+		t.Fatalf("Interactions() got = %d, want 1", len(interactions))
+	}
+	body := interactions[0].Response.Body
+	if strings.Contains(body, "GoDaddy") || strings.Contains(body, apiKey) {
+		t.Errorf("response body isn't scrubbed: %s", body)
+	}
+	if u := interactions[0].Request.URL; !strings.Contains(u, "apiKey="+redactedValue) {
+		t.Errorf("request URL isn't scrubbed: %s", u)
+	}
+}
@@ -0,0 +1,241 @@
+package whoisapi
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultFailureThreshold is the default number of consecutive failures that opens the circuit
+	DefaultFailureThreshold = 5
+
+	// DefaultOpenTimeout is the default time the circuit stays open before probing the endpoint
+	DefaultOpenTimeout = 30 * time.Second
+
+	// DefaultHalfOpenProbes is the default number of probe requests in the half-open state
+	DefaultHalfOpenProbes = 1
+)
+
+// ErrCircuitOpen is returned without sending the request when the CircuitBreaker is open
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState is the state of the CircuitBreaker
+type CircuitState int
+
+const (
+	// CircuitClosed sends all requests
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen fails all requests with ErrCircuitOpen
+	CircuitOpen
+
+	// CircuitHalfOpen sends a limited number of probe requests, the others fail with ErrCircuitOpen
+	CircuitHalfOpen
+)
+
+// String returns the name of the state
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// circuitResult is the outcome of a request for the CircuitBreaker
+type circuitResult int
+
+const (
+	// circuitSuccess is a response with a status code below 500
+	circuitSuccess circuitResult = iota
+
+	// circuitFailure is a transport error or a 5xx response
+	circuitFailure
+
+	// circuitIgnored is a request cancelled by the caller, it's neither a success nor a failure
+	circuitIgnored
+)
+
+// circuitChange is the state change of the CircuitBreaker
+type circuitChange struct {
+	from, to CircuitState
+}
+
+// circuitTicket identifies the state a request was allowed in
+type circuitTicket struct {
+	generation uint64
+}
+
+// CircuitBreaker stops sending requests to the endpoint after consecutive transport errors and 5xx responses.
+// After OpenTimeout it lets HalfOpenProbes requests through and closes again if all of them succeed.
+// It is safe for concurrent use
+type CircuitBreaker struct {
+	// FailureThreshold is the number of consecutive failures that opens the circuit,
+	// DefaultFailureThreshold is used if zero
+	FailureThreshold int
+
+	// OpenTimeout is the time the circuit stays open before the half-open state, DefaultOpenTimeout is used if zero
+	OpenTimeout time.Duration
+
+	// HalfOpenProbes is the number of concurrent requests sent in the half-open state
+	// and the number of their successes that closes the circuit, DefaultHalfOpenProbes is used if zero
+	HalfOpenProbes int
+
+	// OnStateChange is called after every state change. It may be called concurrently
+	OnStateChange func(from, to CircuitState)
+
+	// Now returns the current time, time.Now is used if nil
+	Now func() time.Time
+
+	mu        sync.Mutex
+	state     CircuitState
+	failures  int
+	probes    int
+	successes int
+	openedAt  time.Time
+
+	// generation is incremented on every state change, results of the requests
+	// allowed in another generation are ignored
+	generation uint64
+}
+
+// NewCircuitBreaker creates the closed CircuitBreaker with default parameters.
+// The zero value is ready to use too
+func NewCircuitBreaker() *CircuitBreaker {
+	return &CircuitBreaker{Now: time.Now}
+}
+
+// State returns the current state
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen && !b.timeNow().Before(b.openedAt.Add(b.openTimeout())) {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+// allow reports whether the request may be sent, it returns ErrCircuitOpen if it may not.
+// Every allowed request must be followed by record with the returned ticket
+func (b *CircuitBreaker) allow() (circuitTicket, *circuitChange, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var change *circuitChange
+	if b.state == CircuitOpen {
+		if b.timeNow().Before(b.openedAt.Add(b.openTimeout())) {
+			return circuitTicket{}, nil, ErrCircuitOpen
+		}
+		change = b.setState(CircuitHalfOpen)
+	}
+
+	if b.state == CircuitHalfOpen {
+		if b.probes >= b.halfOpenProbes() {
+			return circuitTicket{}, change, ErrCircuitOpen
+		}
+		b.probes++
+	}
+
+	return circuitTicket{generation: b.generation}, change, nil
+}
+
+// record records the result of the request allowed with the ticket
+func (b *CircuitBreaker) record(ticket circuitTicket, result circuitResult) *circuitChange {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if ticket.generation != b.generation {
+		// the request was allowed before the last state change, e.g. it was sent
+		// while the circuit was closed and finished after it opened
+		return nil
+	}
+
+	switch b.state {
+	case CircuitClosed:
+		switch result {
+		case circuitSuccess:
+			b.failures = 0
+		case circuitFailure:
+			b.failures++
+			if b.failures >= b.failureThreshold() {
+				return b.setState(CircuitOpen)
+			}
+		}
+
+	case CircuitHalfOpen:
+		b.probes--
+		switch result {
+		case circuitSuccess:
+			b.successes++
+			if b.successes >= b.halfOpenProbes() {
+				return b.setState(CircuitClosed)
+			}
+		case circuitFailure:
+			return b.setState(CircuitOpen)
+		}
+	}
+
+	return nil
+}
+
+// setState changes the state and resets the counters
+func (b *CircuitBreaker) setState(state CircuitState) *circuitChange {
+	change := &circuitChange{from: b.state, to: state}
+
+	b.state = state
+	b.generation++
+	b.failures, b.successes = 0, 0
+	if state != CircuitHalfOpen {
+		b.probes = 0
+	}
+	if state == CircuitOpen {
+		b.openedAt = b.timeNow()
+	}
+
+	return change
+}
+
+// notify calls OnStateChange with the change, if any
+func (b *CircuitBreaker) notify(change *circuitChange) {
+	if change != nil && b.OnStateChange != nil {
+		b.OnStateChange(change.from, change.to)
+	}
+}
+
+// timeNow returns the current time of the Now clock
+func (b *CircuitBreaker) timeNow() time.Time {
+	if b.Now == nil {
+		return time.Now()
+	}
+	return b.Now()
+}
+
+// failureThreshold returns FailureThreshold or its default
+func (b *CircuitBreaker) failureThreshold() int {
+	if b.FailureThreshold > 0 {
+		return b.FailureThreshold
+	}
+	return DefaultFailureThreshold
+}
+
+// openTimeout returns OpenTimeout or its default
+func (b *CircuitBreaker) openTimeout() time.Duration {
+	if b.OpenTimeout > 0 {
+		return b.OpenTimeout
+	}
+	return DefaultOpenTimeout
+}
+
+// halfOpenProbes returns HalfOpenProbes or its default
+func (b *CircuitBreaker) halfOpenProbes() int {
+	if b.HalfOpenProbes > 0 {
+		return b.HalfOpenProbes
+	}
+	return DefaultHalfOpenProbes
+}
@@ -0,0 +1,317 @@
+package whoisapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// fakeClock is the clock of the CircuitBreaker moved by tests
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// Now returns the current fake time
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Add moves the clock forward
+func (c *fakeClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// newTestBreaker returns the CircuitBreaker with the fake clock recording its state changes
+func newTestBreaker(clock *fakeClock, changes *[]string) *CircuitBreaker {
+	b := NewCircuitBreaker()
+	b.Now = clock.Now
+	b.FailureThreshold = 3
+	b.OpenTimeout = time.Minute
+	b.HalfOpenProbes = 2
+
+	var mu sync.Mutex
+	b.OnStateChange = func(from, to CircuitState) {
+		mu.Lock()
+		defer mu.Unlock()
+		*changes = append(*changes, from.String()+">"+to.String())
+	}
+	return b
+}
+
+// TestCircuitBreakerStates tests the state transitions
+func TestCircuitBreakerStates(t *testing.T) {
+	type step struct {
+		advance   time.Duration
+		result    circuitResult
+		wantAllow bool
+		wantState CircuitState
+	}
+
+	tests := []struct {
+		name        string
+		steps       []step
+		wantChanges []string
+	}{
+		{
+			name: "success resets failures",
+			steps: []step{
+				{result: circuitFailure, wantAllow: true, wantState: CircuitClosed},
+				{result: circuitFailure, wantAllow: true, wantState: CircuitClosed},
+				{result: circuitSuccess, wantAllow: true, wantState: CircuitClosed},
+				{result: circuitFailure, wantAllow: true, wantState: CircuitClosed},
+				{result: circuitIgnored, wantAllow: true, wantState: CircuitClosed},
+				{result: circuitFailure, wantAllow: true, wantState: CircuitClosed},
+			},
+		},
+		{
+			name: "open, half-open, closed",
+			steps: []step{
+				{result: circuitFailure, wantAllow: true, wantState: CircuitClosed},
+				{result: circuitFailure, wantAllow: true, wantState: CircuitClosed},
+				{result: circuitFailure, wantAllow: true, wantState: CircuitOpen},
+				{advance: 59 * time.Second, wantAllow: false, wantState: CircuitOpen},
+				{advance: time.Second, result: circuitSuccess, wantAllow: true, wantState: CircuitHalfOpen},
+				{result: circuitSuccess, wantAllow: true, wantState: CircuitClosed},
+			},
+			wantChanges: []string{"closed>open", "open>half-open", "half-open>closed"},
+		},
+		{
+			name: "failed probe opens again",
+			steps: []step{
+				{result: circuitFailure, wantAllow: true, wantState: CircuitClosed},
+				{result: circuitFailure, wantAllow: true, wantState: CircuitClosed},
+				{result: circuitFailure, wantAllow: true, wantState: CircuitOpen},
+				{advance: time.Minute, result: circuitSuccess, wantAllow: true, wantState: CircuitHalfOpen},
+				{result: circuitFailure, wantAllow: true, wantState: CircuitOpen},
+				{advance: 30 * time.Second, wantAllow: false, wantState: CircuitOpen},
+			},
+			wantChanges: []string{"closed>open", "open>half-open", "half-open>open"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := &fakeClock{now: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)}
+			var changes []string
+			b := newTestBreaker(clock, &changes)
+
+			for i, s := range tt.steps {
+				clock.Add(s.advance)
+				ticket, change, err := b.allow()
+				b.notify(change)
+				if (err == nil) != s.wantAllow {
+					t.Fatalf("step %d allow() error = %v, want allowed %v", i, err, s.wantAllow)
+				}
+				if err == nil {
+					b.notify(b.record(ticket, s.result))
+				}
+				if got := b.State(); got != s.wantState {
+					t.Errorf("step %d State() got = %v, want %v", i, got, s.wantState)
+				}
+			}
+			if !reflect.DeepEqual(changes, tt.wantChanges) {
+				t.Errorf("changes got = %v, want %v", changes, tt.wantChanges)
+			}
+		})
+	}
+}
+
+// TestCircuitBreakerProbes tests that the half-open state limits the concurrent probes
+func TestCircuitBreakerProbes(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)}
+	var changes []string
+	b := newTestBreaker(clock, &changes)
+	for i := 0; i < 3; i++ {
+		ticket, _, _ := b.allow()
+		b.record(ticket, circuitFailure)
+	}
+	clock.Add(time.Minute)
+
+	var probes []circuitTicket
+	for i := 0; i < 2; i++ {
+		ticket, _, err := b.allow()
+		if err != nil {
+			t.Fatalf("probe %d allow() error = %v", i, err)
+		}
+		probes = append(probes, ticket)
+	}
+	if _, _, err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("third probe allow() error = %v, want ErrCircuitOpen", err)
+	}
+
+	// the cancelled probe frees its slot
+	b.record(probes[0], circuitIgnored)
+	if _, _, err := b.allow(); err != nil {
+		t.Errorf("allow() after the cancelled probe error = %v", err)
+	}
+}
+
+// TestCircuitBreakerStaleResults tests that the results of requests allowed before a state change are ignored
+func TestCircuitBreakerStaleResults(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)}
+	var changes []string
+	b := newTestBreaker(clock, &changes)
+
+	// slow requests sent while the circuit is closed
+	slowFailure, _, _ := b.allow()
+	slowSuccess, _, _ := b.allow()
+	for i := 0; i < 3; i++ {
+		ticket, _, _ := b.allow()
+		b.notify(b.record(ticket, circuitFailure))
+	}
+	clock.Add(time.Minute)
+
+	probe, change, err := b.allow()
+	b.notify(change)
+	if err != nil {
+		t.Fatalf("probe allow() error = %v", err)
+	}
+
+	// neither opens the circuit again, takes a probe slot or counts as a probe success
+	b.notify(b.record(slowFailure, circuitFailure))
+	b.notify(b.record(slowSuccess, circuitSuccess))
+	if state := b.State(); state != CircuitHalfOpen {
+		t.Fatalf("State() got = %v, want %v", state, CircuitHalfOpen)
+	}
+	second, _, err := b.allow()
+	if err != nil {
+		t.Fatalf("second probe allow() error = %v", err)
+	}
+	if _, _, err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("third probe allow() error = %v, want ErrCircuitOpen", err)
+	}
+
+	b.notify(b.record(probe, circuitSuccess))
+	b.notify(b.record(second, circuitSuccess))
+	if state := b.State(); state != CircuitClosed {
+		t.Errorf("State() got = %v, want %v", state, CircuitClosed)
+	}
+
+	wantChanges := []string{"closed>open", "open>half-open", "half-open>closed"}
+	if !reflect.DeepEqual(changes, wantChanges) {
+		t.Errorf("changes got = %v, want %v", changes, wantChanges)
+	}
+}
+
+// TestCircuitBreakerClient tests that the client fails fast when the circuit is open
+func TestCircuitBreakerClient(t *testing.T) {
+	var calls int32
+	var status int32 = http.StatusBadGateway
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(int(atomic.LoadInt32(&status)))
+		_, _ = w.Write([]byte(`{"WhoisRecord": {"domainName": "whoisxmlapi.com"}}`))
+	}))
+	defer server.Close()
+
+	apiURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clock := &fakeClock{now: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)}
+	var changes []string
+	breaker := newTestBreaker(clock, &changes)
+	breaker.HalfOpenProbes = 1
+	reader := sdkmetric.NewManualReader()
+	// the shared request of a cancelled caller would finish in the background
+	client := NewClient(apiKey, ClientParams{
+		HTTPClient:           server.Client(),
+		WhoisBaseURL:         apiURL,
+		CircuitBreaker:       breaker,
+		MeterProvider:        sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+		DisableDeduplication: true,
+	})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, err := client.RawData(ctx, "whoisxmlapi.com")
+		var respErr ErrorResponse
+		if !errors.As(err, &respErr) {
+			t.Fatalf("RawData() error = %v, want ErrorResponse", err)
+		}
+	}
+
+	_, err = client.RawData(ctx, "whoisxmlapi.com")
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("RawData() error = %v, want ErrCircuitOpen", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls got = %d, want 3", calls)
+	}
+
+	// a request cancelled by the caller is not a failure
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	clock.Add(time.Minute)
+	if _, err := client.RawData(cancelled, "whoisxmlapi.com"); !errors.Is(err, context.Canceled) {
+		t.Errorf("RawData() error = %v, want context.Canceled", err)
+	}
+	if state := breaker.State(); state != CircuitHalfOpen {
+		t.Errorf("State() got = %v, want %v", state, CircuitHalfOpen)
+	}
+
+	atomic.StoreInt32(&status, http.StatusOK)
+	if _, _, err := client.Data(ctx, "whoisxmlapi.com"); err != nil {
+		t.Fatalf("Data() error = %v", err)
+	}
+	if state := breaker.State(); state != CircuitClosed {
+		t.Errorf("State() got = %v, want %v", state, CircuitClosed)
+	}
+
+	wantChanges := []string{"closed>open", "open>half-open", "half-open>closed"}
+	if !reflect.DeepEqual(changes, wantChanges) {
+		t.Errorf("changes got = %v, want %v", changes, wantChanges)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			data, ok := m.Data.(metricdata.Sum[int64])
+			if !ok {
+				continue
+			}
+			for _, dp := range data.DataPoints {
+				key := m.Name
+				if v, ok := dp.Attributes.Value(attrState); ok {
+					key += "/" + v.AsString()
+				}
+				if v, ok := dp.Attributes.Value(attrErrorCode); ok {
+					key += "/" + v.AsString()
+				}
+				got[key] += dp.Value
+			}
+		}
+	}
+	want := map[string]int64{
+		"whoisapi.client.circuit.state_changes/open":      1,
+		"whoisapi.client.circuit.state_changes/half-open": 1,
+		"whoisapi.client.circuit.state_changes/closed":    1,
+		"whoisapi.client.circuit.rejected":                1,
+		"whoisapi.client.errors/circuit_open":             1,
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%v got = %v, want %v", k, got[k], v)
+		}
+	}
+}
@@ -2,11 +2,18 @@ package whoisapi
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"net/netip"
 	"net/url"
 	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -15,6 +22,23 @@ const (
 	mediaType      = "application/json"
 )
 
+// AuthMode defines how the API key is sent to the API
+type AuthMode int
+
+const (
+	// AuthModeQuery sends the API key as the apiKey query parameter of a GET request
+	AuthModeQuery AuthMode = iota
+
+	// AuthModeHeader sends the API key in the X-Authentication-Token header of a GET request
+	AuthModeHeader
+
+	// AuthModeBody sends the API key with all other parameters as the JSON body of a POST request
+	AuthModeBody
+)
+
+// authHeader is the header used by AuthModeHeader
+const authHeader = "X-Authentication-Token"
+
 // ClientParams is used to create Client. None of parameters are mandatory and
 // leaving this struct empty works just fine for most cases
 type ClientParams struct {
@@ -27,6 +51,53 @@ type ClientParams struct {
 
 	// Endpoint for 'historic whois' service
 	HistoricBaseURL *url.URL
+
+	// TracerProvider is used to create spans for lookups and HTTP requests
+	// If it's nil then no-op provider is used
+	TracerProvider trace.TracerProvider
+
+	// MeterProvider is used to record request, error and response size metrics
+	// If it's nil then no-op provider is used
+	MeterProvider metric.MeterProvider
+
+	// Logger is used to log every request with the apiKey redacted from the URL
+	// If it's nil then nothing is logged
+	Logger *slog.Logger
+
+	// LogBodies enables logging of response bodies, up to 64 KiB each
+	LogBodies bool
+
+	// AuthMode defines how the API key is sent
+	// AuthModeQuery is used by default for compatibility, other modes keep the key out of URLs and access logs
+	AuthMode AuthMode
+
+	// KeyPool picks the API key for every request instead of the key passed to NewClient
+	// Keys returning quota or auth errors are taken out of rotation and the request is retried with another key
+	KeyPool *KeyPool
+
+	// DomainNormalizer turns the name passed to Data and RawData into the name to look up
+	// If it's nil then NormalizeDomain is used, set it to KeepDomain to send names as is
+	DomainNormalizer func(name string) (string, error)
+
+	// SuffixList is used to find registrable domains for OptionRegistrableDomain
+	// If it's nil then the list embedded in the package is used
+	SuffixList *SuffixList
+
+	// StrictDecoding makes Data fail if any value of the response cannot be decoded
+	// By default such values are skipped and reported in Response.DecodeWarnings
+	StrictDecoding bool
+
+	// DetectSchemaDrift makes Data compare the keys of every response with the known model
+	// and the earlier responses and report new and missing keys in Response.SchemaDrift
+	DetectSchemaDrift bool
+
+	// DisableDeduplication makes every call send its own request.
+	// By default concurrent calls with the same name and options share one request and its Response
+	DisableDeduplication bool
+
+	// CircuitBreaker fails requests with ErrCircuitOpen without sending them after consecutive
+	// transport errors and 5xx responses. If it's nil then requests are always sent
+	CircuitBreaker *CircuitBreaker
 }
 
 // NewBasicClient creates Client with recommended parameters
@@ -47,6 +118,11 @@ func NewClient(apiKey string, params ClientParams) *Client {
 		}
 	}
 
+	normalize := params.DomainNormalizer
+	if normalize == nil {
+		normalize = NormalizeDomain
+	}
+
 	httpClient := http.DefaultClient
 	if params.HTTPClient != nil {
 		httpClient = params.HTTPClient
@@ -56,8 +132,25 @@ func NewClient(apiKey string, params ClientParams) *Client {
 		client:    httpClient,
 		userAgent: userAgent,
 		apiKey:    apiKey,
+		logger:    params.Logger,
+		logBodies: params.LogBodies,
+		authMode:  params.AuthMode,
+		keys:      params.KeyPool,
+		normalize: normalize,
+		suffixes:  params.SuffixList,
+		breaker:   params.CircuitBreaker,
+
+		strictDecoding: params.StrictDecoding,
+	}
+	if params.DetectSchemaDrift {
+		client.drift = NewSchemaTracker()
+	}
+	if !params.DisableDeduplication {
+		client.flights = newFlightGroup()
 	}
+	client.telemetry = newTelemetry(params.TracerProvider, params.MeterProvider, client.redact)
 
+	client.whoisBaseURL = whoisBaseURL
 	client.WhoisService = &whoisApiServiceOp{client: client, baseURL: whoisBaseURL}
 
 	return client
@@ -69,11 +162,38 @@ type Client struct {
 
 	userAgent string
 	apiKey    string
+	authMode  AuthMode
+	keys      *KeyPool
+	normalize func(name string) (string, error)
+	suffixes  *SuffixList
+	breaker   *CircuitBreaker
+
+	strictDecoding bool
+	drift          *SchemaTracker
+
+	flights   *flightGroup
+	telemetry *telemetry
+	logger    *slog.Logger
+	logBodies bool
+
+	whoisBaseURL *url.URL
 
 	// WhoisService is an interface for Whois API
 	WhoisService
 }
 
+// With returns the WhoisService applying the request options to every call,
+// e.g. client.With(OptionAPIKey(billingKey)).Data(ctx, name)
+func (c *Client) With(opts ...RequestOption) WhoisService {
+	return &whoisApiServiceOp{client: c, baseURL: c.whoisBaseURL, requestOpts: opts}
+}
+
+// PrefixData returns the Whois record of the most specific netblock containing the whole CIDR prefix,
+// see PrefixService
+func (c *Client) PrefixData(ctx context.Context, prefix netip.Prefix, opts ...Option) (*IPWhoisRecord, *Response, error) {
+	return whoisApiServiceOp{client: c, baseURL: c.whoisBaseURL}.PrefixData(ctx, prefix, opts...)
+}
+
 // NewRequest creates a basic API request
 func (c *Client) NewRequest(method string, u *url.URL, body io.Reader) (*http.Request, error) {
 
@@ -95,10 +215,49 @@ func (c *Client) NewRequest(method string, u *url.URL, body io.Reader) (*http.Re
 // Do sends the API request and returns the API response
 func (c *Client) Do(ctx context.Context, req *http.Request, v io.Writer) (response *http.Response, err error) {
 
+	if c.breaker != nil {
+		ticket, change, openErr := c.breaker.allow()
+		c.circuitChange(ctx, change)
+		if openErr != nil {
+			c.telemetry.circuitRejected(ctx)
+			return nil, fmt.Errorf("cannot execute request: %w", openErr)
+		}
+
+		defer func() {
+			c.circuitChange(ctx, c.breaker.record(ticket, circuitOutcome(ctx, response, err)))
+		}()
+	}
+
+	ctx, span, start := c.telemetry.startRequest(ctx, req.Method, req.URL)
+
+	var n int64
+	var body *capWriter
+	if c.logger != nil {
+		body = &capWriter{max: maxLoggedBody}
+		v = io.MultiWriter(v, body)
+	}
+
+	defer func() {
+		status := 0
+		if response != nil {
+			status = response.StatusCode
+		}
+		c.telemetry.endRequest(ctx, span, start, status, n, err)
+
+		if c.logger != nil {
+			c.logRequest(ctx, req, response, start, n, body.buf.Bytes(), err)
+		}
+	}()
+
 	req = req.WithContext(ctx)
 
 	resp, err := c.client.Do(req)
 	if err != nil {
+		// the URL may contain a per-call API key unknown to redact
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			uerr.URL = RedactURL(req.URL).String()
+		}
 		return nil, fmt.Errorf("cannot execute request: %w", err)
 	}
 
@@ -108,7 +267,7 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v io.Writer) (respon
 		}
 	}()
 
-	_, err = io.Copy(v, resp.Body)
+	n, err = io.Copy(v, resp.Body)
 	if err != nil {
 		return resp, fmt.Errorf("cannot read response: %w", err)
 	}
@@ -116,6 +275,37 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v io.Writer) (respon
 	return resp, err
 }
 
+// circuitChange reports the state change of the circuit breaker, if any
+func (c *Client) circuitChange(ctx context.Context, change *circuitChange) {
+	c.telemetry.circuitChange(ctx, change)
+	c.breaker.notify(change)
+}
+
+// circuitOutcome classifies the result of the request for the circuit breaker.
+// Errors caused by the cancellation of the caller's context are ignored
+func circuitOutcome(ctx context.Context, resp *http.Response, err error) circuitResult {
+	switch {
+	case err != nil && ctx.Err() != nil:
+		return circuitIgnored
+	case err != nil:
+		return circuitFailure
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return circuitFailure
+	}
+	return circuitSuccess
+}
+
+// redact replaces the API keys in the string
+func (c *Client) redact(s string) string {
+	if c.keys != nil {
+		s = c.keys.redact(s)
+	}
+	if c.apiKey == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, c.apiKey, redactedValue)
+}
+
 // ErrorResponse is returned when the response status code is not 2xx
 type ErrorResponse struct {
 	Response *http.Response
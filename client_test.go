@@ -2,9 +2,11 @@ package whoisapi
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"reflect"
 	"strconv"
 	"testing"
 )
@@ -358,3 +360,86 @@ func TestWhoisAPIRawData(t *testing.T) {
 		})
 	}
 }
+
+// TestAuthModes tests the ways the API key is sent
+func TestAuthModes(t *testing.T) {
+
+	type captured struct {
+		method string
+		query  url.Values
+		header string
+		body   map[string]interface{}
+	}
+
+	var got captured
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		got = captured{
+			method: req.Method,
+			query:  req.URL.Query(),
+			header: req.Header.Get("X-Authentication-Token"),
+		}
+		if req.Method == http.MethodPost {
+			if err := json.NewDecoder(req.Body).Decode(&got.body); err != nil {
+				panic(err)
+			}
+		}
+		_, _ = w.Write([]byte(`{"WhoisRecord": {"domainName": "whoisxmlapi.com"}}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		mode   AuthMode
+		method string
+		query  url.Values
+		header string
+		body   map[string]interface{}
+	}{
+		{
+			name:   "query",
+			mode:   AuthModeQuery,
+			method: http.MethodGet,
+			query:  url.Values{"apiKey": {apiKey}, "domainName": {"whoisxmlapi.com"}, "da": {"2"}, "outputFormat": {"JSON"}},
+		},
+		{
+			name:   "header",
+			mode:   AuthModeHeader,
+			method: http.MethodGet,
+			query:  url.Values{"domainName": {"whoisxmlapi.com"}, "da": {"2"}, "outputFormat": {"JSON"}},
+			header: apiKey,
+		},
+		{
+			name:   "body",
+			mode:   AuthModeBody,
+			method: http.MethodPost,
+			query:  url.Values{},
+			body: map[string]interface{}{
+				"apiKey":       apiKey,
+				"domainName":   "whoisxmlapi.com",
+				"da":           float64(2),
+				"outputFormat": "JSON",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiURL, _ := url.Parse(server.URL)
+			client := NewClient(apiKey, ClientParams{
+				HTTPClient:   server.Client(),
+				WhoisBaseURL: apiURL,
+				AuthMode:     tt.mode,
+			})
+
+			rec, _, err := client.Data(context.Background(), "whoisxmlapi.com", OptionDA(2))
+			if err != nil || rec == nil {
+				t.Fatalf("Data() got = %v, %v", rec, err)
+			}
+
+			want := captured{method: tt.method, query: tt.query, header: tt.header, body: tt.body}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("request got  = %v", got)
+				t.Errorf("request want = %v", want)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// Command whoisapi-proxy is the caching reverse proxy for Whois API.
+//
+// It serves the /whois?domainName= endpoint compatible with the upstream one, so internal
+// services can use it without knowing the vendor API key. Callers authenticate with their own
+// tokens sent as the apiKey parameter, the X-Authentication-Token header or
+// the "Authorization: Bearer" header.
+// The real API key is read from the WHOISXMLAPI_KEY environment variable.
+//
+// Usage:
+//
+//	WHOISXMLAPI_KEY=at_... whoisapi-proxy -callers callers.json -listen :8080
+//
+// The callers file is a JSON array:
+//
+//	[{"name": "billing", "token": "secret", "quota": 1000}]
+//
+// Other endpoints are /healthz and /metrics (Prometheus text format).
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	whoisapi "github.com/whois-api-llc/whois-api-go"
+)
+
+func main() {
+	listen := flag.String("listen", ":8080", "address to listen on")
+	callersFile := flag.String("callers", "callers.json", "JSON file with caller names, tokens and quotas")
+	upstream := flag.String("upstream", "", "upstream Whois API URL, the library default if empty")
+	quotaPeriod := flag.Duration("quota-period", 24*time.Hour, "period of caller quotas")
+	cacheTTL := flag.Duration("cache-ttl", time.Hour, "how long successful responses are cached, 0 disables caching")
+	cacheSize := flag.Int("cache-size", 10000, "maximum number of cached responses")
+	rateLimit := flag.Float64("rate-limit", 20, "maximum upstream requests per second, 0 for unlimited")
+	retries := flag.Int("retries", 2, "retries of upstream requests failed with transport errors or 5xx")
+	timeout := flag.Duration("timeout", 30*time.Second, "upstream request timeout")
+	authMode := flag.String("auth-mode", "query", "how the API key is sent upstream: query, header or body")
+	flag.Parse()
+
+	apiKey := os.Getenv("WHOISXMLAPI_KEY")
+	if apiKey == "" {
+		log.Fatal("WHOISXMLAPI_KEY is not set")
+	}
+
+	callers, err := loadCallers(*callersFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	params := whoisapi.ClientParams{
+		HTTPClient: &http.Client{Timeout: *timeout},
+	}
+
+	switch *authMode {
+	case "query":
+		params.AuthMode = whoisapi.AuthModeQuery
+	case "header":
+		params.AuthMode = whoisapi.AuthModeHeader
+	case "body":
+		params.AuthMode = whoisapi.AuthModeBody
+	default:
+		log.Fatalf("unknown auth mode %q", *authMode)
+	}
+	if *upstream != "" {
+		params.WhoisBaseURL, err = url.Parse(*upstream)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	p := newProxy(proxyParams{
+		Service:      whoisapi.NewClient(apiKey, params).WhoisService,
+		Callers:      callers,
+		QuotaPeriod:  *quotaPeriod,
+		CacheTTL:     *cacheTTL,
+		CacheSize:    *cacheSize,
+		RateLimit:    *rateLimit,
+		Retries:      *retries,
+		RetryBackoff: 500 * time.Millisecond,
+	})
+
+	server := &http.Server{
+		Addr:              *listen,
+		Handler:           p.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), *timeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	log.Printf("listening on %s with %d callers", *listen, len(callers))
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+}
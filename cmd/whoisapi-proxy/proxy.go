@@ -0,0 +1,484 @@
+package main
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	whoisapi "github.com/whois-api-llc/whois-api-go"
+)
+
+// Caller is the internal service allowed to use the proxy
+type Caller struct {
+	// Name identifies the caller in metrics
+	Name string `json:"name"`
+
+	// Token is the secret the caller sends instead of the vendor API key
+	Token string `json:"token"`
+
+	// Quota is the maximum number of upstream requests per quota period, unlimited if zero
+	Quota int `json:"quota"`
+}
+
+// loadCallers reads callers from the JSON file
+func loadCallers(path string) ([]Caller, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var callers []Caller
+	if err := json.Unmarshal(b, &callers); err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %w", path, err)
+	}
+
+	for i, c := range callers {
+		if c.Name == "" || c.Token == "" {
+			return nil, fmt.Errorf("caller #%d: name and token cannot be empty", i)
+		}
+	}
+
+	return callers, nil
+}
+
+// proxyParams is used to create proxy
+type proxyParams struct {
+	// Service is the upstream Whois API service
+	Service whoisapi.WhoisService
+
+	// Callers are the allowed internal callers
+	Callers []Caller
+
+	// QuotaPeriod is the period of caller quotas
+	QuotaPeriod time.Duration
+
+	// CacheTTL is how long successful responses are cached, caching is disabled if zero
+	CacheTTL time.Duration
+
+	// CacheSize is the maximum number of cached responses
+	CacheSize int
+
+	// RateLimit is the maximum number of upstream requests per second, unlimited if zero
+	RateLimit float64
+
+	// Retries is the number of retries of failed upstream requests
+	Retries int
+
+	// RetryBackoff is the delay before the first retry, doubled for every following one
+	RetryBackoff time.Duration
+
+	// Now is the source of time, time.Now is used if nil
+	Now func() time.Time
+}
+
+// proxy serves the Whois API compatible endpoint using the real API key
+type proxy struct {
+	service      whoisapi.WhoisService
+	callers      []Caller
+	retries      int
+	retryBackoff time.Duration
+	now          func() time.Time
+
+	cache   *cache
+	limiter *limiter
+	quotas  *quotas
+	metrics *metrics
+}
+
+// newProxy creates the proxy with specified parameters
+func newProxy(params proxyParams) *proxy {
+	now := params.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	return &proxy{
+		service:      params.Service,
+		callers:      params.Callers,
+		retries:      params.Retries,
+		retryBackoff: params.RetryBackoff,
+		now:          now,
+		cache:        newCache(params.CacheSize, params.CacheTTL, now),
+		limiter:      newLimiter(params.RateLimit, now),
+		quotas:       newQuotas(params.QuotaPeriod, now),
+		metrics:      newMetrics(),
+	}
+}
+
+// handler returns the HTTP handler with all endpoints
+func (p *proxy) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/whois", p.serveWhois)
+	mux.HandleFunc("/healthz", p.serveHealth)
+	mux.HandleFunc("/metrics", p.metrics.serve)
+	return mux
+}
+
+// writeError writes the error in the Whois API format
+func writeError(w http.ResponseWriter, status int, code, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(struct {
+		ErrorMessage whoisapi.ErrorMessage `json:"ErrorMessage"`
+	}{whoisapi.ErrorMessage{ErrorCode: code, Message: msg}})
+}
+
+// authenticate returns the caller by the bearer token, the X-Authentication-Token header or
+// the apiKey parameter, so the library clients can use the proxy by setting WhoisBaseURL
+// and the internal token
+func (p *proxy) authenticate(req *http.Request) (*Caller, bool) {
+	token := req.URL.Query().Get("apiKey")
+	if header := req.Header.Get("X-Authentication-Token"); header != "" {
+		token = header
+	}
+	if auth := req.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		token = strings.TrimPrefix(auth, "Bearer ")
+	}
+	if token == "" {
+		return nil, false
+	}
+
+	for i := range p.callers {
+		if subtle.ConstantTimeCompare([]byte(p.callers[i].Token), []byte(token)) == 1 {
+			return &p.callers[i], true
+		}
+	}
+	return nil, false
+}
+
+// serveWhois serves the /whois?domainName= endpoint
+func (p *proxy) serveWhois(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "PROXY_00", "method not allowed")
+		return
+	}
+
+	caller, ok := p.authenticate(req)
+	if !ok {
+		p.metrics.inc("requests_total", "caller", "", "result", "unauthorized")
+		writeError(w, http.StatusUnauthorized, "PROXY_01", "invalid token")
+		return
+	}
+
+	query := req.URL.Query()
+	name := strings.TrimSpace(query.Get("domainName"))
+	if name == "" {
+		p.metrics.inc("requests_total", "caller", caller.Name, "result", "bad_request")
+		writeError(w, http.StatusBadRequest, "PROXY_02", `"domainName" cannot be empty`)
+		return
+	}
+
+	query.Del("apiKey")
+	query.Del("domainName")
+	key := strings.ToLower(name) + "?" + query.Encode()
+
+	if entry, ok := p.cache.get(key); ok {
+		p.metrics.inc("cache_hits_total")
+		p.metrics.inc("requests_total", "caller", caller.Name, "result", "hit")
+		writeEntry(w, entry, "HIT")
+		return
+	}
+	p.metrics.inc("cache_misses_total")
+
+	if !p.quotas.take(caller) {
+		p.metrics.inc("requests_total", "caller", caller.Name, "result", "quota_exceeded")
+		writeError(w, http.StatusTooManyRequests, "PROXY_03", "quota exceeded")
+		return
+	}
+
+	entry, err := p.fetch(req.Context(), name, query)
+	if err != nil {
+		// transport errors contain the upstream URL with the API key, so they are not passed to callers
+		p.metrics.inc("requests_total", "caller", caller.Name, "result", "upstream_error")
+		writeError(w, http.StatusBadGateway, "PROXY_04", "upstream request failed")
+		return
+	}
+
+	if entry.status == http.StatusOK && !bytes.Contains(entry.body, []byte("ErrorMessage")) {
+		p.cache.set(key, entry)
+	}
+
+	p.metrics.inc("requests_total", "caller", caller.Name, "result", "miss")
+	writeEntry(w, entry, "MISS")
+}
+
+// serveHealth serves the /healthz endpoint
+func (p *proxy) serveHealth(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain")
+	_, _ = io.WriteString(w, "ok\n")
+}
+
+// writeEntry writes the upstream response
+func writeEntry(w http.ResponseWriter, entry *cacheEntry, cacheStatus string) {
+	if entry.contentType != "" {
+		w.Header().Set("Content-Type", entry.contentType)
+	}
+	w.Header().Set("X-Cache", cacheStatus)
+	w.WriteHeader(entry.status)
+	_, _ = w.Write(entry.body)
+}
+
+// fetch requests the upstream with rate limiting and retries
+func (p *proxy) fetch(ctx context.Context, name string, query url.Values) (*cacheEntry, error) {
+	opts := make([]whoisapi.Option, 0, len(query))
+	for k, vv := range query {
+		k, vv := k, vv
+		opts = append(opts, func(v url.Values) {
+			v.Del(k)
+			for _, x := range vv {
+				v.Add(k, x)
+			}
+		})
+	}
+
+	backoff := p.retryBackoff
+	var lastErr error
+	for attempt := 0; attempt <= p.retries; attempt++ {
+		if attempt > 0 {
+			p.metrics.inc("upstream_retries_total")
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		if err := p.limiter.wait(ctx); err != nil {
+			return nil, err
+		}
+
+		p.metrics.inc("upstream_requests_total")
+		resp, err := p.service.RawData(ctx, name, opts...)
+
+		var respErr whoisapi.ErrorResponse
+		switch {
+		case err == nil, errors.As(err, &respErr) && respErr.Response.StatusCode < 500:
+			return &cacheEntry{
+				status:      resp.StatusCode,
+				contentType: resp.Header.Get("Content-Type"),
+				body:        resp.Body,
+			}, nil
+		case errors.As(err, &respErr):
+			p.metrics.inc("upstream_errors_total", "status", "5xx")
+		default:
+			p.metrics.inc("upstream_errors_total", "status", "transport")
+		}
+		lastErr = err
+	}
+
+	return nil, lastErr
+}
+
+// cacheEntry is the cached upstream response
+type cacheEntry struct {
+	key         string
+	status      int
+	contentType string
+	body        []byte
+	expires     time.Time
+}
+
+// cache is the LRU cache of upstream responses with expiration
+type cache struct {
+	mu    sync.Mutex
+	size  int
+	ttl   time.Duration
+	now   func() time.Time
+	lru   *list.List
+	items map[string]*list.Element
+}
+
+// newCache creates the cache, it is disabled if the size or ttl is not positive
+func newCache(size int, ttl time.Duration, now func() time.Time) *cache {
+	return &cache{
+		size:  size,
+		ttl:   ttl,
+		now:   now,
+		lru:   list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+// get returns the fresh entry by key
+func (c *cache) get(key string) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := el.Value.(*cacheEntry)
+	if !c.now().Before(entry.expires) {
+		c.lru.Remove(el)
+		delete(c.items, key)
+		return nil, false
+	}
+
+	c.lru.MoveToFront(el)
+	return entry, true
+}
+
+// set adds the entry and evicts the least recently used ones
+func (c *cache) set(key string, entry *cacheEntry) {
+	if c.size <= 0 || c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry.key = key
+	entry.expires = c.now().Add(c.ttl)
+
+	if el, ok := c.items[key]; ok {
+		el.Value = entry
+		c.lru.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.size {
+		el := c.lru.Back()
+		c.lru.Remove(el)
+		delete(c.items, el.Value.(*cacheEntry).key)
+	}
+}
+
+// limiter is the token bucket limiting the upstream request rate
+type limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// newLimiter creates the limiter, it is disabled if the rate is not positive
+func newLimiter(rate float64, now func() time.Time) *limiter {
+	return &limiter{rate: rate, tokens: 1, last: now(), now: now}
+}
+
+// wait blocks until the request is allowed or the context is done
+func (l *limiter) wait(ctx context.Context) error {
+	if l.rate <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := l.now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > 1 {
+		l.tokens = 1
+	}
+	l.last = now
+	l.tokens--
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(delay):
+		return nil
+	}
+}
+
+// quotas counts upstream requests of callers in fixed periods
+type quotas struct {
+	mu     sync.Mutex
+	period time.Duration
+	now    func() time.Time
+	start  time.Time
+	used   map[string]int
+}
+
+// newQuotas creates quotas with the period
+func newQuotas(period time.Duration, now func() time.Time) *quotas {
+	return &quotas{period: period, now: now, start: now(), used: make(map[string]int)}
+}
+
+// take reports whether the caller has quota left and uses one request of it
+func (q *quotas) take(c *Caller) bool {
+	if c.Quota <= 0 {
+		return true
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.period > 0 && !q.now().Before(q.start.Add(q.period)) {
+		q.start = q.now().Truncate(q.period)
+		q.used = make(map[string]int)
+	}
+
+	if q.used[c.Name] >= c.Quota {
+		return false
+	}
+	q.used[c.Name]++
+	return true
+}
+
+// metrics are counters exposed in the Prometheus text format
+type metrics struct {
+	mu       sync.Mutex
+	counters map[string]float64
+}
+
+// newMetrics creates metrics
+func newMetrics() *metrics {
+	return &metrics{counters: make(map[string]float64)}
+}
+
+// inc increments the counter with the label name and value pairs
+func (m *metrics) inc(name string, labels ...string) {
+	key := "whoisapi_proxy_" + name
+	if len(labels) > 0 {
+		pairs := make([]string, 0, len(labels)/2)
+		for i := 0; i+1 < len(labels); i += 2 {
+			pairs = append(pairs, labels[i]+`="`+labels[i+1]+`"`)
+		}
+		key += "{" + strings.Join(pairs, ",") + "}"
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counters[key]++
+}
+
+// serve writes the counters
+func (m *metrics) serve(w http.ResponseWriter, _ *http.Request) {
+	m.mu.Lock()
+	keys := make([]string, 0, len(m.counters))
+	for k := range m.counters {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b bytes.Buffer
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s %v\n", k, m.counters[k])
+	}
+	m.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	_, _ = w.Write(b.Bytes())
+}
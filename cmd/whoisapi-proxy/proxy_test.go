@@ -0,0 +1,313 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	whoisapi "github.com/whois-api-llc/whois-api-go"
+)
+
+const (
+	realAPIKey = "at_LoremIpsumDolorSitAmetConsect"
+	okResponse = `{"WhoisRecord": {"domainName": "whoisxmlapi.com"}}`
+)
+
+// upstreamServer is the sample Whois API server that fails the first failures requests with the status
+func upstreamServer(t *testing.T, failures int, status int) (*httptest.Server, *[]url.Values) {
+	var mu sync.Mutex
+	var queries []url.Values
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		queries = append(queries, req.URL.Query())
+		if len(queries) <= failures {
+			w.WriteHeader(status)
+			_, _ = io.WriteString(w, `{"ErrorMessage": {"errorCode": "WHOIS_00", "msg": "failure"}}`)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, okResponse)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &queries
+}
+
+// newTestProxy returns the proxy server using the upstream server
+func newTestProxy(t *testing.T, upstream *httptest.Server) *httptest.Server {
+	apiURL, err := url.Parse(upstream.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := whoisapi.NewClient(realAPIKey, whoisapi.ClientParams{
+		HTTPClient:   upstream.Client(),
+		WhoisBaseURL: apiURL,
+	})
+
+	p := newProxy(proxyParams{
+		Service: client.WhoisService,
+		Callers: []Caller{
+			{Name: "billing", Token: "billing-token", Quota: 2},
+			{Name: "security", Token: "security-token"},
+		},
+		QuotaPeriod:  time.Hour,
+		CacheTTL:     time.Minute,
+		CacheSize:    10,
+		Retries:      2,
+		RetryBackoff: time.Millisecond,
+	})
+
+	server := httptest.NewServer(p.handler())
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// get makes the request to the proxy
+func get(t *testing.T, server *httptest.Server, path string, header http.Header) (int, string, http.Header) {
+	req, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return resp.StatusCode, string(body), resp.Header
+}
+
+// TestProxyAuth tests the caller authentication
+func TestProxyAuth(t *testing.T) {
+	upstream, queries := upstreamServer(t, 0, 0)
+	server := newTestProxy(t, upstream)
+
+	tests := []struct {
+		name   string
+		path   string
+		header http.Header
+		status int
+	}{
+		{
+			name:   "no token",
+			path:   "/whois?domainName=whoisxmlapi.com",
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "real api key is not accepted",
+			path:   "/whois?domainName=whoisxmlapi.com&apiKey=" + realAPIKey,
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "empty domain name",
+			path:   "/whois?apiKey=security-token",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "token as apiKey",
+			path:   "/whois?domainName=whoisxmlapi.com&apiKey=security-token",
+			status: http.StatusOK,
+		},
+		{
+			name:   "authentication header",
+			path:   "/whois?domainName=example.org",
+			header: http.Header{"X-Authentication-Token": {"security-token"}},
+			status: http.StatusOK,
+		},
+		{
+			name:   "bearer token",
+			path:   "/whois?domainName=example.com",
+			header: http.Header{"Authorization": {"Bearer security-token"}},
+			status: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body, _ := get(t, server, tt.path, tt.header)
+			if status != tt.status {
+				t.Errorf("status got = %v, want %v (%s)", status, tt.status, body)
+			}
+		})
+	}
+
+	if len(*queries) != 3 {
+		t.Fatalf("upstream calls got = %v, want 3", len(*queries))
+	}
+	for _, q := range *queries {
+		if q.Get("apiKey") != realAPIKey {
+			t.Errorf("upstream apiKey got = %v, want the real key", q.Get("apiKey"))
+		}
+	}
+}
+
+// TestProxyCacheAndQuota tests the shared cache and the caller quotas
+func TestProxyCacheAndQuota(t *testing.T) {
+	upstream, queries := upstreamServer(t, 0, 0)
+	server := newTestProxy(t, upstream)
+
+	auth := http.Header{"Authorization": {"Bearer billing-token"}}
+
+	steps := []struct {
+		path   string
+		status int
+		cache  string
+	}{
+		{"/whois?domainName=whoisxmlapi.com&da=2", http.StatusOK, "MISS"},
+		{"/whois?domainName=WhoisXMLAPI.com&da=2", http.StatusOK, "HIT"},
+		{"/whois?domainName=whoisxmlapi.com&da=1", http.StatusOK, "MISS"},
+		{"/whois?domainName=example.com", http.StatusTooManyRequests, ""},
+		{"/whois?domainName=whoisxmlapi.com&da=1", http.StatusOK, "HIT"},
+	}
+	for i, step := range steps {
+		status, body, header := get(t, server, step.path, auth)
+		if status != step.status {
+			t.Errorf("step %d: status got = %v, want %v", i, status, step.status)
+		}
+		if header.Get("X-Cache") != step.cache {
+			t.Errorf("step %d: X-Cache got = %v, want %v", i, header.Get("X-Cache"), step.cache)
+		}
+		if status == http.StatusOK && body != okResponse {
+			t.Errorf("step %d: body got = %v", i, body)
+		}
+	}
+
+	if len(*queries) != 2 {
+		t.Fatalf("upstream calls got = %v, want 2", len(*queries))
+	}
+	if (*queries)[0].Get("da") != "2" || (*queries)[0].Get("domainName") != "whoisxmlapi.com" {
+		t.Errorf("upstream query got = %v", (*queries)[0])
+	}
+
+	_, metrics, _ := get(t, server, "/metrics", nil)
+	for _, want := range []string{
+		`whoisapi_proxy_cache_hits_total 2`,
+		`whoisapi_proxy_requests_total{caller="billing",result="quota_exceeded"} 1`,
+		`whoisapi_proxy_upstream_requests_total 2`,
+	} {
+		if !strings.Contains(metrics, want) {
+			t.Errorf("metrics do not contain %q:\n%s", want, metrics)
+		}
+	}
+}
+
+// TestProxyUpstreamErrors tests retries and pass-through of upstream errors
+func TestProxyUpstreamErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		failures  int
+		status    int
+		want      int
+		wantCalls int
+	}{
+		{
+			name:      "retried 5xx",
+			failures:  2,
+			status:    http.StatusServiceUnavailable,
+			want:      http.StatusOK,
+			wantCalls: 3,
+		},
+		{
+			name:      "exhausted retries",
+			failures:  3,
+			status:    http.StatusInternalServerError,
+			want:      http.StatusBadGateway,
+			wantCalls: 3,
+		},
+		{
+			name:      "4xx is passed through",
+			failures:  1,
+			status:    http.StatusBadRequest,
+			want:      http.StatusBadRequest,
+			wantCalls: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upstream, queries := upstreamServer(t, tt.failures, tt.status)
+			server := newTestProxy(t, upstream)
+
+			status, body, _ := get(t, server, "/whois?domainName=whoisxmlapi.com&apiKey=security-token", nil)
+			if status != tt.want {
+				t.Errorf("status got = %v, want %v (%s)", status, tt.want, body)
+			}
+			if len(*queries) != tt.wantCalls {
+				t.Errorf("upstream calls got = %v, want %v", len(*queries), tt.wantCalls)
+			}
+			if strings.Contains(body, realAPIKey) {
+				t.Errorf("body leaks the api key: %s", body)
+			}
+		})
+	}
+}
+
+// TestHealth tests the health endpoint
+func TestHealth(t *testing.T) {
+	upstream, _ := upstreamServer(t, 0, 0)
+	server := newTestProxy(t, upstream)
+
+	status, body, _ := get(t, server, "/healthz", nil)
+	if status != http.StatusOK || body != "ok\n" {
+		t.Errorf("healthz got = %v %q", status, body)
+	}
+}
+
+// TestLimiter tests the upstream rate limiter
+func TestLimiter(t *testing.T) {
+	l := newLimiter(100, time.Now)
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		if err := l.wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if d := time.Since(start); d < 40*time.Millisecond {
+		t.Errorf("6 requests at 100 rps took %v, expected at least 50ms", d)
+	}
+}
+
+// TestLoadCallers tests the callers file parsing
+func TestLoadCallers(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "valid.json")
+	if err := os.WriteFile(valid, []byte(`[{"name": "billing", "token": "t", "quota": 5}]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalid, []byte(`[{"name": "billing"}]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	callers, err := loadCallers(valid)
+	if err != nil || len(callers) != 1 || callers[0].Quota != 5 {
+		t.Errorf("loadCallers() got = %v, %v", callers, err)
+	}
+
+	if _, err := loadCallers(invalid); err == nil || err.Error() != "caller #0: name and token cannot be empty" {
+		t.Errorf("loadCallers() error = %v", err)
+	}
+}
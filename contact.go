@@ -0,0 +1,367 @@
+package whoisapi
+
+import (
+	"maps"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// defaultPrivacyServices are lowercase fragments of names, organizations and email domains of known
+// privacy and proxy services
+var defaultPrivacyServices = []string{
+	"domains by proxy",
+	"domainsbyproxy.com",
+	"whoisguard",
+	"withheld for privacy",
+	"withheldforprivacy.com",
+	"contact privacy inc",
+	"contactprivacy.com",
+	"privacyguardian.org",
+	"perfect privacy",
+	"perfectprivacy.com",
+	"whoisprivacyprotect",
+	"whois privacy protection",
+	"whoisprivacycorp",
+	"privacy protect, llc",
+	"privacyprotect.org",
+	"domain protection services",
+	"identity protection service",
+	"super privacy service",
+	"private by design",
+	"data protected limited",
+	"dynadot privacy",
+	"whois agent",
+	"proxy protection llc",
+	"njalla",
+	"anonymize.com",
+	"whoisproxy",
+	"whois protection service",
+}
+
+// defaultRedactionPhrases are lowercase fragments of contact fields masked by registrars and registries
+var defaultRedactionPhrases = []string{
+	"redacted",
+	"not disclosed",
+	"non-public data",
+	"gdpr masked",
+	"statutory masking",
+	"not available from registry",
+	"please query the rdds service",
+	"whois-contact",
+	"request email form",
+	"hidden upon user request",
+}
+
+// MaskDetector detects redacted contacts and privacy services by fragments of the contact fields.
+// It is safe for concurrent use
+type MaskDetector struct {
+	mu               sync.RWMutex
+	privacyServices  []string
+	redactionPhrases []string
+}
+
+// NewMaskDetector creates MaskDetector with the built-in privacy services and redaction phrases
+func NewMaskDetector() *MaskDetector {
+	return &MaskDetector{
+		privacyServices:  append([]string(nil), defaultPrivacyServices...),
+		redactionPhrases: append([]string(nil), defaultRedactionPhrases...),
+	}
+}
+
+// defaultMaskDetector is used by the Contact and WhoisRecord methods
+var defaultMaskDetector = NewMaskDetector()
+
+// AddPrivacyServices adds fragments of names, organizations, email domains or streets of privacy services
+func (d *MaskDetector) AddPrivacyServices(fragments ...string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.privacyServices = appendLower(d.privacyServices, fragments)
+}
+
+// AddRedactionPhrases adds fragments of masked names, organizations or emails
+func (d *MaskDetector) AddRedactionPhrases(fragments ...string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.redactionPhrases = appendLower(d.redactionPhrases, fragments)
+}
+
+// appendLower appends the non-empty fragments in lowercase
+func appendLower(list, fragments []string) []string {
+	for _, f := range fragments {
+		if f = strings.ToLower(strings.TrimSpace(f)); f != "" {
+			list = append(list, f)
+		}
+	}
+	return list
+}
+
+// containsAny reports whether the lowercase s contains any of the fragments
+func containsAny(s string, fragments []string) bool {
+	if s == "" {
+		return false
+	}
+	s = strings.ToLower(s)
+	for _, f := range fragments {
+		if strings.Contains(s, f) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEmpty reports whether all known fields of the contact are empty, Extra is not checked
+func (c Contact) IsEmpty() bool {
+	c.Extra = nil
+	return reflect.DeepEqual(c, Contact{})
+}
+
+// IsRedacted reports whether the identity of the contact is masked: its name, organization or email
+// contains one of the redaction phrases, or all of them are empty while other fields are not
+func (d *MaskDetector) IsRedacted(c Contact) bool {
+	if c.IsEmpty() {
+		return false
+	}
+	if c.Name == "" && c.Organization == "" && c.Email == "" {
+		return true
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return containsAny(c.Name, d.redactionPhrases) ||
+		containsAny(c.Organization, d.redactionPhrases) ||
+		containsAny(c.Email, d.redactionPhrases)
+}
+
+// IsPrivacyProxy reports whether the contact is one of the privacy services
+func (d *MaskDetector) IsPrivacyProxy(c Contact) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return containsAny(c.Name, d.privacyServices) ||
+		containsAny(c.Organization, d.privacyServices) ||
+		containsAny(c.Email, d.privacyServices) ||
+		containsAny(c.Street1, d.privacyServices)
+}
+
+// isMasked reports whether the contact doesn't identify the owner
+func (d *MaskDetector) isMasked(c Contact) bool {
+	return c.IsEmpty() || d.IsRedacted(c) || d.IsPrivacyProxy(c)
+}
+
+// EffectiveRegistrant returns the registrant of the record that is neither redacted nor a privacy service.
+// The registrar's registrant is checked first, then the registry's one and the registrants of sub-records.
+// The proxy data returned with OptionCheckProxyData is the privacy service, not the owner, it only marks
+// the registrar's registrant as masked.
+// If every registrant is masked then the registrar's registrant and false are returned
+func (d *MaskDetector) EffectiveRegistrant(r *WhoisRecord) (Contact, bool) {
+	var candidates []Contact
+	if r.PrivateWhoisProxy == nil || r.PrivateWhoisProxy.IsEmpty() {
+		candidates = append(candidates, r.Registrant)
+	}
+	candidates = append(candidates, r.RegistryData.Registrant)
+	for i := range r.SubRecords {
+		candidates = append(candidates, r.SubRecords[i].Registrant, r.SubRecords[i].RegistryData.Registrant)
+	}
+
+	for _, c := range candidates {
+		if !d.isMasked(c) {
+			return c, true
+		}
+	}
+
+	return r.Registrant, false
+}
+
+// IsRedacted reports whether the identity of the contact is masked according to the built-in phrases,
+// see MaskDetector.IsRedacted
+func (c Contact) IsRedacted() bool {
+	return defaultMaskDetector.IsRedacted(c)
+}
+
+// IsPrivacyProxy reports whether the contact is one of the built-in privacy services,
+// see MaskDetector.IsPrivacyProxy
+func (c Contact) IsPrivacyProxy() bool {
+	return defaultMaskDetector.IsPrivacyProxy(c)
+}
+
+// EffectiveRegistrant returns the registrant that is neither redacted nor a privacy service
+// according to the built-in lists, see MaskDetector.EffectiveRegistrant
+func (r *WhoisRecord) EffectiveRegistrant() (Contact, bool) {
+	return defaultMaskDetector.EffectiveRegistrant(r)
+}
+
+// country is the ISO 3166-1 country with its calling code
+type country struct {
+	alpha2 string
+	alpha3 string
+	name   string
+	dial   string
+}
+
+// lookupCountry finds the country by the alpha-2 or alpha-3 code, the name or a common alias
+func lookupCountry(s string) (country, bool) {
+	key := strings.ToLower(strings.Join(strings.FieldsFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(".,'()", r)
+	}), " "))
+	key = strings.TrimPrefix(key, "the ")
+	if key == "" {
+		return country{}, false
+	}
+
+	code := countryNames[key]
+	for _, c := range countries {
+		if c.alpha2 == code || strings.EqualFold(c.alpha2, key) || strings.EqualFold(c.alpha3, key) {
+			return c, true
+		}
+	}
+	return country{}, false
+}
+
+// Normalize returns the contact normalized for comparison across records:
+//   - text fields are trimmed and inner whitespace is collapsed
+//   - Street1–Street4 are joined with ", " into Street1
+//   - Country is the ISO 3166-1 name and CountryCode is the alpha-2 code if the country is recognized
+//   - Email is lowercased if it's a valid address
+//   - Telephone and Fax are in the E.164 format with the extension appended as ";ext=" if they are valid numbers.
+//     Numbers without the country code get the calling code of the country instead of its trunk prefix
+//
+// Emails and numbers that cannot be normalized are kept with their extensions, Extra is copied
+func (c Contact) Normalize() Contact {
+	n := Contact{
+		Name:         collapseSpaces(c.Name),
+		Organization: collapseSpaces(c.Organization),
+		City:         collapseSpaces(c.City),
+		State:        collapseSpaces(c.State),
+		PostalCode:   collapseSpaces(c.PostalCode),
+		Country:      collapseSpaces(c.Country),
+		CountryCode:  collapseSpaces(c.CountryCode),
+		RawText:      c.RawText,
+		Unparsable:   c.Unparsable,
+		Extra:        maps.Clone(c.Extra),
+	}
+
+	var street []string
+	for _, s := range []string{c.Street1, c.Street2, c.Street3, c.Street4} {
+		if s = collapseSpaces(s); s != "" {
+			street = append(street, s)
+		}
+	}
+	n.Street1 = strings.Join(street, ", ")
+
+	cntry, ok := lookupCountry(n.CountryCode)
+	if !ok {
+		cntry, ok = lookupCountry(n.Country)
+	}
+	if ok {
+		n.Country = cntry.name
+		n.CountryCode = cntry.alpha2
+	}
+
+	n.Email = collapseSpaces(c.Email)
+	if email := normalizeEmail(c.Email); email != "" {
+		n.Email = email
+	}
+
+	n.Telephone, n.TelephoneExt = collapseSpaces(c.Telephone), collapseSpaces(c.TelephoneExt)
+	if phone := normalizePhone(c.Telephone, c.TelephoneExt, cntry); phone != "" {
+		n.Telephone, n.TelephoneExt = phone, ""
+	}
+	n.Fax, n.FaxExt = collapseSpaces(c.Fax), collapseSpaces(c.FaxExt)
+	if fax := normalizePhone(c.Fax, c.FaxExt, cntry); fax != "" {
+		n.Fax, n.FaxExt = fax, ""
+	}
+
+	return n
+}
+
+// collapseSpaces trims the string and replaces inner whitespace with single spaces
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// normalizeEmail lowercases the email, it returns an empty string if the email is not valid
+func normalizeEmail(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s {
+		return ""
+	}
+
+	_, domain, _ := strings.Cut(s, "@")
+	if !strings.Contains(domain, ".") || strings.HasPrefix(domain, ".") || strings.HasSuffix(domain, ".") {
+		return ""
+	}
+	return s
+}
+
+// phoneExtension matches extensions written in the phone number
+var phoneExtension = regexp.MustCompile(`(?i)\s*(?:;\s*ext\s*=|ext\.?|extension|x|#)\s*(\d+)\s*$`)
+
+// trunkPrefixes are the national trunk prefixes other than "0" by the country code.
+// An empty prefix means the leading zero is a part of the national number
+var trunkPrefixes = map[string]string{
+	"IT": "",
+	"SM": "",
+	"VA": "",
+	"RU": "8",
+	"KZ": "8",
+	"BY": "8",
+	"HU": "06",
+}
+
+// normalizePhone returns the phone in the E.164 format with the extension, or an empty string if it's not valid.
+// National numbers get the calling code of the country
+func normalizePhone(phone, ext string, cntry country) string {
+	phone = strings.TrimSpace(phone)
+	if m := phoneExtension.FindStringSubmatch(phone); m != nil {
+		phone = phone[:len(phone)-len(m[0])]
+		if ext == "" {
+			ext = m[1]
+		}
+	}
+
+	international := strings.HasPrefix(phone, "+")
+	digits := onlyDigits(phone)
+
+	switch {
+	case international:
+	case strings.HasPrefix(digits, "00"):
+		digits = digits[2:]
+	case cntry.dial == "":
+		return ""
+	case cntry.dial == "1" && len(digits) == 11 && strings.HasPrefix(digits, "1"):
+	default:
+		trunk, ok := trunkPrefixes[cntry.alpha2]
+		if !ok {
+			trunk = "0"
+		}
+		if trunk != "" {
+			digits = strings.TrimPrefix(digits, trunk)
+		}
+		digits = cntry.dial + digits
+	}
+
+	// E.164 numbers have up to 15 digits, the shortest ones have 8
+	if len(digits) < 8 || len(digits) > 15 || digits[0] == '0' {
+		return ""
+	}
+
+	e164 := "+" + digits
+	if ext = onlyDigits(ext); ext != "" {
+		e164 += ";ext=" + ext
+	}
+	return e164
+}
+
+// onlyDigits returns the ASCII digits of the string
+func onlyDigits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
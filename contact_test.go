@@ -0,0 +1,342 @@
+package whoisapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// TestContactMasking tests the IsRedacted and IsPrivacyProxy methods
+func TestContactMasking(t *testing.T) {
+	tests := []struct {
+		name         string
+		contact      Contact
+		redacted     bool
+		privacyProxy bool
+	}{
+		{
+			name:    "empty",
+			contact: Contact{},
+		},
+		{
+			name:    "real owner",
+			contact: Contact{Name: "Jane Doe", Organization: "Whois API, Inc.", Email: "jane@whoisxmlapi.com"},
+		},
+		{
+			name:     "redacted for privacy",
+			contact:  Contact{Name: "REDACTED FOR PRIVACY", Organization: "REDACTED FOR PRIVACY", Country: "US"},
+			redacted: true,
+		},
+		{
+			name:     "only country is disclosed",
+			contact:  Contact{State: "CA", Country: "UNITED STATES", CountryCode: "US"},
+			redacted: true,
+		},
+		{
+			name:     "contact form email",
+			contact:  Contact{Email: "Select Request Email Form at https://domains.google.com/registrar/whois-contact"},
+			redacted: true,
+		},
+		{
+			name:         "domains by proxy",
+			contact:      Contact{Name: "Registration Private", Organization: "Domains By Proxy, LLC"},
+			privacyProxy: true,
+		},
+		{
+			name:         "proxy email domain",
+			contact:      Contact{Name: "WhoisGuard Protected", Email: "5a3c@whoisguard.com"},
+			privacyProxy: true,
+		},
+		{
+			name:         "withheld for privacy",
+			contact:      Contact{Organization: "Withheld for Privacy ehf", Email: "x@withheldforprivacy.com"},
+			privacyProxy: true,
+		},
+		{
+			name:    "real owner with broad phrases",
+			contact: Contact{Name: "Contact Form Labs", Organization: "Data Protected Systems GmbH", Email: "withheld@example.com"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.contact.IsRedacted(); got != tt.redacted {
+				t.Errorf("IsRedacted() got = %v, want %v", got, tt.redacted)
+			}
+			if got := tt.contact.IsPrivacyProxy(); got != tt.privacyProxy {
+				t.Errorf("IsPrivacyProxy() got = %v, want %v", got, tt.privacyProxy)
+			}
+		})
+	}
+}
+
+// TestMaskDetector tests that the lists of the detector can be extended without changing the defaults
+func TestMaskDetector(t *testing.T) {
+	d := NewMaskDetector()
+	proxy := Contact{Organization: "Acme Privacy Shield"}
+	redacted := Contact{Name: "Masked by Acme", Country: "US"}
+	if d.IsPrivacyProxy(proxy) || d.IsRedacted(redacted) {
+		t.Fatal("contacts are masked before the fragments are added")
+	}
+
+	d.AddPrivacyServices("Acme Privacy Shield")
+	d.AddRedactionPhrases("masked by", " ")
+	if !d.IsPrivacyProxy(proxy) || !d.IsRedacted(redacted) {
+		t.Error("contacts are not masked after the fragments are added")
+	}
+	if proxy.IsPrivacyProxy() || redacted.IsRedacted() {
+		t.Error("the built-in lists are changed")
+	}
+
+	rec := &WhoisRecord{}
+	rec.Registrant = proxy
+	rec.RegistryData.Registrant = Contact{Organization: "Whois API, Inc."}
+	if c, ok := d.EffectiveRegistrant(rec); !ok || c.Organization != "Whois API, Inc." {
+		t.Errorf("EffectiveRegistrant() got = %v, %v", c, ok)
+	}
+}
+
+// TestEffectiveRegistrant tests the EffectiveRegistrant method
+func TestEffectiveRegistrant(t *testing.T) {
+	owner := Contact{Name: "Jane Doe", Organization: "Whois API, Inc."}
+	proxy := Contact{Organization: "Domains By Proxy, LLC"}
+	redacted := Contact{Name: "REDACTED FOR PRIVACY"}
+	unknownProxy := Contact{Organization: "Example Privacy Ltd", Email: "owner@example-privacy.net"}
+
+	tests := []struct {
+		name   string
+		record func() *WhoisRecord
+		want   Contact
+		ok     bool
+	}{
+		{
+			name: "registrar registrant",
+			record: func() *WhoisRecord {
+				rec := &WhoisRecord{}
+				rec.Registrant = owner
+				rec.RegistryData.Registrant = redacted
+				return rec
+			},
+			want: owner,
+			ok:   true,
+		},
+		{
+			name: "registry data",
+			record: func() *WhoisRecord {
+				rec := &WhoisRecord{}
+				rec.Registrant = proxy
+				rec.RegistryData.Registrant = owner
+				return rec
+			},
+			want: owner,
+			ok:   true,
+		},
+		{
+			name: "proxy data marks the registrant",
+			record: func() *WhoisRecord {
+				rec := &WhoisRecord{PrivateWhoisProxy: &unknownProxy}
+				rec.Registrant = unknownProxy
+				rec.RegistryData.Registrant = owner
+				return rec
+			},
+			want: owner,
+			ok:   true,
+		},
+		{
+			name: "proxy data is not the owner",
+			record: func() *WhoisRecord {
+				rec := &WhoisRecord{PrivateWhoisProxy: &unknownProxy}
+				rec.Registrant = redacted
+				rec.RegistryData.Registrant = proxy
+				return rec
+			},
+			want: redacted,
+			ok:   false,
+		},
+		{
+			name: "sub-record",
+			record: func() *WhoisRecord {
+				sub := WhoisRecord{}
+				sub.Registrant = owner
+				rec := &WhoisRecord{SubRecords: []WhoisRecord{sub}}
+				rec.Registrant = redacted
+				return rec
+			},
+			want: owner,
+			ok:   true,
+		},
+		{
+			name: "all masked",
+			record: func() *WhoisRecord {
+				rec := &WhoisRecord{}
+				rec.Registrant = proxy
+				rec.RegistryData.Registrant = redacted
+				return rec
+			},
+			want: proxy,
+			ok:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.record().EffectiveRegistrant()
+			if !reflect.DeepEqual(got, tt.want) || ok != tt.ok {
+				t.Errorf("EffectiveRegistrant() got = %+v, %v, want %+v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+// TestContactNormalize tests the Normalize method
+func TestContactNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		contact Contact
+		want    Contact
+	}{
+		{
+			name: "icann format",
+			contact: Contact{
+				Name:         "  Domain   Administrator ",
+				Street1:      "14455 N. Hayden Road",
+				Street2:      " Suite 219 ",
+				Street4:      "Building 2",
+				Country:      "UNITED STATES",
+				Email:        " Abuse@GoDaddy.COM ",
+				Telephone:    "+1.4805058800",
+				TelephoneExt: "123",
+				Fax:          "+1.4805058844",
+			},
+			want: Contact{
+				Name:        "Domain Administrator",
+				Street1:     "14455 N. Hayden Road, Suite 219, Building 2",
+				Country:     "United States",
+				CountryCode: "US",
+				Email:       "abuse@godaddy.com",
+				Telephone:   "+14805058800;ext=123",
+				Fax:         "+14805058844",
+			},
+		},
+		{
+			name: "national numbers",
+			contact: Contact{
+				CountryCode: "gb",
+				Telephone:   "020 7946 0958 ext. 42",
+				Fax:         "0044 (20) 7946-0959",
+			},
+			want: Contact{
+				Country:     "United Kingdom",
+				CountryCode: "GB",
+				Telephone:   "+442079460958;ext=42",
+				Fax:         "+442079460959",
+			},
+		},
+		{
+			name: "nanp number with the trunk code",
+			contact: Contact{
+				Country:   "USA",
+				Telephone: "1 (480) 505-8800",
+			},
+			want: Contact{
+				Country:     "United States",
+				CountryCode: "US",
+				Telephone:   "+14805058800",
+			},
+		},
+		{
+			name: "alpha-3 code and alias",
+			contact: Contact{
+				Country:     "Russia",
+				CountryCode: "RUS",
+				Telephone:   "+7 495 123-45-67",
+			},
+			want: Contact{
+				Country:     "Russian Federation",
+				CountryCode: "RU",
+				Telephone:   "+74951234567",
+			},
+		},
+		{
+			name: "invalid values are kept",
+			contact: Contact{
+				Country:   "Atlantis",
+				Email:     " Select Request Email Form at https://domains.google.com/registrar/whois-contact",
+				Telephone: "REDACTED FOR PRIVACY",
+				Fax:       "4805058800",
+				FaxExt:    "12",
+			},
+			want: Contact{
+				Country:   "Atlantis",
+				Email:     "Select Request Email Form at https://domains.google.com/registrar/whois-contact",
+				Telephone: "REDACTED FOR PRIVACY",
+				Fax:       "4805058800",
+				FaxExt:    "12",
+			},
+		},
+		{
+			name: "italian numbers keep the leading zero",
+			contact: Contact{
+				CountryCode: "IT",
+				Telephone:   "06 6988 4857",
+				Fax:         "+39 06 6988 4858",
+			},
+			want: Contact{
+				Country:     "Italy",
+				CountryCode: "IT",
+				Telephone:   "+390669884857",
+				Fax:         "+390669884858",
+			},
+		},
+		{
+			name: "other trunk prefixes",
+			contact: Contact{
+				CountryCode: "RU",
+				Telephone:   "8 (495) 123-45-67",
+			},
+			want: Contact{
+				Country:     "Russian Federation",
+				CountryCode: "RU",
+				Telephone:   "+74951234567",
+			},
+		},
+		{
+			name: "extra is copied",
+			contact: Contact{
+				Name:  "Jane Doe",
+				Extra: map[string]json.RawMessage{"handle": json.RawMessage(`"JD1"`)},
+			},
+			want: Contact{
+				Name:  "Jane Doe",
+				Extra: map[string]json.RawMessage{"handle": json.RawMessage(`"JD1"`)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.contact.Normalize()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Normalize() got  = %+v", got)
+				t.Errorf("Normalize() want = %+v", tt.want)
+			}
+		})
+	}
+}
+
+// TestNormalizeEmail tests the normalizeEmail function
+func TestNormalizeEmail(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Support@WhoisXMLAPI.com", "support@whoisxmlapi.com"},
+		{"Jane <jane@example.com>", ""},
+		{"jane@localhost", ""},
+		{"jane@example.com.", ""},
+		{"not an email", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeEmail(tt.in); got != tt.want {
+			t.Errorf("normalizeEmail(%q) got = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
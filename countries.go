@@ -0,0 +1,729 @@
+package whoisapi
+
+// countries are ISO 3166-1 countries from the iso-codes project with ITU calling codes, sorted by the alpha-2 code
+var countries = []country{
+	{"AD", "AND", "Andorra", "376"},
+	{"AE", "ARE", "United Arab Emirates", "971"},
+	{"AF", "AFG", "Afghanistan", "93"},
+	{"AG", "ATG", "Antigua and Barbuda", "1"},
+	{"AI", "AIA", "Anguilla", "1"},
+	{"AL", "ALB", "Albania", "355"},
+	{"AM", "ARM", "Armenia", "374"},
+	{"AO", "AGO", "Angola", "244"},
+	{"AQ", "ATA", "Antarctica", "672"},
+	{"AR", "ARG", "Argentina", "54"},
+	{"AS", "ASM", "American Samoa", "1"},
+	{"AT", "AUT", "Austria", "43"},
+	{"AU", "AUS", "Australia", "61"},
+	{"AW", "ABW", "Aruba", "297"},
+	{"AX", "ALA", "Åland Islands", "358"},
+	{"AZ", "AZE", "Azerbaijan", "994"},
+	{"BA", "BIH", "Bosnia and Herzegovina", "387"},
+	{"BB", "BRB", "Barbados", "1"},
+	{"BD", "BGD", "Bangladesh", "880"},
+	{"BE", "BEL", "Belgium", "32"},
+	{"BF", "BFA", "Burkina Faso", "226"},
+	{"BG", "BGR", "Bulgaria", "359"},
+	{"BH", "BHR", "Bahrain", "973"},
+	{"BI", "BDI", "Burundi", "257"},
+	{"BJ", "BEN", "Benin", "229"},
+	{"BL", "BLM", "Saint Barthélemy", "590"},
+	{"BM", "BMU", "Bermuda", "1"},
+	{"BN", "BRN", "Brunei Darussalam", "673"},
+	{"BO", "BOL", "Bolivia", "591"},
+	{"BQ", "BES", "Bonaire, Sint Eustatius and Saba", "599"},
+	{"BR", "BRA", "Brazil", "55"},
+	{"BS", "BHS", "Bahamas", "1"},
+	{"BT", "BTN", "Bhutan", "975"},
+	{"BV", "BVT", "Bouvet Island", "47"},
+	{"BW", "BWA", "Botswana", "267"},
+	{"BY", "BLR", "Belarus", "375"},
+	{"BZ", "BLZ", "Belize", "501"},
+	{"CA", "CAN", "Canada", "1"},
+	{"CC", "CCK", "Cocos (Keeling) Islands", "61"},
+	{"CD", "COD", "Congo, The Democratic Republic of the", "243"},
+	{"CF", "CAF", "Central African Republic", "236"},
+	{"CG", "COG", "Congo", "242"},
+	{"CH", "CHE", "Switzerland", "41"},
+	{"CI", "CIV", "Côte d'Ivoire", "225"},
+	{"CK", "COK", "Cook Islands", "682"},
+	{"CL", "CHL", "Chile", "56"},
+	{"CM", "CMR", "Cameroon", "237"},
+	{"CN", "CHN", "China", "86"},
+	{"CO", "COL", "Colombia", "57"},
+	{"CR", "CRI", "Costa Rica", "506"},
+	{"CU", "CUB", "Cuba", "53"},
+	{"CV", "CPV", "Cabo Verde", "238"},
+	{"CW", "CUW", "Curaçao", "599"},
+	{"CX", "CXR", "Christmas Island", "61"},
+	{"CY", "CYP", "Cyprus", "357"},
+	{"CZ", "CZE", "Czechia", "420"},
+	{"DE", "DEU", "Germany", "49"},
+	{"DJ", "DJI", "Djibouti", "253"},
+	{"DK", "DNK", "Denmark", "45"},
+	{"DM", "DMA", "Dominica", "1"},
+	{"DO", "DOM", "Dominican Republic", "1"},
+	{"DZ", "DZA", "Algeria", "213"},
+	{"EC", "ECU", "Ecuador", "593"},
+	{"EE", "EST", "Estonia", "372"},
+	{"EG", "EGY", "Egypt", "20"},
+	{"EH", "ESH", "Western Sahara", "212"},
+	{"ER", "ERI", "Eritrea", "291"},
+	{"ES", "ESP", "Spain", "34"},
+	{"ET", "ETH", "Ethiopia", "251"},
+	{"FI", "FIN", "Finland", "358"},
+	{"FJ", "FJI", "Fiji", "679"},
+	{"FK", "FLK", "Falkland Islands (Malvinas)", "500"},
+	{"FM", "FSM", "Micronesia, Federated States of", "691"},
+	{"FO", "FRO", "Faroe Islands", "298"},
+	{"FR", "FRA", "France", "33"},
+	{"GA", "GAB", "Gabon", "241"},
+	{"GB", "GBR", "United Kingdom", "44"},
+	{"GD", "GRD", "Grenada", "1"},
+	{"GE", "GEO", "Georgia", "995"},
+	{"GF", "GUF", "French Guiana", "594"},
+	{"GG", "GGY", "Guernsey", "44"},
+	{"GH", "GHA", "Ghana", "233"},
+	{"GI", "GIB", "Gibraltar", "350"},
+	{"GL", "GRL", "Greenland", "299"},
+	{"GM", "GMB", "Gambia", "220"},
+	{"GN", "GIN", "Guinea", "224"},
+	{"GP", "GLP", "Guadeloupe", "590"},
+	{"GQ", "GNQ", "Equatorial Guinea", "240"},
+	{"GR", "GRC", "Greece", "30"},
+	{"GS", "SGS", "South Georgia and the South Sandwich Islands", "500"},
+	{"GT", "GTM", "Guatemala", "502"},
+	{"GU", "GUM", "Guam", "1"},
+	{"GW", "GNB", "Guinea-Bissau", "245"},
+	{"GY", "GUY", "Guyana", "592"},
+	{"HK", "HKG", "Hong Kong", "852"},
+	{"HM", "HMD", "Heard Island and McDonald Islands", "672"},
+	{"HN", "HND", "Honduras", "504"},
+	{"HR", "HRV", "Croatia", "385"},
+	{"HT", "HTI", "Haiti", "509"},
+	{"HU", "HUN", "Hungary", "36"},
+	{"ID", "IDN", "Indonesia", "62"},
+	{"IE", "IRL", "Ireland", "353"},
+	{"IL", "ISR", "Israel", "972"},
+	{"IM", "IMN", "Isle of Man", "44"},
+	{"IN", "IND", "India", "91"},
+	{"IO", "IOT", "British Indian Ocean Territory", "246"},
+	{"IQ", "IRQ", "Iraq", "964"},
+	{"IR", "IRN", "Iran", "98"},
+	{"IS", "ISL", "Iceland", "354"},
+	{"IT", "ITA", "Italy", "39"},
+	{"JE", "JEY", "Jersey", "44"},
+	{"JM", "JAM", "Jamaica", "1"},
+	{"JO", "JOR", "Jordan", "962"},
+	{"JP", "JPN", "Japan", "81"},
+	{"KE", "KEN", "Kenya", "254"},
+	{"KG", "KGZ", "Kyrgyzstan", "996"},
+	{"KH", "KHM", "Cambodia", "855"},
+	{"KI", "KIR", "Kiribati", "686"},
+	{"KM", "COM", "Comoros", "269"},
+	{"KN", "KNA", "Saint Kitts and Nevis", "1"},
+	{"KP", "PRK", "North Korea", "850"},
+	{"KR", "KOR", "South Korea", "82"},
+	{"KW", "KWT", "Kuwait", "965"},
+	{"KY", "CYM", "Cayman Islands", "1"},
+	{"KZ", "KAZ", "Kazakhstan", "7"},
+	{"LA", "LAO", "Laos", "856"},
+	{"LB", "LBN", "Lebanon", "961"},
+	{"LC", "LCA", "Saint Lucia", "1"},
+	{"LI", "LIE", "Liechtenstein", "423"},
+	{"LK", "LKA", "Sri Lanka", "94"},
+	{"LR", "LBR", "Liberia", "231"},
+	{"LS", "LSO", "Lesotho", "266"},
+	{"LT", "LTU", "Lithuania", "370"},
+	{"LU", "LUX", "Luxembourg", "352"},
+	{"LV", "LVA", "Latvia", "371"},
+	{"LY", "LBY", "Libya", "218"},
+	{"MA", "MAR", "Morocco", "212"},
+	{"MC", "MCO", "Monaco", "377"},
+	{"MD", "MDA", "Moldova", "373"},
+	{"ME", "MNE", "Montenegro", "382"},
+	{"MF", "MAF", "Saint Martin (French part)", "590"},
+	{"MG", "MDG", "Madagascar", "261"},
+	{"MH", "MHL", "Marshall Islands", "692"},
+	{"MK", "MKD", "North Macedonia", "389"},
+	{"ML", "MLI", "Mali", "223"},
+	{"MM", "MMR", "Myanmar", "95"},
+	{"MN", "MNG", "Mongolia", "976"},
+	{"MO", "MAC", "Macao", "853"},
+	{"MP", "MNP", "Northern Mariana Islands", "1"},
+	{"MQ", "MTQ", "Martinique", "596"},
+	{"MR", "MRT", "Mauritania", "222"},
+	{"MS", "MSR", "Montserrat", "1"},
+	{"MT", "MLT", "Malta", "356"},
+	{"MU", "MUS", "Mauritius", "230"},
+	{"MV", "MDV", "Maldives", "960"},
+	{"MW", "MWI", "Malawi", "265"},
+	{"MX", "MEX", "Mexico", "52"},
+	{"MY", "MYS", "Malaysia", "60"},
+	{"MZ", "MOZ", "Mozambique", "258"},
+	{"NA", "NAM", "Namibia", "264"},
+	{"NC", "NCL", "New Caledonia", "687"},
+	{"NE", "NER", "Niger", "227"},
+	{"NF", "NFK", "Norfolk Island", "672"},
+	{"NG", "NGA", "Nigeria", "234"},
+	{"NI", "NIC", "Nicaragua", "505"},
+	{"NL", "NLD", "Netherlands", "31"},
+	{"NO", "NOR", "Norway", "47"},
+	{"NP", "NPL", "Nepal", "977"},
+	{"NR", "NRU", "Nauru", "674"},
+	{"NU", "NIU", "Niue", "683"},
+	{"NZ", "NZL", "New Zealand", "64"},
+	{"OM", "OMN", "Oman", "968"},
+	{"PA", "PAN", "Panama", "507"},
+	{"PE", "PER", "Peru", "51"},
+	{"PF", "PYF", "French Polynesia", "689"},
+	{"PG", "PNG", "Papua New Guinea", "675"},
+	{"PH", "PHL", "Philippines", "63"},
+	{"PK", "PAK", "Pakistan", "92"},
+	{"PL", "POL", "Poland", "48"},
+	{"PM", "SPM", "Saint Pierre and Miquelon", "508"},
+	{"PN", "PCN", "Pitcairn", "64"},
+	{"PR", "PRI", "Puerto Rico", "1"},
+	{"PS", "PSE", "Palestine, State of", "970"},
+	{"PT", "PRT", "Portugal", "351"},
+	{"PW", "PLW", "Palau", "680"},
+	{"PY", "PRY", "Paraguay", "595"},
+	{"QA", "QAT", "Qatar", "974"},
+	{"RE", "REU", "Réunion", "262"},
+	{"RO", "ROU", "Romania", "40"},
+	{"RS", "SRB", "Serbia", "381"},
+	{"RU", "RUS", "Russian Federation", "7"},
+	{"RW", "RWA", "Rwanda", "250"},
+	{"SA", "SAU", "Saudi Arabia", "966"},
+	{"SB", "SLB", "Solomon Islands", "677"},
+	{"SC", "SYC", "Seychelles", "248"},
+	{"SD", "SDN", "Sudan", "249"},
+	{"SE", "SWE", "Sweden", "46"},
+	{"SG", "SGP", "Singapore", "65"},
+	{"SH", "SHN", "Saint Helena, Ascension and Tristan da Cunha", "290"},
+	{"SI", "SVN", "Slovenia", "386"},
+	{"SJ", "SJM", "Svalbard and Jan Mayen", "47"},
+	{"SK", "SVK", "Slovakia", "421"},
+	{"SL", "SLE", "Sierra Leone", "232"},
+	{"SM", "SMR", "San Marino", "378"},
+	{"SN", "SEN", "Senegal", "221"},
+	{"SO", "SOM", "Somalia", "252"},
+	{"SR", "SUR", "Suriname", "597"},
+	{"SS", "SSD", "South Sudan", "211"},
+	{"ST", "STP", "Sao Tome and Principe", "239"},
+	{"SV", "SLV", "El Salvador", "503"},
+	{"SX", "SXM", "Sint Maarten (Dutch part)", "1"},
+	{"SY", "SYR", "Syria", "963"},
+	{"SZ", "SWZ", "Eswatini", "268"},
+	{"TC", "TCA", "Turks and Caicos Islands", "1"},
+	{"TD", "TCD", "Chad", "235"},
+	{"TF", "ATF", "French Southern Territories", "262"},
+	{"TG", "TGO", "Togo", "228"},
+	{"TH", "THA", "Thailand", "66"},
+	{"TJ", "TJK", "Tajikistan", "992"},
+	{"TK", "TKL", "Tokelau", "690"},
+	{"TL", "TLS", "Timor-Leste", "670"},
+	{"TM", "TKM", "Turkmenistan", "993"},
+	{"TN", "TUN", "Tunisia", "216"},
+	{"TO", "TON", "Tonga", "676"},
+	{"TR", "TUR", "Türkiye", "90"},
+	{"TT", "TTO", "Trinidad and Tobago", "1"},
+	{"TV", "TUV", "Tuvalu", "688"},
+	{"TW", "TWN", "Taiwan", "886"},
+	{"TZ", "TZA", "Tanzania", "255"},
+	{"UA", "UKR", "Ukraine", "380"},
+	{"UG", "UGA", "Uganda", "256"},
+	{"UM", "UMI", "United States Minor Outlying Islands", "1"},
+	{"US", "USA", "United States", "1"},
+	{"UY", "URY", "Uruguay", "598"},
+	{"UZ", "UZB", "Uzbekistan", "998"},
+	{"VA", "VAT", "Holy See (Vatican City State)", "39"},
+	{"VC", "VCT", "Saint Vincent and the Grenadines", "1"},
+	{"VE", "VEN", "Venezuela", "58"},
+	{"VG", "VGB", "Virgin Islands, British", "1"},
+	{"VI", "VIR", "Virgin Islands, U.S.", "1"},
+	{"VN", "VNM", "Vietnam", "84"},
+	{"VU", "VUT", "Vanuatu", "678"},
+	{"WF", "WLF", "Wallis and Futuna", "681"},
+	{"WS", "WSM", "Samoa", "685"},
+	{"YE", "YEM", "Yemen", "967"},
+	{"YT", "MYT", "Mayotte", "262"},
+	{"ZA", "ZAF", "South Africa", "27"},
+	{"ZM", "ZMB", "Zambia", "260"},
+	{"ZW", "ZWE", "Zimbabwe", "263"},
+}
+
+// countryNames are lowercase country names and aliases with their alpha-2 codes
+var countryNames = map[string]string{
+	"afghanistan":                      "AF",
+	"aland islands":                    "AX",
+	"albania":                          "AL",
+	"algeria":                          "DZ",
+	"america":                          "US",
+	"american samoa":                   "AS",
+	"andorra":                          "AD",
+	"angola":                           "AO",
+	"anguilla":                         "AI",
+	"antarctica":                       "AQ",
+	"antigua and barbuda":              "AG",
+	"arab republic of egypt":           "EG",
+	"argentina":                        "AR",
+	"argentine republic":               "AR",
+	"armenia":                          "AM",
+	"aruba":                            "AW",
+	"australia":                        "AU",
+	"austria":                          "AT",
+	"azerbaijan":                       "AZ",
+	"bahamas":                          "BS",
+	"bahrain":                          "BH",
+	"bangladesh":                       "BD",
+	"barbados":                         "BB",
+	"belarus":                          "BY",
+	"belgium":                          "BE",
+	"belize":                           "BZ",
+	"benin":                            "BJ",
+	"bermuda":                          "BM",
+	"bhutan":                           "BT",
+	"bolivarian republic of venezuela": "VE",
+	"bolivia":                          "BO",
+	"bolivia plurinational state of":   "BO",
+	"bonaire sint eustatius and saba":  "BQ",
+	"bosnia":                           "BA",
+	"bosnia and herzegovina":           "BA",
+	"botswana":                         "BW",
+	"bouvet island":                    "BV",
+	"brazil":                           "BR",
+	"british indian ocean territory":   "IO",
+	"british virgin islands":           "VG",
+	"brunei":                           "BN",
+	"brunei darussalam":                "BN",
+	"bulgaria":                         "BG",
+	"burkina faso":                     "BF",
+	"burma":                            "MM",
+	"burundi":                          "BI",
+	"cabo verde":                       "CV",
+	"cambodia":                         "KH",
+	"cameroon":                         "CM",
+	"canada":                           "CA",
+	"cape verde":                       "CV",
+	"cayman islands":                   "KY",
+	"central african republic":         "CF",
+	"chad":                             "TD",
+	"chile":                            "CL",
+	"china":                            "CN",
+	"christmas island":                 "CX",
+	"cocos keeling islands":            "CC",
+	"colombia":                         "CO",
+	"commonwealth of dominica":         "DM",
+	"commonwealth of the bahamas":      "BS",
+	"commonwealth of the northern mariana islands": "MP",
+	"comoros":                               "KM",
+	"congo":                                 "CG",
+	"congo brazzaville":                     "CG",
+	"congo kinshasa":                        "CD",
+	"congo the democratic republic of the":  "CD",
+	"cook islands":                          "CK",
+	"costa rica":                            "CR",
+	"cote d ivoire":                         "CI",
+	"croatia":                               "HR",
+	"cuba":                                  "CU",
+	"curacao":                               "CW",
+	"curaçao":                               "CW",
+	"cyprus":                                "CY",
+	"czech republic":                        "CZ",
+	"czechia":                               "CZ",
+	"côte d ivoire":                         "CI",
+	"democratic people s republic of korea": "KP",
+	"democratic republic of congo":          "CD",
+	"democratic republic of sao tome and principe": "ST",
+	"democratic republic of timor-leste":           "TL",
+	"democratic socialist republic of sri lanka":   "LK",
+	"denmark":                     "DK",
+	"djibouti":                    "DJ",
+	"dominica":                    "DM",
+	"dominican republic":          "DO",
+	"drc":                         "CD",
+	"east timor":                  "TL",
+	"eastern republic of uruguay": "UY",
+	"ecuador":                     "EC",
+	"egypt":                       "EG",
+	"el salvador":                 "SV",
+	"england":                     "GB",
+	"equatorial guinea":           "GQ",
+	"eritrea":                     "ER",
+	"estonia":                     "EE",
+	"eswatini":                    "SZ",
+	"ethiopia":                    "ET",
+	"falkland islands malvinas":   "FK",
+	"faroe islands":               "FO",
+	"federal democratic republic of ethiopia": "ET",
+	"federal democratic republic of nepal":    "NP",
+	"federal republic of germany":             "DE",
+	"federal republic of nigeria":             "NG",
+	"federal republic of somalia":             "SO",
+	"federated states of micronesia":          "FM",
+	"federative republic of brazil":           "BR",
+	"fiji":                                    "FJ",
+	"finland":                                 "FI",
+	"france":                                  "FR",
+	"french guiana":                           "GF",
+	"french polynesia":                        "PF",
+	"french republic":                         "FR",
+	"french southern territories":             "TF",
+	"gabon":                                   "GA",
+	"gabonese republic":                       "GA",
+	"gambia":                                  "GM",
+	"georgia":                                 "GE",
+	"germany":                                 "DE",
+	"ghana":                                   "GH",
+	"gibraltar":                               "GI",
+	"grand duchy of luxembourg":               "LU",
+	"great britain":                           "GB",
+	"greece":                                  "GR",
+	"greenland":                               "GL",
+	"grenada":                                 "GD",
+	"guadeloupe":                              "GP",
+	"guam":                                    "GU",
+	"guatemala":                               "GT",
+	"guernsey":                                "GG",
+	"guinea":                                  "GN",
+	"guinea-bissau":                           "GW",
+	"guyana":                                  "GY",
+	"haiti":                                   "HT",
+	"hashemite kingdom of jordan":             "JO",
+	"heard island and mcdonald islands":       "HM",
+	"hellenic republic":                       "GR",
+	"holland":                                 "NL",
+	"holy see":                                "VA",
+	"holy see vatican city state":             "VA",
+	"honduras":                                "HN",
+	"hong kong":                               "HK",
+	"hong kong sar":                           "HK",
+	"hong kong special administrative region of china": "HK",
+	"hungary":                               "HU",
+	"iceland":                               "IS",
+	"independent state of papua new guinea": "PG",
+	"independent state of samoa":            "WS",
+	"india":                                 "IN",
+	"indonesia":                             "ID",
+	"iran":                                  "IR",
+	"iran islamic republic of":              "IR",
+	"iraq":                                  "IQ",
+	"ireland":                               "IE",
+	"islamic republic of afghanistan":       "AF",
+	"islamic republic of iran":              "IR",
+	"islamic republic of mauritania":        "MR",
+	"islamic republic of pakistan":          "PK",
+	"isle of man":                           "IM",
+	"israel":                                "IL",
+	"italian republic":                      "IT",
+	"italy":                                 "IT",
+	"ivory coast":                           "CI",
+	"jamaica":                               "JM",
+	"japan":                                 "JP",
+	"jersey":                                "JE",
+	"jordan":                                "JO",
+	"kazakhstan":                            "KZ",
+	"kenya":                                 "KE",
+	"kingdom of bahrain":                    "BH",
+	"kingdom of belgium":                    "BE",
+	"kingdom of bhutan":                     "BT",
+	"kingdom of cambodia":                   "KH",
+	"kingdom of denmark":                    "DK",
+	"kingdom of eswatini":                   "SZ",
+	"kingdom of lesotho":                    "LS",
+	"kingdom of morocco":                    "MA",
+	"kingdom of norway":                     "NO",
+	"kingdom of saudi arabia":               "SA",
+	"kingdom of spain":                      "ES",
+	"kingdom of sweden":                     "SE",
+	"kingdom of thailand":                   "TH",
+	"kingdom of the netherlands":            "NL",
+	"kingdom of tonga":                      "TO",
+	"kiribati":                              "KI",
+	"korea":                                 "KR",
+	"korea democratic people s republic of": "KP",
+	"korea republic of":                     "KR",
+	"kuwait":                                "KW",
+	"kyrgyz republic":                       "KG",
+	"kyrgyzstan":                            "KG",
+	"lao people s democratic republic":      "LA",
+	"laos":                                  "LA",
+	"latvia":                                "LV",
+	"lebanese republic":                     "LB",
+	"lebanon":                               "LB",
+	"lesotho":                               "LS",
+	"liberia":                               "LR",
+	"libya":                                 "LY",
+	"liechtenstein":                         "LI",
+	"lithuania":                             "LT",
+	"luxembourg":                            "LU",
+	"macao":                                 "MO",
+	"macao special administrative region of china": "MO",
+	"macau":                          "MO",
+	"macedonia":                      "MK",
+	"madagascar":                     "MG",
+	"malawi":                         "MW",
+	"malaysia":                       "MY",
+	"maldives":                       "MV",
+	"mali":                           "ML",
+	"malta":                          "MT",
+	"marshall islands":               "MH",
+	"martinique":                     "MQ",
+	"mauritania":                     "MR",
+	"mauritius":                      "MU",
+	"mayotte":                        "YT",
+	"mexico":                         "MX",
+	"micronesia":                     "FM",
+	"micronesia federated states of": "FM",
+	"moldova":                        "MD",
+	"moldova republic of":            "MD",
+	"monaco":                         "MC",
+	"mongolia":                       "MN",
+	"montenegro":                     "ME",
+	"montserrat":                     "MS",
+	"morocco":                        "MA",
+	"mozambique":                     "MZ",
+	"myanmar":                        "MM",
+	"namibia":                        "NA",
+	"nauru":                          "NR",
+	"nepal":                          "NP",
+	"netherlands":                    "NL",
+	"new caledonia":                  "NC",
+	"new zealand":                    "NZ",
+	"nicaragua":                      "NI",
+	"niger":                          "NE",
+	"nigeria":                        "NG",
+	"niue":                           "NU",
+	"norfolk island":                 "NF",
+	"north korea":                    "KP",
+	"north macedonia":                "MK",
+	"northern ireland":               "GB",
+	"northern mariana islands":       "MP",
+	"norway":                         "NO",
+	"oman":                           "OM",
+	"pakistan":                       "PK",
+	"palau":                          "PW",
+	"palestine":                      "PS",
+	"palestine state of":             "PS",
+	"panama":                         "PA",
+	"papua new guinea":               "PG",
+	"paraguay":                       "PY",
+	"people s democratic republic of algeria":      "DZ",
+	"people s republic of bangladesh":              "BD",
+	"people s republic of china":                   "CN",
+	"peru":                                         "PE",
+	"philippines":                                  "PH",
+	"pitcairn":                                     "PN",
+	"plurinational state of bolivia":               "BO",
+	"poland":                                       "PL",
+	"portugal":                                     "PT",
+	"portuguese republic":                          "PT",
+	"prc":                                          "CN",
+	"principality of andorra":                      "AD",
+	"principality of liechtenstein":                "LI",
+	"principality of monaco":                       "MC",
+	"puerto rico":                                  "PR",
+	"qatar":                                        "QA",
+	"republic of albania":                          "AL",
+	"republic of angola":                           "AO",
+	"republic of armenia":                          "AM",
+	"republic of austria":                          "AT",
+	"republic of azerbaijan":                       "AZ",
+	"republic of belarus":                          "BY",
+	"republic of benin":                            "BJ",
+	"republic of bosnia and herzegovina":           "BA",
+	"republic of botswana":                         "BW",
+	"republic of bulgaria":                         "BG",
+	"republic of burundi":                          "BI",
+	"republic of cabo verde":                       "CV",
+	"republic of cameroon":                         "CM",
+	"republic of chad":                             "TD",
+	"republic of chile":                            "CL",
+	"republic of colombia":                         "CO",
+	"republic of costa rica":                       "CR",
+	"republic of croatia":                          "HR",
+	"republic of cuba":                             "CU",
+	"republic of cyprus":                           "CY",
+	"republic of côte d ivoire":                    "CI",
+	"republic of djibouti":                         "DJ",
+	"republic of ecuador":                          "EC",
+	"republic of el salvador":                      "SV",
+	"republic of equatorial guinea":                "GQ",
+	"republic of estonia":                          "EE",
+	"republic of fiji":                             "FJ",
+	"republic of finland":                          "FI",
+	"republic of ghana":                            "GH",
+	"republic of guatemala":                        "GT",
+	"republic of guinea":                           "GN",
+	"republic of guinea-bissau":                    "GW",
+	"republic of guyana":                           "GY",
+	"republic of haiti":                            "HT",
+	"republic of honduras":                         "HN",
+	"republic of iceland":                          "IS",
+	"republic of india":                            "IN",
+	"republic of indonesia":                        "ID",
+	"republic of iraq":                             "IQ",
+	"republic of kazakhstan":                       "KZ",
+	"republic of kenya":                            "KE",
+	"republic of kiribati":                         "KI",
+	"republic of korea":                            "KR",
+	"republic of latvia":                           "LV",
+	"republic of liberia":                          "LR",
+	"republic of lithuania":                        "LT",
+	"republic of madagascar":                       "MG",
+	"republic of malawi":                           "MW",
+	"republic of maldives":                         "MV",
+	"republic of mali":                             "ML",
+	"republic of malta":                            "MT",
+	"republic of mauritius":                        "MU",
+	"republic of moldova":                          "MD",
+	"republic of mozambique":                       "MZ",
+	"republic of myanmar":                          "MM",
+	"republic of namibia":                          "NA",
+	"republic of nauru":                            "NR",
+	"republic of nicaragua":                        "NI",
+	"republic of north macedonia":                  "MK",
+	"republic of palau":                            "PW",
+	"republic of panama":                           "PA",
+	"republic of paraguay":                         "PY",
+	"republic of peru":                             "PE",
+	"republic of poland":                           "PL",
+	"republic of san marino":                       "SM",
+	"republic of senegal":                          "SN",
+	"republic of serbia":                           "RS",
+	"republic of seychelles":                       "SC",
+	"republic of sierra leone":                     "SL",
+	"republic of singapore":                        "SG",
+	"republic of slovenia":                         "SI",
+	"republic of south africa":                     "ZA",
+	"republic of south sudan":                      "SS",
+	"republic of suriname":                         "SR",
+	"republic of tajikistan":                       "TJ",
+	"republic of the congo":                        "CG",
+	"republic of the gambia":                       "GM",
+	"republic of the marshall islands":             "MH",
+	"republic of the niger":                        "NE",
+	"republic of the philippines":                  "PH",
+	"republic of the sudan":                        "SD",
+	"republic of trinidad and tobago":              "TT",
+	"republic of tunisia":                          "TN",
+	"republic of türkiye":                          "TR",
+	"republic of uganda":                           "UG",
+	"republic of uzbekistan":                       "UZ",
+	"republic of vanuatu":                          "VU",
+	"republic of yemen":                            "YE",
+	"republic of zambia":                           "ZM",
+	"republic of zimbabwe":                         "ZW",
+	"reunion":                                      "RE",
+	"romania":                                      "RO",
+	"russia":                                       "RU",
+	"russian federation":                           "RU",
+	"rwanda":                                       "RW",
+	"rwandese republic":                            "RW",
+	"réunion":                                      "RE",
+	"saint barthelemy":                             "BL",
+	"saint barthélemy":                             "BL",
+	"saint helena ascension and tristan da cunha":  "SH",
+	"saint kitts and nevis":                        "KN",
+	"saint lucia":                                  "LC",
+	"saint martin french part":                     "MF",
+	"saint pierre and miquelon":                    "PM",
+	"saint vincent and the grenadines":             "VC",
+	"samoa":                                        "WS",
+	"san marino":                                   "SM",
+	"sao tome and principe":                        "ST",
+	"saudi arabia":                                 "SA",
+	"scotland":                                     "GB",
+	"senegal":                                      "SN",
+	"serbia":                                       "RS",
+	"seychelles":                                   "SC",
+	"sierra leone":                                 "SL",
+	"singapore":                                    "SG",
+	"sint maarten dutch part":                      "SX",
+	"slovak republic":                              "SK",
+	"slovakia":                                     "SK",
+	"slovenia":                                     "SI",
+	"socialist republic of viet nam":               "VN",
+	"solomon islands":                              "SB",
+	"somalia":                                      "SO",
+	"south africa":                                 "ZA",
+	"south georgia and the south sandwich islands": "GS",
+	"south korea":                                  "KR",
+	"south sudan":                                  "SS",
+	"spain":                                        "ES",
+	"sri lanka":                                    "LK",
+	"st kitts and nevis":                           "KN",
+	"st lucia":                                     "LC",
+	"st vincent and the grenadines":                "VC",
+	"state of eritrea":                             "ER",
+	"state of israel":                              "IL",
+	"state of kuwait":                              "KW",
+	"state of palestine":                           "PS",
+	"state of qatar":                               "QA",
+	"sudan":                                        "SD",
+	"sultanate of oman":                            "OM",
+	"suriname":                                     "SR",
+	"svalbard and jan mayen":                       "SJ",
+	"swaziland":                                    "SZ",
+	"sweden":                                       "SE",
+	"swiss confederation":                          "CH",
+	"switzerland":                                  "CH",
+	"syria":                                        "SY",
+	"syrian arab republic":                         "SY",
+	"taiwan":                                       "TW",
+	"taiwan province of china":                     "TW",
+	"tajikistan":                                   "TJ",
+	"tanzania":                                     "TZ",
+	"tanzania united republic of":                  "TZ",
+	"thailand":                                     "TH",
+	"timor-leste":                                  "TL",
+	"togo":                                         "TG",
+	"togolese republic":                            "TG",
+	"tokelau":                                      "TK",
+	"tonga":                                        "TO",
+	"trinidad":                                     "TT",
+	"trinidad and tobago":                          "TT",
+	"tunisia":                                      "TN",
+	"turkey":                                       "TR",
+	"turkiye":                                      "TR",
+	"turkmenistan":                                 "TM",
+	"turks and caicos islands":                     "TC",
+	"tuvalu":                                       "TV",
+	"türkiye":                                      "TR",
+	"u s":                                          "US",
+	"u s a":                                        "US",
+	"uae":                                          "AE",
+	"uganda":                                       "UG",
+	"uk":                                           "GB",
+	"ukraine":                                      "UA",
+	"union of the comoros":                         "KM",
+	"united arab emirates":                         "AE",
+	"united kingdom":                               "GB",
+	"united kingdom of great britain and northern ireland": "GB",
+	"united mexican states":                                "MX",
+	"united republic of tanzania":                          "TZ",
+	"united states":                                        "US",
+	"united states minor outlying islands":                 "UM",
+	"united states of america":                             "US",
+	"uruguay":                                              "UY",
+	"usa":                                                  "US",
+	"uzbekistan":                                           "UZ",
+	"vanuatu":                                              "VU",
+	"vatican":                                              "VA",
+	"vatican city":                                         "VA",
+	"venezuela":                                            "VE",
+	"venezuela bolivarian republic of":                     "VE",
+	"viet nam":                                             "VN",
+	"vietnam":                                              "VN",
+	"virgin islands british":                               "VG",
+	"virgin islands of the united states":                  "VI",
+	"virgin islands u s":                                   "VI",
+	"wales":                                                "GB",
+	"wallis and futuna":                                    "WF",
+	"western sahara":                                       "EH",
+	"yemen":                                                "YE",
+	"zambia":                                               "ZM",
+	"zimbabwe":                                             "ZW",
+	"åland islands":                                        "AX",
+}
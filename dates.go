@@ -0,0 +1,231 @@
+package whoisapi
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DateSource is the field a record date is taken from
+type DateSource int
+
+const (
+	// DateSourceNone means the date is not available
+	DateSourceNone DateSource = iota
+
+	// DateSourceNormalized means the date is the *DateNormalized field of the record
+	DateSourceNormalized
+
+	// DateSourceRaw means the date is parsed from the raw date field of the record
+	DateSourceRaw
+
+	// DateSourceRegistry means the date is taken from RegistryData, normalized or raw
+	DateSourceRegistry
+)
+
+// String returns the source name
+func (s DateSource) String() string {
+	switch s {
+	case DateSourceNormalized:
+		return "normalized"
+	case DateSourceRaw:
+		return "raw"
+	case DateSourceRegistry:
+		return "registry"
+	default:
+		return "none"
+	}
+}
+
+// dateLayouts are the layouts used by registrars and registries, dates without a zone are in UTC
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05 Z07:00",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"02-Jan-2006 15:04:05 MST",
+	"02-Jan-2006 15:04:05",
+	"2-Jan-2006",
+	"2 Jan 2006",
+	"2. Jan 2006",
+	"2 January 2006",
+	"Jan 2 2006",
+	"January 2 2006",
+	"Jan-2-2006",
+	time.UnixDate,
+	time.ANSIC,
+	time.RFC1123,
+	time.RFC1123Z,
+	time.RFC822,
+	"2006.01.02 15:04:05",
+	"2006.01.02",
+	"2006. 01. 02.",
+	"2.1.2006 15:04:05",
+	"2.1.2006",
+	"02-01-2006",
+	"2006/01/02 15:04:05",
+	"2006/01/02",
+	"20060102",
+}
+
+// zoneOffsets are the offsets of zone abbreviations in dates. time.Parse knows only the abbreviations
+// of the local zone and parses others with zero offset. Ambiguous ones like "CST" or "IST" are left out
+var zoneOffsets = map[string]int{
+	"UTC": 0, "UT": 0, "GMT": 0, "Z": 0, "WET": 0,
+	"WEST": 1 * 3600, "BST": 1 * 3600, "CET": 1 * 3600, "MET": 1 * 3600,
+	"CEST": 2 * 3600, "MEST": 2 * 3600, "EET": 2 * 3600,
+	"EEST": 3 * 3600, "MSK": 3 * 3600,
+	"HKT": 8 * 3600, "SGT": 8 * 3600, "AWST": 8 * 3600,
+	"JST": 9 * 3600, "KST": 9 * 3600, "ACST": 9*3600 + 1800,
+	"AEST": 10 * 3600, "AEDT": 11 * 3600, "NZST": 12 * 3600, "NZDT": 13 * 3600,
+	"EST": -5 * 3600, "EDT": -4 * 3600, "CDT": -5 * 3600,
+	"MST": -7 * 3600, "MDT": -6 * 3600, "PST": -8 * 3600, "PDT": -7 * 3600,
+	"AKST": -9 * 3600, "AKDT": -8 * 3600, "HST": -10 * 3600,
+}
+
+// localMonths are lowercase localized month names and abbreviations
+var localMonths = map[string]string{}
+
+func init() {
+	for month, names := range map[string][]string{
+		"Jan": {"january", "janvier", "janv", "januar", "jänner", "enero", "ene", "gennaio", "gen", "janeiro", "januari", "января", "январь", "янв", "stycznia", "styczeń", "sty", "ocak"},
+		"Feb": {"february", "février", "fevrier", "févr", "fevr", "februar", "febrero", "febbraio", "fevereiro", "fev", "februari", "февраля", "февраль", "фев", "lutego", "luty", "lut", "şubat", "subat"},
+		"Mar": {"march", "mars", "märz", "maerz", "mrz", "marzo", "março", "marco", "maart", "mrt", "марта", "март", "мар", "marca", "marzec", "mart"},
+		"Apr": {"april", "avril", "avr", "abril", "abr", "aprile", "апреля", "апрель", "апр", "kwietnia", "kwiecień", "kwi", "nisan"},
+		"May": {"mai", "mayo", "maggio", "mag", "maio", "mei", "мая", "май", "maja", "maj", "mayıs", "mayis"},
+		"Jun": {"june", "juin", "juni", "junio", "giugno", "giu", "junho", "июня", "июнь", "июн", "czerwca", "czerwiec", "cze", "haziran"},
+		"Jul": {"july", "juillet", "juil", "juli", "julio", "luglio", "lug", "julho", "июля", "июль", "июл", "lipca", "lipiec", "lip", "temmuz"},
+		"Aug": {"august", "août", "aout", "agosto", "ago", "augustus", "августа", "август", "авг", "sierpnia", "sierpień", "sie", "ağustos", "agustos"},
+		"Sep": {"september", "septembre", "sept", "septiembre", "setiembre", "settembre", "set", "setembro", "сентября", "сентябрь", "сен", "września", "wrzesień", "wrz", "eylül", "eylul"},
+		"Oct": {"october", "octobre", "oktober", "okt", "octubre", "ottobre", "ott", "outubro", "out", "октября", "октябрь", "окт", "października", "październik", "paź", "ekim"},
+		"Nov": {"november", "novembre", "noviembre", "novembro", "ноября", "ноябрь", "ноя", "listopada", "listopad", "lis", "kasım", "kasim"},
+		"Dec": {"december", "décembre", "decembre", "déc", "dezember", "dez", "diciembre", "dic", "dicembre", "dezembro", "декабря", "декабрь", "дек", "grudnia", "grudzień", "gru", "aralık", "aralik"},
+	} {
+		for _, name := range names {
+			localMonths[name] = month
+		}
+	}
+}
+
+// dateFillers are words dropped from localized dates, e.g. "2 de enero de 2006"
+var dateFillers = map[string]bool{
+	"de":   true,
+	"del":  true,
+	"of":   true,
+	"г":    true,
+	"года": true,
+}
+
+var (
+	// dateWord matches words of the date with an optional abbreviation dot
+	dateWord = regexp.MustCompile(`\p{L}+\.?`)
+
+	// dateComment matches comments after the date like "(UTC+8)"
+	dateComment = regexp.MustCompile(`\s*\([^)]*\)\s*$`)
+)
+
+// ParseDate parses the date in one of the formats used by registrars and registries,
+// including RFC 3339, dd-Mon-yyyy and dates with localized month names.
+// Dates with an unknown zone abbreviation are rejected instead of being taken as UTC
+func ParseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, fmt.Errorf("cannot parse date: empty string")
+	}
+
+	candidates := []string{s}
+	if cleaned := cleanDate(s); cleaned != s {
+		candidates = append(candidates, cleaned)
+	}
+
+	for _, c := range candidates {
+		for _, layout := range dateLayouts {
+			if t, err := time.Parse(layout, c); err == nil {
+				return fixZone(s, t)
+			}
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("cannot parse date %q", s)
+}
+
+// fixZone sets the offset of the zone abbreviation that time.Parse didn't know
+func fixZone(s string, t time.Time) (time.Time, error) {
+	name, offset := t.Zone()
+	if offset != 0 || name == "" || name == "UTC" {
+		return t, nil
+	}
+	known, ok := zoneOffsets[name]
+	if !ok {
+		return time.Time{}, fmt.Errorf("cannot parse date %q: unknown time zone %q", s, name)
+	}
+	if known == 0 {
+		return t, nil
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(),
+		time.FixedZone(name, known)), nil
+}
+
+// cleanDate removes comments and filler words and replaces localized month names with English ones
+func cleanDate(s string) string {
+	s = dateComment.ReplaceAllString(s, "")
+	s = dateWord.ReplaceAllStringFunc(s, func(word string) string {
+		key := strings.ToLower(strings.TrimSuffix(word, "."))
+		if month, ok := localMonths[key]; ok {
+			return month
+		}
+		if dateFillers[key] {
+			return ""
+		}
+		return word
+	})
+	s = strings.TrimSuffix(strings.Join(strings.Fields(s), " "), ",")
+	return strings.ReplaceAll(s, ",", "")
+}
+
+// recordDate returns the normalized date or parses the raw one
+func recordDate(normalized Time, raw string) (time.Time, bool) {
+	if t := time.Time(normalized); !t.IsZero() {
+		return t, true
+	}
+	if t, err := ParseDate(raw); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// pickDate returns the date of the record, falling back to RegistryData
+func pickDate(normalized Time, raw string, registryNormalized Time, registryRaw string) (time.Time, DateSource) {
+	if t := time.Time(normalized); !t.IsZero() {
+		return t, DateSourceNormalized
+	}
+	if t, err := ParseDate(raw); err == nil {
+		return t, DateSourceRaw
+	}
+	if t, ok := recordDate(registryNormalized, registryRaw); ok {
+		return t, DateSourceRegistry
+	}
+	return time.Time{}, DateSourceNone
+}
+
+// Created returns the date the domain was registered and the field it is taken from
+func (r *WhoisRecord) Created() (time.Time, DateSource) {
+	return pickDate(r.CreatedDateNormalized, r.CreatedDate, r.RegistryData.CreatedDateNormalized, r.RegistryData.CreatedDate)
+}
+
+// Updated returns the date the record was last updated and the field it is taken from
+func (r *WhoisRecord) Updated() (time.Time, DateSource) {
+	return pickDate(r.UpdatedDateNormalized, r.UpdatedDate, r.RegistryData.UpdatedDateNormalized, r.RegistryData.UpdatedDate)
+}
+
+// Expires returns the date the domain expires and the field it is taken from
+func (r *WhoisRecord) Expires() (time.Time, DateSource) {
+	return pickDate(r.ExpiresDateNormalized, r.ExpiresDate, r.RegistryData.ExpiresDateNormalized, r.RegistryData.ExpiresDate)
+}
@@ -0,0 +1,101 @@
+package whoisapi
+
+import (
+	"testing"
+	"time"
+)
+
+// TestParseDate tests the ParseDate function
+func TestParseDate(t *testing.T) {
+	day := time.Date(2027, 3, 19, 0, 0, 0, 0, time.UTC)
+	moment := time.Date(2027, 3, 19, 14, 30, 5, 0, time.UTC)
+
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr string
+	}{
+		// RFC 3339 and ISO 8601 variants
+		{"2027-03-19T14:30:05Z", moment, ""},
+		{"2027-03-19T14:30:05.000Z", moment, ""},
+		{"2027-03-19T16:30:05+02:00", moment, ""},
+		{"2027-03-19T14:30:05+0000", moment, ""},
+		{"2027-03-19T14:30:05", moment, ""},
+		{"2027-03-19 14:30:05 UTC", moment, ""},
+		{"2027-03-19 14:30:05", moment, ""},
+		{"2027-03-19", day, ""},
+		// registrar and registry specific formats
+		{"19-Mar-2027", day, ""},
+		{"19-MAR-2027", day, ""},
+		{"2027.03.19", day, ""},
+		{"2027.03.19 14:30:05", moment, ""},
+		{"19.3.2027", day, ""},
+		{"19.03.2027 14:30:05", moment, ""},
+		{"2027/03/19", day, ""},
+		{"2027. 03. 19.", day, ""},
+		{"20270319", day, ""},
+		{"19-03-2027", day, ""},
+		{"2027-03-19 14:30:05 (UTC)", moment, ""},
+		{"Fri Mar 19 14:30:05 UTC 2027", moment, ""},
+		{"March 19, 2027", day, ""},
+		{" 19 March 2027 ", day, ""},
+		// zone abbreviations
+		{"2027-03-19 14:30:05 GMT", moment, ""},
+		{"2027-03-19 16:30:05 CEST", moment, ""},
+		{"19-Mar-2027 09:30:05 EST", moment, ""},
+		{"Fri, 19 Mar 2027 07:30:05 PDT", moment, ""},
+		{"2027-03-19 14:30:05 XYZ", time.Time{}, `cannot parse date "2027-03-19 14:30:05 XYZ": unknown time zone "XYZ"`},
+		// localized month names
+		{"19 mars 2027", day, ""},
+		{"19-févr.-2027", time.Date(2027, 2, 19, 0, 0, 0, 0, time.UTC), ""},
+		{"19 de marzo de 2027", day, ""},
+		{"19. März 2027", day, ""},
+		{"19 марта 2027 г.", day, ""},
+		{"19 Dezember 2027", time.Date(2027, 12, 19, 0, 0, 0, 0, time.UTC), ""},
+		{"19 ağustos 2027", time.Date(2027, 8, 19, 0, 0, 0, 0, time.UTC), ""},
+		// errors
+		{"", time.Time{}, "cannot parse date: empty string"},
+		{"soon", time.Time{}, `cannot parse date "soon"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseDate(tt.in)
+			checkErr(t, err, tt.wantErr)
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseDate() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRecordDates tests the Created, Updated and Expires methods
+func TestRecordDates(t *testing.T) {
+	want := time.Date(2027, 3, 19, 0, 0, 0, 0, time.UTC)
+
+	rec := &WhoisRecord{}
+	rec.CreatedDateNormalized = Time(want)
+	rec.CreatedDate = "not used"
+	rec.UpdatedDate = "19-Mar-2027"
+	rec.ExpiresDate = "soon"
+	rec.RegistryData.ExpiresDate = "19.03.2027"
+
+	tests := []struct {
+		name   string
+		date   func() (time.Time, DateSource)
+		want   time.Time
+		source DateSource
+	}{
+		{"created", rec.Created, want, DateSourceNormalized},
+		{"updated", rec.Updated, want, DateSourceRaw},
+		{"expires", rec.Expires, want, DateSourceRegistry},
+		{"empty", (&WhoisRecord{}).Expires, time.Time{}, DateSourceNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, source := tt.date()
+			if !got.Equal(tt.want) || source != tt.source {
+				t.Errorf("got = %v, %v, want %v, %v", got, source, tt.want, tt.source)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package whoisapi
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DecodeWarning is the value that could not be decoded into its field
+type DecodeWarning struct {
+	// Path is the JSON path of the value, e.g. "WhoisRecord.registryData.createdDateNormalized"
+	Path string
+
+	// Raw is the JSON value as it was in the response
+	Raw json.RawMessage
+
+	// Err is the decoding error
+	Err error
+}
+
+// String returns the warning as a string
+func (w DecodeWarning) String() string {
+	return w.Path + ": " + string(w.Raw) + ": " + w.Err.Error()
+}
+
+// DecodeWarnings are the values skipped by lenient decoding, the fields are left zero
+type DecodeWarnings []DecodeWarning
+
+// String returns all warnings separated by "; "
+func (w DecodeWarnings) String() string {
+	s := make([]string, 0, len(w))
+	for _, warning := range w {
+		s = append(s, warning.String())
+	}
+	return strings.Join(s, "; ")
+}
+
+// UnmarshalLenient decodes the JSON document into v like json.Unmarshal, but values that cannot be
+// decoded into their fields are left zero and reported as warnings instead of failing the whole document.
+// Syntax errors are still returned as errors.
+// The document is decoded strictly first, the slower lenient decoding starts from a zero v only if that fails
+func UnmarshalLenient(data []byte, v interface{}) (DecodeWarnings, error) {
+	if err := json.Unmarshal(data, v); err == nil {
+		return nil, nil
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv.Elem().SetZero()
+	}
+
+	var tree interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&tree); err != nil {
+		return nil, err
+	}
+
+	var warnings DecodeWarnings
+	tree, keep := sanitize(tree, reflect.TypeOf(v), "", &warnings)
+	if !keep {
+		return warnings, nil
+	}
+
+	clean, err := json.Marshal(tree)
+	if err != nil {
+		return warnings, err
+	}
+
+	return warnings, json.Unmarshal(clean, v)
+}
+
+var (
+	unmarshalerType     = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// isLeaf reports whether values of the type are decoded as a whole.
+// Models keeping unknown keys in Extra are walked like plain structs
+func isLeaf(t reflect.Type) bool {
+	if hasExtra(t) {
+		return false
+	}
+	if reflect.PointerTo(t).Implements(unmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Array:
+		return false
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.Uint8
+	default:
+		return true
+	}
+}
+
+// sanitize removes the values that cannot be decoded into the type from the JSON tree.
+// It reports whether the value itself can be decoded
+func sanitize(value interface{}, t reflect.Type, path string, warnings *DecodeWarnings) (interface{}, bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if value == nil {
+		return nil, true
+	}
+
+	// skip reports the value that cannot be decoded
+	skip := func(err error) (interface{}, bool) {
+		raw, _ := json.Marshal(value)
+		*warnings = append(*warnings, DecodeWarning{Path: path, Raw: raw, Err: err})
+		return nil, false
+	}
+
+	if isLeaf(t) {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return skip(err)
+		}
+		if err := json.Unmarshal(raw, reflect.New(t).Interface()); err != nil {
+			return skip(err)
+		}
+		return value, true
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return skip(fmt.Errorf("cannot decode %s into %s", jsonKind(value), t))
+		}
+		fields := jsonFields(t)
+		for k, v := range obj {
+			f, ok := fields.lookup(k)
+			if !ok {
+				continue
+			}
+			if clean, keep := sanitize(v, f.typ, joinPath(path, k), warnings); keep {
+				obj[k] = clean
+			} else {
+				delete(obj, k)
+			}
+		}
+		return obj, true
+
+	case reflect.Map:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return skip(fmt.Errorf("cannot decode %s into %s", jsonKind(value), t))
+		}
+		for k, v := range obj {
+			if clean, keep := sanitize(v, t.Elem(), joinPath(path, k), warnings); keep {
+				obj[k] = clean
+			} else {
+				delete(obj, k)
+			}
+		}
+		return obj, true
+
+	default:
+		arr, ok := value.([]interface{})
+		if !ok {
+			return skip(fmt.Errorf("cannot decode %s into %s", jsonKind(value), t))
+		}
+		for i, v := range arr {
+			clean, _ := sanitize(v, t.Elem(), path+"["+strconv.Itoa(i)+"]", warnings)
+			arr[i] = clean
+		}
+		return arr, true
+	}
+}
+
+// joinPath appends the key to the JSON path
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// jsonKind returns the JSON type name of the decoded value
+func jsonKind(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "bool"
+	default:
+		return "null"
+	}
+}
+
+// structField is the type of the struct field and whether it may be absent from JSON
+type structField struct {
+	typ      reflect.Type
+	optional bool
+}
+
+// structFields are the JSON keys of struct fields
+type structFields map[string]structField
+
+// lookup finds the field by the key, case-insensitively like encoding/json does
+func (f structFields) lookup(key string) (structField, bool) {
+	if sf, ok := f[key]; ok {
+		return sf, true
+	}
+	for name, sf := range f {
+		if strings.EqualFold(name, key) {
+			return sf, true
+		}
+	}
+	return structField{}, false
+}
+
+// fieldsCache caches structFields by type
+var fieldsCache sync.Map
+
+// jsonFields returns the JSON keys of the struct including the fields of embedded structs
+func jsonFields(t reflect.Type) structFields {
+	if f, ok := fieldsCache.Load(t); ok {
+		return f.(structFields)
+	}
+
+	fields := make(structFields)
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if sf.Anonymous && name == "" && sf.Type.Kind() == reflect.Struct {
+			for k, v := range jsonFields(sf.Type) {
+				if _, ok := fields[k]; !ok {
+					fields[k] = v
+				}
+			}
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fields[name] = structField{
+			typ:      sf.Type,
+			optional: sf.Type.Kind() == reflect.Pointer || strings.Contains(","+opts+",", ",omitempty,"),
+		}
+	}
+
+	fieldsCache.Store(t, fields)
+	return fields
+}
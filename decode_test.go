@@ -0,0 +1,126 @@
+package whoisapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// oddDatesResponse is the response with values that don't match the model
+const oddDatesResponse = `{"WhoisRecord": {
+	"domainName": "whoisxmlapi.com",
+	"createdDateNormalized": "2013-02-28 00:00:00 UTC",
+	"expiresDateNormalized": "28.02.2027",
+	"registrant": {"organization": "Whois API, Inc.", "country": 1},
+	"nameServers": "ns1.whoisxmlapi.com",
+	"subRecords": [
+		{"domainName": "whoisxmlapi.com", "audit": {"createdDate": "yesterday"}},
+		{"domainName": "whoisxmlapi.com"}
+	],
+	"estimatedDomainAge": "old"
+}}`
+
+// TestUnmarshalLenient tests that undecodable values are skipped with warnings
+func TestUnmarshalLenient(t *testing.T) {
+	var resp whoisApiResponse
+	warnings, err := UnmarshalLenient([]byte(oddDatesResponse), &resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := resp.WhoisRecord
+	if rec == nil || rec.DomainName != "whoisxmlapi.com" || rec.Registrant.Organization != "Whois API, Inc." {
+		t.Fatalf("record got = %+v", rec)
+	}
+	if !time.Time(rec.CreatedDateNormalized).Equal(time.Date(2013, 2, 28, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("CreatedDateNormalized got = %v", time.Time(rec.CreatedDateNormalized))
+	}
+	if rec.ExpiresDateNormalized != emptyTime || rec.EstimatedDomainAge != 0 || rec.Registrant.Country != "" {
+		t.Errorf("skipped fields are not zero: %+v", rec)
+	}
+	if len(rec.SubRecords) != 2 || rec.SubRecords[0].DomainName != "whoisxmlapi.com" {
+		t.Errorf("SubRecords got = %+v", rec.SubRecords)
+	}
+
+	want := map[string]string{
+		"WhoisRecord.expiresDateNormalized":           `"28.02.2027"`,
+		"WhoisRecord.registrant.country":              `1`,
+		"WhoisRecord.nameServers":                     `"ns1.whoisxmlapi.com"`,
+		"WhoisRecord.subRecords[0].audit.createdDate": `"yesterday"`,
+		"WhoisRecord.estimatedDomainAge":              `"old"`,
+	}
+	if len(warnings) != len(want) {
+		t.Errorf("warnings got = %v", warnings)
+	}
+	for _, w := range warnings {
+		if raw, ok := want[w.Path]; !ok || string(w.Raw) != raw || w.Err == nil {
+			t.Errorf("unexpected warning %v", w)
+		}
+	}
+
+	_, err = UnmarshalLenient([]byte(`{"WhoisRecord": {`), &resp)
+	checkErr(t, err, "unexpected EOF")
+}
+
+// TestUnmarshalLenientReset tests that the values set by the failed strict decoding are not kept
+func TestUnmarshalLenientReset(t *testing.T) {
+	resp := whoisApiResponse{WhoisRecord: &WhoisRecord{}}
+	resp.WhoisRecord.DomainName = "example.com"
+	warnings, err := UnmarshalLenient([]byte(`{"WhoisRecord": {"estimatedDomainAge": "old"}}`), &resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 1 || resp.WhoisRecord == nil || resp.WhoisRecord.DomainName != "" {
+		t.Errorf("UnmarshalLenient() got = %+v, %v", resp.WhoisRecord, warnings)
+	}
+}
+
+// BenchmarkUnmarshalLenient measures lenient decoding of valid and odd responses
+func BenchmarkUnmarshalLenient(b *testing.B) {
+	for _, bm := range []struct {
+		name string
+		data string
+	}{
+		{name: "valid", data: driftResponse},
+		{name: "odd values", data: oddDatesResponse},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var resp whoisApiResponse
+				if _, err := UnmarshalLenient([]byte(bm.data), &resp); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// TestDecodingModes tests lenient and strict decoding of Data responses
+func TestDecodingModes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(oddDatesResponse))
+	}))
+	defer server.Close()
+
+	apiURL, _ := url.Parse(server.URL)
+
+	lenient := NewClient(apiKey, ClientParams{HTTPClient: server.Client(), WhoisBaseURL: apiURL})
+	rec, resp, err := lenient.Data(context.Background(), "whoisxmlapi.com")
+	if err != nil || rec == nil {
+		t.Fatalf("Data() got = %v, %v", rec, err)
+	}
+	if len(resp.DecodeWarnings) != 5 {
+		t.Errorf("DecodeWarnings got = %v", resp.DecodeWarnings)
+	}
+
+	strict := NewClient(apiKey, ClientParams{HTTPClient: server.Client(), WhoisBaseURL: apiURL, StrictDecoding: true})
+	rec, _, err = strict.Data(context.Background(), "whoisxmlapi.com")
+	checkErr(t, err, `cannot parse response: parsing time "28.02.2027" as "2006-01-02 15:04:05 MST": cannot parse "28.02.2027" as "2006"`)
+	if rec != nil {
+		t.Errorf("Data() got = %v, want nil", rec)
+	}
+}
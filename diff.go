@@ -0,0 +1,290 @@
+package whoisapi
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ChangeKind is the kind of difference between two Whois records
+type ChangeKind string
+
+const (
+	// ChangeAdded means the value was empty in the old record and is set in the new one
+	ChangeAdded ChangeKind = "added"
+
+	// ChangeRemoved means the value was set in the old record and is empty in the new one
+	ChangeRemoved ChangeKind = "removed"
+
+	// ChangeModified means the value is set in both records but differs
+	ChangeModified ChangeKind = "modified"
+)
+
+// Change is a single field-level difference between two Whois records
+type Change struct {
+	// Path is the JSON path of the changed field, e.g. "registrant.email" or "subRecords[0].status"
+	Path string `json:"path"`
+
+	// Kind is the kind of the change
+	Kind ChangeKind `json:"kind"`
+
+	// Old is the value in the old record
+	Old string `json:"old,omitempty"`
+
+	// New is the value in the new record
+	New string `json:"new,omitempty"`
+}
+
+// String returns the change in a human-readable form
+func (c Change) String() string {
+	switch c.Kind {
+	case ChangeAdded:
+		return c.Path + ": added " + strconv.Quote(c.New)
+	case ChangeRemoved:
+		return c.Path + ": removed " + strconv.Quote(c.Old)
+	default:
+		return c.Path + ": " + strconv.Quote(c.Old) + " -> " + strconv.Quote(c.New)
+	}
+}
+
+// DiffOption changes the set of fields compared by Diff
+type DiffOption func(o *diffOptions)
+
+// diffOptions holds the Diff settings
+type diffOptions struct {
+	audit    bool
+	rawTexts bool
+}
+
+// DiffIncludeAudit makes Diff compare the fields that change with every collection:
+// Audit dates and EstimatedDomainAge
+func DiffIncludeAudit() DiffOption {
+	return func(o *diffOptions) {
+		o.audit = true
+	}
+}
+
+// DiffIncludeRawTexts makes Diff compare raw texts, headers, footers, stripped texts and unparsable parts
+func DiffIncludeRawTexts() DiffOption {
+	return func(o *diffOptions) {
+		o.rawTexts = true
+	}
+}
+
+// Diff returns the field-level changes between the old and the new Whois records.
+// Name servers and IP addresses are compared as sets and Status is compared token by token,
+// so reordering is not reported. A nil record is treated as an empty one
+func Diff(old, new *WhoisRecord, opts ...DiffOption) []Change {
+	var o diffOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if old == nil {
+		old = &WhoisRecord{}
+	}
+	if new == nil {
+		new = &WhoisRecord{}
+	}
+
+	d := differ{opts: o}
+	d.record("", old, new)
+
+	return d.changes
+}
+
+// differ accumulates changes between two records
+type differ struct {
+	opts    diffOptions
+	changes []Change
+}
+
+// join returns the JSON path of the field within the prefix
+func join(prefix, field string) string {
+	if prefix == "" {
+		return field
+	}
+	return prefix + "." + field
+}
+
+// str adds a change if string values differ
+func (d *differ) str(path, old, new string) {
+	if old == new {
+		return
+	}
+
+	c := Change{Path: path, Kind: ChangeModified, Old: old, New: new}
+	switch {
+	case old == "":
+		c.Kind = ChangeAdded
+	case new == "":
+		c.Kind = ChangeRemoved
+	}
+
+	d.changes = append(d.changes, c)
+}
+
+// date adds a change if time values differ
+func (d *differ) date(path string, old, new Time) {
+	d.str(path, formatTime(old), formatTime(new))
+}
+
+// set adds a change for every element present in only one of the sets
+func (d *differ) set(path string, old, new []string, fold bool) {
+	oldSet := toSet(old, fold)
+	newSet := toSet(new, fold)
+
+	for _, v := range sortedKeys(oldSet) {
+		if _, ok := newSet[v]; !ok {
+			d.changes = append(d.changes, Change{Path: path, Kind: ChangeRemoved, Old: v})
+		}
+	}
+	for _, v := range sortedKeys(newSet) {
+		if _, ok := oldSet[v]; !ok {
+			d.changes = append(d.changes, Change{Path: path, Kind: ChangeAdded, New: v})
+		}
+	}
+}
+
+// record compares two Whois records
+func (d *differ) record(prefix string, old, new *WhoisRecord) {
+	d.base(prefix, &old.baseWhoisRecord, &new.baseWhoisRecord)
+
+	d.base(join(prefix, "registryData"), &old.RegistryData.baseWhoisRecord, &new.RegistryData.baseWhoisRecord)
+	d.str(join(prefix, "registryData.whoisServer"), old.RegistryData.WhoisServer, new.RegistryData.WhoisServer)
+	d.str(join(prefix, "registryData.referralURL"), old.RegistryData.ReferralURL, new.RegistryData.ReferralURL)
+
+	d.str(join(prefix, "contactEmail"), old.ContactEmail, new.ContactEmail)
+	d.str(join(prefix, "domainAvailability"), old.DomainAvailability, new.DomainAvailability)
+	d.str(join(prefix, "domainNameExt"), old.DomainNameExt, new.DomainNameExt)
+	if d.opts.audit {
+		d.str(join(prefix, "estimatedDomainAge"),
+			strconv.Itoa(old.EstimatedDomainAge), strconv.Itoa(new.EstimatedDomainAge))
+	}
+	d.set(join(prefix, "ips"), old.Ips, new.Ips, false)
+	d.str(join(prefix, "custom1FieldName"), old.Custom1FieldName, new.Custom1FieldName)
+	d.str(join(prefix, "custom1FieldValue"), old.Custom1FieldValue, new.Custom1FieldValue)
+	d.str(join(prefix, "custom2FieldName"), old.Custom2FieldName, new.Custom2FieldName)
+	d.str(join(prefix, "custom2FieldValue"), old.Custom2FieldValue, new.Custom2FieldValue)
+	d.str(join(prefix, "custom3FieldName"), old.Custom3FieldName, new.Custom3FieldName)
+	d.str(join(prefix, "custom3FieldValue"), old.Custom3FieldValue, new.Custom3FieldValue)
+	d.str(join(prefix, "dataError"), old.DataError, new.DataError)
+	d.contact(join(prefix, "privateWhoisProxy"), orEmpty(old.PrivateWhoisProxy), orEmpty(new.PrivateWhoisProxy))
+
+	for i := 0; i < len(old.SubRecords) || i < len(new.SubRecords); i++ {
+		path := join(prefix, "subRecords["+strconv.Itoa(i)+"]")
+		switch {
+		case i >= len(new.SubRecords):
+			d.changes = append(d.changes, Change{Path: path, Kind: ChangeRemoved, Old: old.SubRecords[i].DomainName})
+		case i >= len(old.SubRecords):
+			d.changes = append(d.changes, Change{Path: path, Kind: ChangeAdded, New: new.SubRecords[i].DomainName})
+		default:
+			d.record(path, &old.SubRecords[i], &new.SubRecords[i])
+		}
+	}
+}
+
+// base compares the common part of Whois records
+func (d *differ) base(prefix string, old, new *baseWhoisRecord) {
+	d.str(join(prefix, "domainName"), old.DomainName, new.DomainName)
+	d.date(join(prefix, "createdDateNormalized"), old.CreatedDateNormalized, new.CreatedDateNormalized)
+	d.date(join(prefix, "updatedDateNormalized"), old.UpdatedDateNormalized, new.UpdatedDateNormalized)
+	d.date(join(prefix, "expiresDateNormalized"), old.ExpiresDateNormalized, new.ExpiresDateNormalized)
+	d.str(join(prefix, "createdDate"), old.CreatedDate, new.CreatedDate)
+	d.str(join(prefix, "updatedDate"), old.UpdatedDate, new.UpdatedDate)
+	d.str(join(prefix, "expiresDate"), old.ExpiresDate, new.ExpiresDate)
+
+	if d.opts.audit {
+		d.date(join(prefix, "audit.createdDate"), old.Audit.CreatedDate, new.Audit.CreatedDate)
+		d.date(join(prefix, "audit.updatedDate"), old.Audit.UpdatedDate, new.Audit.UpdatedDate)
+	}
+
+	d.set(join(prefix, "nameServers.hostNames"), old.NameServers.HostNames, new.NameServers.HostNames, true)
+	d.set(join(prefix, "nameServers.ips"), old.NameServers.Ips, new.NameServers.Ips, false)
+
+	d.str(join(prefix, "registrarName"), old.RegistrarName, new.RegistrarName)
+	d.str(join(prefix, "registrarIANAID"), old.RegistrarIANAID, new.RegistrarIANAID)
+	d.set(join(prefix, "status"), strings.Fields(old.Status), strings.Fields(new.Status), false)
+	d.str(join(prefix, "parseCode"), strconv.Itoa(old.ParseCode), strconv.Itoa(new.ParseCode))
+
+	d.contact(join(prefix, "registrant"), &old.Registrant, &new.Registrant)
+	d.contact(join(prefix, "administrativeContact"), &old.AdministrativeContact, &new.AdministrativeContact)
+	d.contact(join(prefix, "technicalContact"), &old.TechnicalContact, &new.TechnicalContact)
+	d.contact(join(prefix, "billingContact"), &old.BillingContact, &new.BillingContact)
+	d.contact(join(prefix, "zoneContact"), &old.ZoneContact, &new.ZoneContact)
+
+	if d.opts.rawTexts {
+		d.str(join(prefix, "nameServers.rawText"), old.NameServers.RawText, new.NameServers.RawText)
+		d.str(join(prefix, "rawText"), old.RawText, new.RawText)
+		d.str(join(prefix, "header"), old.Header, new.Header)
+		d.str(join(prefix, "footer"), old.Footer, new.Footer)
+		d.str(join(prefix, "strippedText"), old.StrippedText, new.StrippedText)
+	}
+}
+
+// contact compares two contacts
+func (d *differ) contact(prefix string, old, new *Contact) {
+	d.str(join(prefix, "name"), old.Name, new.Name)
+	d.str(join(prefix, "organization"), old.Organization, new.Organization)
+	d.str(join(prefix, "street1"), old.Street1, new.Street1)
+	d.str(join(prefix, "street2"), old.Street2, new.Street2)
+	d.str(join(prefix, "street3"), old.Street3, new.Street3)
+	d.str(join(prefix, "street4"), old.Street4, new.Street4)
+	d.str(join(prefix, "city"), old.City, new.City)
+	d.str(join(prefix, "state"), old.State, new.State)
+	d.str(join(prefix, "postalCode"), old.PostalCode, new.PostalCode)
+	d.str(join(prefix, "country"), old.Country, new.Country)
+	d.str(join(prefix, "countryCode"), old.CountryCode, new.CountryCode)
+	d.str(join(prefix, "email"), old.Email, new.Email)
+	d.str(join(prefix, "telephone"), old.Telephone, new.Telephone)
+	d.str(join(prefix, "telephoneExt"), old.TelephoneExt, new.TelephoneExt)
+	d.str(join(prefix, "fax"), old.Fax, new.Fax)
+	d.str(join(prefix, "faxExt"), old.FaxExt, new.FaxExt)
+
+	if d.opts.rawTexts {
+		d.str(join(prefix, "rawText"), old.RawText, new.RawText)
+		d.str(join(prefix, "unparsable"), old.Unparsable, new.Unparsable)
+	}
+}
+
+// orEmpty returns the contact or an empty one if it's nil, so missing proxy data is compared as empty
+func orEmpty(c *Contact) *Contact {
+	if c == nil {
+		return &Contact{}
+	}
+	return c
+}
+
+// formatTime returns the time in the Whois API format or an empty string for the zero value
+func formatTime(t Time) string {
+	if t == emptyTime {
+		return ""
+	}
+	return time.Time(t).Format("2006-01-02 15:04:05 MST")
+}
+
+// toSet returns the set of non-empty values, optionally lower-cased
+func toSet(values []string, fold bool) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if fold {
+			v = strings.ToLower(v)
+		}
+		if v != "" {
+			set[v] = struct{}{}
+		}
+	}
+	return set
+}
+
+// sortedKeys returns the set elements in a stable order
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,159 @@
+package whoisapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestDiff tests the Diff function
+func TestDiff(t *testing.T) {
+
+	base := func() *WhoisRecord {
+		rec := &WhoisRecord{}
+		rec.DomainName = "whoisxmlapi.com"
+		rec.ExpiresDateNormalized = Time(time.Date(2027, 3, 19, 21, 47, 17, 0, time.UTC))
+		rec.Status = "clientTransferProhibited clientUpdateProhibited"
+		rec.NameServers.HostNames = []string{"ns1.example.com", "ns2.example.com"}
+		rec.Registrant.Email = "owner@example.com"
+		rec.Audit.UpdatedDate = Time(time.Date(2022, 4, 7, 7, 42, 54, 0, time.UTC))
+		rec.RawText = "raw"
+		rec.EstimatedDomainAge = 100
+		return rec
+	}
+
+	tests := []struct {
+		name   string
+		modify func(rec *WhoisRecord)
+		opts   []DiffOption
+		want   []Change
+	}{
+		{
+			name:   "no changes",
+			modify: func(rec *WhoisRecord) {},
+			want:   nil,
+		},
+		{
+			name: "reordered name servers and status",
+			modify: func(rec *WhoisRecord) {
+				rec.NameServers.HostNames = []string{"NS2.EXAMPLE.COM", "ns1.example.com"}
+				rec.Status = "clientUpdateProhibited clientTransferProhibited"
+			},
+			want: nil,
+		},
+		{
+			name: "collection fields are ignored",
+			modify: func(rec *WhoisRecord) {
+				rec.Audit.UpdatedDate = Time(time.Date(2022, 5, 7, 7, 42, 54, 0, time.UTC))
+				rec.RawText = "new raw"
+				rec.EstimatedDomainAge = 130
+			},
+			want: nil,
+		},
+		{
+			name: "collection fields are included",
+			modify: func(rec *WhoisRecord) {
+				rec.Audit.UpdatedDate = Time(time.Date(2022, 5, 7, 7, 42, 54, 0, time.UTC))
+				rec.RawText = "new raw"
+			},
+			opts: []DiffOption{DiffIncludeAudit(), DiffIncludeRawTexts()},
+			want: []Change{
+				{Path: "audit.updatedDate", Kind: ChangeModified, Old: "2022-04-07 07:42:54 UTC", New: "2022-05-07 07:42:54 UTC"},
+				{Path: "rawText", Kind: ChangeModified, Old: "raw", New: "new raw"},
+			},
+		},
+		{
+			name: "fields changed",
+			modify: func(rec *WhoisRecord) {
+				rec.ExpiresDateNormalized = Time(time.Date(2028, 3, 19, 21, 47, 17, 0, time.UTC))
+				rec.NameServers.HostNames = []string{"ns1.example.com", "ns3.example.com"}
+				rec.Status = "clientTransferProhibited"
+				rec.Registrant.Email = ""
+				rec.RegistryData.WhoisServer = "whois.verisign-grs.com"
+				rec.SubRecords = []WhoisRecord{{}}
+				rec.SubRecords[0].DomainName = "sub.whoisxmlapi.com"
+			},
+			want: []Change{
+				{Path: "expiresDateNormalized", Kind: ChangeModified, Old: "2027-03-19 21:47:17 UTC", New: "2028-03-19 21:47:17 UTC"},
+				{Path: "nameServers.hostNames", Kind: ChangeRemoved, Old: "ns2.example.com"},
+				{Path: "nameServers.hostNames", Kind: ChangeAdded, New: "ns3.example.com"},
+				{Path: "status", Kind: ChangeRemoved, Old: "clientUpdateProhibited"},
+				{Path: "registrant.email", Kind: ChangeRemoved, Old: "owner@example.com"},
+				{Path: "registryData.whoisServer", Kind: ChangeAdded, New: "whois.verisign-grs.com"},
+				{Path: "subRecords[0]", Kind: ChangeAdded, New: "sub.whoisxmlapi.com"},
+			},
+		},
+		{
+			name: "empty proxy data",
+			modify: func(rec *WhoisRecord) {
+				rec.PrivateWhoisProxy = &Contact{}
+			},
+			want: nil,
+		},
+		{
+			name: "proxy data added",
+			modify: func(rec *WhoisRecord) {
+				rec.PrivateWhoisProxy = &Contact{Organization: "Domains By Proxy, LLC", Email: "whoisxmlapi.com@domainsbyproxy.com"}
+			},
+			want: []Change{
+				{Path: "privateWhoisProxy.organization", Kind: ChangeAdded, New: "Domains By Proxy, LLC"},
+				{Path: "privateWhoisProxy.email", Kind: ChangeAdded, New: "whoisxmlapi.com@domainsbyproxy.com"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old, new := base(), base()
+			tt.modify(new)
+
+			got := Diff(old, new, tt.opts...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() got  = %v", got)
+				t.Errorf("Diff() want = %v", tt.want)
+			}
+		})
+	}
+}
+
+// TestChange tests the string and JSON representation of Change
+func TestChange(t *testing.T) {
+	tests := []struct {
+		name   string
+		change Change
+		str    string
+		json   string
+	}{
+		{
+			name:   "added",
+			change: Change{Path: "status", Kind: ChangeAdded, New: "clientHold"},
+			str:    `status: added "clientHold"`,
+			json:   `{"path":"status","kind":"added","new":"clientHold"}`,
+		},
+		{
+			name:   "removed",
+			change: Change{Path: "registrant.email", Kind: ChangeRemoved, Old: "a@b.c"},
+			str:    `registrant.email: removed "a@b.c"`,
+			json:   `{"path":"registrant.email","kind":"removed","old":"a@b.c"}`,
+		},
+		{
+			name:   "modified",
+			change: Change{Path: "registrarName", Kind: ChangeModified, Old: "A", New: "B"},
+			str:    `registrarName: "A" -> "B"`,
+			json:   `{"path":"registrarName","kind":"modified","old":"A","new":"B"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.change.String(); got != tt.str {
+				t.Errorf("String() got = %v, want %v", got, tt.str)
+			}
+
+			bb, err := json.Marshal(tt.change)
+			checkErr(t, err, "")
+			if string(bb) != tt.json {
+				t.Errorf("json.Marshal() got = %v, want %v", string(bb), tt.json)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package whoisapi
+
+import (
+	"net/netip"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+)
+
+const (
+	// maxDomainLength is the maximum length of the domain name without the trailing dot
+	maxDomainLength = 253
+
+	// maxLabelLength is the maximum length of the domain name label
+	maxLabelLength = 63
+)
+
+// labelSeparators are the dots that separate IDN labels
+var labelSeparators = strings.NewReplacer("。", ".", "．", ".", "｡", ".")
+
+// NormalizeDomain turns the user input into the name to look up.
+// It strips the scheme, the path, the port and the user@ part of URLs and emails,
+// removes the trailing dot, lowercases the name and converts IDN labels to A-labels.
+// IP addresses are returned in their canonical form.
+// It returns ArgError if the name is empty or a label has an invalid length or characters
+func NormalizeDomain(name string) (string, error) {
+	host := strings.TrimSpace(name)
+
+	if i := strings.Index(host, "://"); i >= 0 {
+		host = host[i+3:]
+	}
+	if i := strings.IndexAny(host, "/?#"); i >= 0 {
+		host = host[:i]
+	}
+	if i := strings.LastIndex(host, "@"); i >= 0 {
+		host = host[i+1:]
+	}
+
+	if strings.HasPrefix(host, "[") {
+		if i := strings.Index(host, "]"); i >= 0 {
+			host = host[1:i]
+		}
+	} else if strings.Count(host, ":") == 1 {
+		host = host[:strings.Index(host, ":")]
+	}
+
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return addr.WithZone("").String(), nil
+	}
+
+	host = strings.TrimSuffix(labelSeparators.Replace(host), ".")
+	if host == "" {
+		return "", &ArgError{"name", "cannot be empty"}
+	}
+
+	labels := strings.Split(strings.ToLower(host), ".")
+	for i, label := range labels {
+		ascii, err := toASCII(label)
+		if err != nil {
+			return "", err
+		}
+		labels[i] = ascii
+	}
+
+	domain := strings.Join(labels, ".")
+	if len(domain) > maxDomainLength {
+		return "", &ArgError{"name", "is longer than 253 characters"}
+	}
+
+	return domain, nil
+}
+
+// KeepDomain is the DomainNormalizer that returns the name unchanged
+func KeepDomain(name string) (string, error) {
+	return name, nil
+}
+
+// toASCII validates the lowercase label and converts it to the A-label if it's an IDN.
+// IDN labels are mapped and normalized with UTS #46 before the punycode encoding,
+// so decomposed and fullwidth forms give the same A-label as the composed ones
+func toASCII(label string) (string, error) {
+	if label == "" {
+		return "", &ArgError{"name", "has an empty label"}
+	}
+	if strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+		return "", &ArgError{"name", `label "` + label + `" cannot start or end with a hyphen`}
+	}
+
+	for _, r := range label {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+		case r >= utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)):
+		default:
+			return "", &ArgError{"name", `label "` + label + `" contains invalid character ` + quoteRune(r)}
+		}
+	}
+
+	// the mapping may turn the label into several ones, e.g. "⒈" into "1."
+	ascii, err := idna.Lookup.ToASCII(label)
+	if err != nil || ascii == "" || strings.Contains(ascii, ".") {
+		return "", &ArgError{"name", `label "` + label + `" is not a valid IDN`}
+	}
+
+	if len(ascii) > maxLabelLength {
+		return "", &ArgError{"name", `label "` + label + `" is longer than 63 characters`}
+	}
+
+	return ascii, nil
+}
+
+// quoteRune returns the rune in single quotes
+func quoteRune(r rune) string {
+	return "'" + string(r) + "'"
+}
@@ -0,0 +1,295 @@
+package whoisapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// extraType is the type of the Extra fields of the models
+var extraType = reflect.TypeOf(map[string]json.RawMessage(nil))
+
+// hasExtra reports whether the struct type keeps unknown keys in its Extra field
+func hasExtra(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	f, ok := t.FieldByName("Extra")
+	return ok && f.Type == extraType
+}
+
+// unmarshalExtra returns the keys of the JSON object that are not fields of the struct type
+func unmarshalExtra(b []byte, t reflect.Type) (map[string]json.RawMessage, error) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return nil, err
+	}
+
+	fields := jsonFields(t)
+	var extra map[string]json.RawMessage
+	for k, v := range obj {
+		if _, ok := fields.lookup(k); ok {
+			continue
+		}
+		if extra == nil {
+			extra = make(map[string]json.RawMessage)
+		}
+		extra[k] = v
+	}
+	return extra, nil
+}
+
+// marshalExtra appends the extra keys to the JSON object, keys that are already in the object are skipped
+func marshalExtra(b []byte, extra map[string]json.RawMessage, t reflect.Type) ([]byte, error) {
+	if len(extra) == 0 {
+		return b, nil
+	}
+
+	keys := make([]string, 0, len(extra))
+	fields := jsonFields(t)
+	for k := range extra {
+		if _, ok := fields.lookup(k); !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	obj := bytes.TrimSuffix(bytes.TrimSpace(b), []byte("}"))
+	var buf bytes.Buffer
+	buf.Write(obj)
+	for i, k := range keys {
+		if i > 0 || !bytes.HasSuffix(obj, []byte("{")) {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(extra[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes the contact keeping unknown keys in Extra
+func (c *Contact) UnmarshalJSON(b []byte) error {
+	type contact Contact
+	var v contact
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	extra, err := unmarshalExtra(b, reflect.TypeOf(v))
+	if err != nil {
+		return err
+	}
+	*c = Contact(v)
+	c.Extra = extra
+	return nil
+}
+
+// MarshalJSON encodes the contact including the keys from Extra
+func (c Contact) MarshalJSON() ([]byte, error) {
+	type contact Contact
+	b, err := json.Marshal(contact(c))
+	if err != nil {
+		return nil, err
+	}
+	return marshalExtra(b, c.Extra, reflect.TypeOf(c))
+}
+
+// UnmarshalJSON decodes the registry data keeping unknown keys in Extra
+func (r *RegistryData) UnmarshalJSON(b []byte) error {
+	type registryData RegistryData
+	var v registryData
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	extra, err := unmarshalExtra(b, reflect.TypeOf(v))
+	if err != nil {
+		return err
+	}
+	*r = RegistryData(v)
+	r.Extra = extra
+	return nil
+}
+
+// MarshalJSON encodes the registry data including the keys from Extra
+func (r RegistryData) MarshalJSON() ([]byte, error) {
+	type registryData RegistryData
+	b, err := json.Marshal(registryData(r))
+	if err != nil {
+		return nil, err
+	}
+	return marshalExtra(b, r.Extra, reflect.TypeOf(r))
+}
+
+// UnmarshalJSON decodes the Whois record keeping unknown keys in Extra
+func (r *WhoisRecord) UnmarshalJSON(b []byte) error {
+	type whoisRecord WhoisRecord
+	var v whoisRecord
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	extra, err := unmarshalExtra(b, reflect.TypeOf(v))
+	if err != nil {
+		return err
+	}
+	*r = WhoisRecord(v)
+	r.Extra = extra
+	return nil
+}
+
+// MarshalJSON encodes the Whois record including the keys from Extra
+func (r WhoisRecord) MarshalJSON() ([]byte, error) {
+	type whoisRecord WhoisRecord
+	b, err := json.Marshal(whoisRecord(r))
+	if err != nil {
+		return nil, err
+	}
+	return marshalExtra(b, r.Extra, reflect.TypeOf(r))
+}
+
+// SchemaDrift lists the differences between the keys of a response and the known model
+type SchemaDrift struct {
+	// New are the paths of the keys the model doesn't know, e.g. "WhoisRecord.registrant.telephoneCountry".
+	// Elements of arrays are reported as "subRecords[]"
+	New []string
+
+	// Missing are the paths of the model keys absent from the response that were present
+	// in an earlier response seen by the same SchemaTracker.
+	// Optional fields, pointers and fields tagged omitempty, are not reported
+	Missing []string
+}
+
+// IsEmpty reports whether the response matches the model
+func (d *SchemaDrift) IsEmpty() bool {
+	return d == nil || len(d.New) == 0 && len(d.Missing) == 0
+}
+
+// String returns the drift as a string
+func (d *SchemaDrift) String() string {
+	if d.IsEmpty() {
+		return "no schema drift"
+	}
+	var s []string
+	if len(d.New) > 0 {
+		s = append(s, "new keys: "+strings.Join(d.New, ", "))
+	}
+	if len(d.Missing) > 0 {
+		s = append(s, "missing keys: "+strings.Join(d.Missing, ", "))
+	}
+	return strings.Join(s, "; ")
+}
+
+// DetectSchemaDrift compares the keys of the JSON document with the fields of the model v,
+// e.g. &WhoisRecord{}, and returns the new keys.
+// Missing keys need earlier responses and are reported only by SchemaTracker
+func DetectSchemaDrift(data []byte, v interface{}) (*SchemaDrift, error) {
+	return NewSchemaTracker().Detect(data, v)
+}
+
+// SchemaTracker detects schema drift of a sequence of responses.
+// It remembers the model keys of the responses it has seen and reports as missing only the keys
+// that were present before, so the fields the API always omits for a TLD are not reported
+type SchemaTracker struct {
+	mu   sync.Mutex
+	seen map[string]struct{}
+}
+
+// NewSchemaTracker returns a SchemaTracker that hasn't seen any response
+func NewSchemaTracker() *SchemaTracker {
+	return &SchemaTracker{seen: make(map[string]struct{})}
+}
+
+// Detect compares the keys of the JSON document with the fields of the model v
+// and with the keys of the earlier responses and returns the keys that are new or missing
+func (s *SchemaTracker) Detect(data []byte, v interface{}) (*SchemaDrift, error) {
+	var tree interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&tree); err != nil {
+		return nil, err
+	}
+
+	w := driftWalker{
+		newKeys:     make(map[string]struct{}),
+		missingKeys: make(map[string]struct{}),
+		present:     make(map[string]struct{}),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w.seen = s.seen
+	w.walk(tree, reflect.TypeOf(v), "")
+	for k := range w.present {
+		s.seen[k] = struct{}{}
+	}
+
+	return &SchemaDrift{New: sortedKeys(w.newKeys), Missing: sortedKeys(w.missingKeys)}, nil
+}
+
+// driftWalker collects the new, missing and present keys of a response.
+// Paths in seen and present are lowercased since keys are matched case-insensitively
+type driftWalker struct {
+	seen        map[string]struct{}
+	present     map[string]struct{}
+	newKeys     map[string]struct{}
+	missingKeys map[string]struct{}
+}
+
+// walk walks the JSON tree along the type and collects new, missing and present keys
+func (w *driftWalker) walk(value interface{}, t reflect.Type, path string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if value == nil || isLeaf(t) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return
+		}
+		fields := jsonFields(t)
+		present := make(map[string]bool, len(obj))
+		for k, v := range obj {
+			present[strings.ToLower(k)] = true
+			f, ok := fields.lookup(k)
+			if !ok {
+				w.newKeys[joinPath(path, k)] = struct{}{}
+				continue
+			}
+			w.present[strings.ToLower(joinPath(path, k))] = struct{}{}
+			w.walk(v, f.typ, joinPath(path, k))
+		}
+		for k, f := range fields {
+			if f.optional || present[strings.ToLower(k)] {
+				continue
+			}
+			if _, ok := w.seen[strings.ToLower(joinPath(path, k))]; ok {
+				w.missingKeys[joinPath(path, k)] = struct{}{}
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		arr, ok := value.([]interface{})
+		if !ok {
+			return
+		}
+		for _, v := range arr {
+			w.walk(v, t.Elem(), path+"[]")
+		}
+	}
+}
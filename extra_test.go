@@ -0,0 +1,262 @@
+package whoisapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+// driftResponse is the response with keys unknown to the model
+const driftResponse = `{"WhoisRecord": {
+	"domainName": "whoisxmlapi.com",
+	"registrant": {"organization": "Whois API, Inc.", "telephoneCountry": "US"},
+	"registryData": {"domainName": "whoisxmlapi.com", "dnssec": "unsigned"},
+	"subRecords": [{"domainName": "whoisxmlapi.com", "source": "rdap"}],
+	"registrarAbuseContact": {"email":"abuse@godaddy.com","phone":"+1.4805058800"}
+}}`
+
+// driftResponseMissing is the response without keys present in driftResponse
+const driftResponseMissing = `{"WhoisRecord": {
+	"domainName": "whoisxmlapi.net",
+	"registrant": {"name": "Jane Doe"},
+	"registryData": {"registrarName": "GoDaddy.com, LLC"}
+}}`
+
+// TestExtraRoundTrip tests that unknown keys are kept in Extra and encoded back
+func TestExtraRoundTrip(t *testing.T) {
+	var resp whoisApiResponse
+	if err := json.Unmarshal([]byte(driftResponse), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := resp.WhoisRecord
+	tests := []struct {
+		name string
+		got  map[string]json.RawMessage
+		want map[string]json.RawMessage
+	}{
+		{
+			name: "record",
+			got:  rec.Extra,
+			want: map[string]json.RawMessage{
+				"registrarAbuseContact": json.RawMessage(`{"email":"abuse@godaddy.com","phone":"+1.4805058800"}`),
+			},
+		},
+		{
+			name: "contact",
+			got:  rec.Registrant.Extra,
+			want: map[string]json.RawMessage{"telephoneCountry": json.RawMessage(`"US"`)},
+		},
+		{
+			name: "registry data",
+			got:  rec.RegistryData.Extra,
+			want: map[string]json.RawMessage{"dnssec": json.RawMessage(`"unsigned"`)},
+		},
+		{
+			name: "sub-record",
+			got:  rec.SubRecords[0].Extra,
+			want: map[string]json.RawMessage{"source": json.RawMessage(`"rdap"`)},
+		},
+		{
+			name: "known keys only",
+			got:  rec.TechnicalContact.Extra,
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("Extra got = %s, want %s", tt.got, tt.want)
+			}
+		})
+	}
+	if rec.DomainName != "whoisxmlapi.com" || rec.Registrant.Organization != "Whois API, Inc." {
+		t.Errorf("known fields got = %+v", rec)
+	}
+
+	b, err := json.Marshal(rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var again WhoisRecord
+	if err := json.Unmarshal(b, &again); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&again, rec) {
+		t.Errorf("round trip got  = %+v", again)
+		t.Errorf("round trip want = %+v", *rec)
+	}
+}
+
+// TestMarshalExtra tests that Extra doesn't override known keys
+func TestMarshalExtra(t *testing.T) {
+	c := Contact{
+		Name: "Jane Doe",
+		Extra: map[string]json.RawMessage{
+			"name":  json.RawMessage(`"ignored"`),
+			"title": json.RawMessage(`"CEO"`),
+		},
+	}
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("invalid JSON %s: %v", b, err)
+	}
+	if got["name"] != "Jane Doe" || got["title"] != "CEO" {
+		t.Errorf("Marshal() got = %s", b)
+	}
+}
+
+// TestDetectSchemaDrift tests the DetectSchemaDrift function
+func TestDetectSchemaDrift(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		v           interface{}
+		wantNew     []string
+		wantMissing []string
+		wantErr     string
+	}{
+		{
+			name: "new and missing keys",
+			data: `{"organization": "Whois API, Inc.", "Name": "Jane Doe", "title": "CEO"}`,
+			v:    &Contact{},
+			wantNew: []string{
+				"title",
+			},
+			wantMissing: nil,
+		},
+		{
+			name:        "nested objects and arrays",
+			data:        `{"ips": ["1.1.1.1"], "subRecords": [{"source": "rdap"}, {"source": "whois", "nameServers": {"ttl": 3600}}]}`,
+			v:           &WhoisRecord{},
+			wantNew:     []string{"subRecords[].nameServers.ttl", "subRecords[].source"},
+			wantMissing: nil,
+		},
+		{
+			name:    "syntax error",
+			data:    `{"name": `,
+			v:       &Contact{},
+			wantErr: "unexpected EOF",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DetectSchemaDrift([]byte(tt.data), tt.v)
+			checkErr(t, err, tt.wantErr)
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(got.New, tt.wantNew) && len(got.New)+len(tt.wantNew) > 0 {
+				t.Errorf("New got = %v, want %v", got.New, tt.wantNew)
+			}
+			if !reflect.DeepEqual(got.Missing, tt.wantMissing) && len(got.Missing)+len(tt.wantMissing) > 0 {
+				t.Errorf("Missing got = %v, want %v", got.Missing, tt.wantMissing)
+			}
+		})
+	}
+}
+
+// TestSchemaTracker tests that only the keys of earlier responses are reported as missing
+func TestSchemaTracker(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		wantMissing []string
+	}{
+		{
+			name:        "first response",
+			data:        `{"name": "Jane Doe", "email": "jane@example.com", "telephone": "+1.5555550100"}`,
+			wantMissing: nil,
+		},
+		{
+			name:        "keys of the first response absent",
+			data:        `{"Name": "John Doe"}`,
+			wantMissing: []string{"email", "telephone"},
+		},
+		{
+			name:        "keys present again",
+			data:        `{"name": "Jane Doe", "email": "jane@example.com", "telephone": "+1.5555550100"}`,
+			wantMissing: nil,
+		},
+	}
+
+	tracker := NewSchemaTracker()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tracker.Detect([]byte(tt.data), &Contact{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got.Missing, tt.wantMissing) && len(got.Missing)+len(tt.wantMissing) > 0 {
+				t.Errorf("Missing got = %v, want %v", got.Missing, tt.wantMissing)
+			}
+		})
+	}
+}
+
+// TestClientSchemaDrift tests the DetectSchemaDrift client parameter
+func TestClientSchemaDrift(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Query().Get("domainName") == "whoisxmlapi.net" {
+			_, _ = w.Write([]byte(driftResponseMissing))
+			return
+		}
+		_, _ = w.Write([]byte(driftResponse))
+	}))
+	defer server.Close()
+
+	apiURL, _ := url.Parse(server.URL)
+
+	for _, detect := range []bool{false, true} {
+		client := NewClient(apiKey, ClientParams{HTTPClient: server.Client(), WhoisBaseURL: apiURL, DetectSchemaDrift: detect})
+		rec, resp, err := client.Data(context.Background(), "whoisxmlapi.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rec.RegistryData.Extra["dnssec"] == nil {
+			t.Errorf("RegistryData.Extra got = %s", rec.RegistryData.Extra)
+		}
+
+		if !detect {
+			if resp.SchemaDrift != nil {
+				t.Errorf("SchemaDrift got = %v, want nil", resp.SchemaDrift)
+			}
+			continue
+		}
+		want := []string{
+			"WhoisRecord.registrant.telephoneCountry",
+			"WhoisRecord.registrarAbuseContact",
+			"WhoisRecord.registryData.dnssec",
+			"WhoisRecord.subRecords[].source",
+		}
+		if resp.SchemaDrift.IsEmpty() || !reflect.DeepEqual(resp.SchemaDrift.New, want) {
+			t.Errorf("SchemaDrift.New got = %v, want %v", resp.SchemaDrift.New, want)
+		}
+		if len(resp.SchemaDrift.Missing) != 0 {
+			t.Errorf("SchemaDrift.Missing got = %v, want []", resp.SchemaDrift.Missing)
+		}
+
+		_, resp, err = client.Data(context.Background(), "whoisxmlapi.net")
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantMissing := []string{
+			"WhoisRecord.registrant.organization",
+			"WhoisRecord.registryData.domainName",
+			"WhoisRecord.subRecords",
+		}
+		if !reflect.DeepEqual(resp.SchemaDrift.Missing, wantMissing) {
+			t.Errorf("SchemaDrift.Missing got = %v, want %v", resp.SchemaDrift.Missing, wantMissing)
+		}
+	}
+}
@@ -0,0 +1,466 @@
+package whoisapi
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// ErrPrefixNotCovered is returned by PrefixData when no netblock of the response contains the whole prefix
+var ErrPrefixNotCovered = errors.New("no netblock contains the whole prefix")
+
+// RIR is the Regional Internet Registry
+type RIR string
+
+const (
+	// RIRUnknown is used when the registry cannot be detected
+	RIRUnknown RIR = ""
+
+	// RIRARIN is the American Registry for Internet Numbers
+	RIRARIN RIR = "ARIN"
+
+	// RIRRIPE is the Réseaux IP Européens Network Coordination Centre
+	RIRRIPE RIR = "RIPE"
+
+	// RIRAPNIC is the Asia-Pacific Network Information Centre
+	RIRAPNIC RIR = "APNIC"
+
+	// RIRLACNIC is the Latin America and Caribbean Network Information Centre
+	RIRLACNIC RIR = "LACNIC"
+
+	// RIRAFRINIC is the African Network Information Centre
+	RIRAFRINIC RIR = "AFRINIC"
+)
+
+// IPRange is the inclusive range of IP addresses
+type IPRange struct {
+	// From is the first address of the range
+	From netip.Addr
+
+	// To is the last address of the range
+	To netip.Addr
+}
+
+// Contains reports whether the range contains the address
+func (r IPRange) Contains(ip netip.Addr) bool {
+	return r.From.IsValid() && r.From.Compare(ip) <= 0 && ip.Compare(r.To) <= 0
+}
+
+// ContainsPrefix reports whether the range contains all addresses of the prefix
+func (r IPRange) ContainsPrefix(prefix netip.Prefix) bool {
+	p := prefixRange(prefix)
+	return r.Contains(p.From) && r.Contains(p.To)
+}
+
+// IsValid reports whether the range is set
+func (r IPRange) IsValid() bool {
+	return r.From.IsValid() && r.To.IsValid() && r.From.BitLen() == r.To.BitLen() && r.From.Compare(r.To) <= 0
+}
+
+// String returns the range in the "From - To" form
+func (r IPRange) String() string {
+	if !r.IsValid() {
+		return ""
+	}
+	return r.From.String() + " - " + r.To.String()
+}
+
+// size returns the number of addresses in the range
+func (r IPRange) size() *big.Int {
+	from := new(big.Int).SetBytes(r.From.AsSlice())
+	to := new(big.Int).SetBytes(r.To.AsSlice())
+	return to.Sub(to, from)
+}
+
+// IPWhoisRecord is the Whois record of the IP address
+type IPWhoisRecord struct {
+	// IP is the IP address looked up, the first address of Prefix for PrefixData
+	IP netip.Addr
+
+	// Prefix is the CIDR prefix looked up by PrefixData, it's not set by IPData
+	Prefix netip.Prefix
+
+	// Range is the most specific netblock containing the IP or the whole Prefix
+	Range IPRange
+
+	// Prefixes are the CIDR prefixes of the netblock
+	Prefixes []netip.Prefix
+
+	// ASN is the autonomous system number originating the netblock, 0 if unknown
+	ASN uint32
+
+	// NetworkName is the name of the netblock
+	NetworkName string
+
+	// RIR is the registry of the netblock
+	RIR RIR
+
+	// Registrant is the organization the netblock is assigned to
+	Registrant Contact
+
+	// AbuseContacts are the contacts for abuse reports
+	AbuseContacts []Contact
+
+	// Record is the Whois record the fields are parsed from
+	Record *WhoisRecord
+}
+
+// IPData returns the Whois record of the IP address
+func (service whoisApiServiceOp) IPData(
+	ctx context.Context,
+	ip netip.Addr,
+	opts ...Option,
+) (*IPWhoisRecord, *Response, error) {
+
+	if !ip.IsValid() {
+		return nil, nil, &ArgError{"ip", "is not valid"}
+	}
+	ip = ip.Unmap().WithZone("")
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return nil, nil, &ArgError{"ip", ip.String() + " is not a public address"}
+	}
+
+	rec, resp, err := service.Data(ctx, ip.String(), opts...)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return NewIPWhoisRecord(ip, rec), resp, nil
+}
+
+// PrefixData returns the Whois record of the most specific netblock containing the whole CIDR prefix.
+// The first address of the prefix is looked up, ErrPrefixNotCovered is returned
+// if none of the netblocks in the response contains the whole prefix
+func (service whoisApiServiceOp) PrefixData(
+	ctx context.Context,
+	prefix netip.Prefix,
+	opts ...Option,
+) (*IPWhoisRecord, *Response, error) {
+
+	prefix, err := NormalizePrefix(prefix)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rec, resp, err := service.Data(ctx, prefix.Addr().String(), opts...)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	ipRec := NewPrefixWhoisRecord(prefix, rec)
+	if !ipRec.Range.ContainsPrefix(prefix) {
+		return nil, resp, fmt.Errorf("cannot look up %s: %w", prefix, ErrPrefixNotCovered)
+	}
+	return ipRec, resp, nil
+}
+
+// NormalizePrefix unmaps IPv4-mapped prefixes and masks the host bits.
+// It returns ArgError if the prefix is not valid or its first or last address is not public
+func NormalizePrefix(prefix netip.Prefix) (netip.Prefix, error) {
+	if !prefix.IsValid() {
+		return netip.Prefix{}, &ArgError{"prefix", "is not valid"}
+	}
+	if addr := prefix.Addr(); addr.Is4In6() && prefix.Bits() >= 96 {
+		prefix = netip.PrefixFrom(addr.Unmap(), prefix.Bits()-96)
+	}
+	prefix = prefix.Masked()
+
+	rng := prefixRange(prefix)
+	for _, ip := range []netip.Addr{rng.From, rng.To} {
+		if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+			return netip.Prefix{}, &ArgError{"prefix", prefix.String() + " is not a public prefix"}
+		}
+	}
+	return prefix, nil
+}
+
+// NewIPWhoisRecord parses IP-specific fields of the Whois record and its sub-records
+func NewIPWhoisRecord(ip netip.Addr, rec *WhoisRecord) *IPWhoisRecord {
+	return newIPWhoisRecord(ip, IPRange{From: ip, To: ip}, rec)
+}
+
+// NewPrefixWhoisRecord parses IP-specific fields of the Whois record and its sub-records
+// choosing the netblock that contains the whole prefix
+func NewPrefixWhoisRecord(prefix netip.Prefix, rec *WhoisRecord) *IPWhoisRecord {
+	result := newIPWhoisRecord(prefix.Addr(), prefixRange(prefix), rec)
+	result.Prefix = prefix
+	return result
+}
+
+// newIPWhoisRecord parses the fields of the most specific netblock containing the target range
+func newIPWhoisRecord(ip netip.Addr, target IPRange, rec *WhoisRecord) *IPWhoisRecord {
+	result := &IPWhoisRecord{
+		IP:     ip,
+		Record: rec,
+	}
+	if rec == nil {
+		return result
+	}
+
+	var blocks []ipBlock
+	var servers []string
+	var contacts []Contact
+	walkRecords(rec, func(r *WhoisRecord) {
+		blocks = append(blocks, customFieldsBlock(r))
+		blocks = append(blocks, parseIPBlocks(r.RawText)...)
+		blocks = append(blocks, parseIPBlocks(r.RegistryData.RawText)...)
+		servers = append(servers, r.RegistryData.WhoisServer)
+		contacts = append(contacts,
+			r.Registrant, r.AdministrativeContact, r.TechnicalContact,
+			r.RegistryData.Registrant, r.RegistryData.AdministrativeContact, r.RegistryData.TechnicalContact)
+		if result.Registrant.IsEmpty() {
+			result.Registrant = firstContact(r.Registrant, r.RegistryData.Registrant)
+		}
+	})
+
+	// the most specific netblock with the target wins
+	var net ipBlock
+	for _, b := range blocks {
+		if !b.rng.Contains(target.From) || !b.rng.Contains(target.To) {
+			continue
+		}
+		if !net.rng.IsValid() || b.rng.size().Cmp(net.rng.size()) < 0 {
+			net = b
+		}
+	}
+	result.Range = net.rng
+	result.Prefixes = net.prefixes
+	result.NetworkName = net.get("netname")
+	result.ASN = parseASN(net.get("originas"))
+	result.RIR = detectRIR(net.get("source", "ref"))
+	if result.RIR == RIRUnknown && net.get("netrange") != "" {
+		result.RIR = RIRARIN
+	}
+
+	// route objects with the target tell the origin ASN
+	if result.ASN == 0 {
+		var route netip.Prefix
+		for _, b := range blocks {
+			prefix, err := netip.ParsePrefix(b.get("route", "route6"))
+			if err != nil || !prefix.Contains(target.From) || !prefix.Contains(target.To) ||
+				(route.IsValid() && prefix.Bits() <= route.Bits()) {
+				continue
+			}
+			if asn := parseASN(b.get("origin")); asn != 0 {
+				route = prefix
+				result.ASN = asn
+			}
+		}
+	}
+
+	for _, b := range blocks {
+		if result.ASN == 0 {
+			result.ASN = parseASN(b.get("originas", "origin"))
+		}
+		if result.RIR == RIRUnknown {
+			result.RIR = detectRIR(b.get("source"))
+		}
+		if c := b.abuseContact(); c.Email != "" {
+			contacts = append(contacts, c)
+		}
+	}
+	for _, s := range servers {
+		if result.RIR == RIRUnknown {
+			result.RIR = detectRIR(s)
+		}
+	}
+
+	result.AbuseContacts = abuseContacts(contacts)
+
+	return result
+}
+
+// walkRecords calls fn for the record and all its sub-records
+func walkRecords(rec *WhoisRecord, fn func(r *WhoisRecord)) {
+	fn(rec)
+	for i := range rec.SubRecords {
+		walkRecords(&rec.SubRecords[i], fn)
+	}
+}
+
+// firstContact returns the first non-empty contact
+func firstContact(contacts ...Contact) Contact {
+	for _, c := range contacts {
+		if !c.IsEmpty() {
+			return c
+		}
+	}
+	return Contact{}
+}
+
+// abuseContacts returns unique contacts with abuse emails
+func abuseContacts(contacts []Contact) []Contact {
+	var result []Contact
+	seen := make(map[string]bool)
+	for _, c := range contacts {
+		email := strings.ToLower(c.Email)
+		if !strings.Contains(email, "abuse") && !strings.Contains(strings.ToLower(c.Name), "abuse") {
+			continue
+		}
+		if email == "" || seen[email] {
+			continue
+		}
+		seen[email] = true
+		result = append(result, c)
+	}
+	return result
+}
+
+// ipBlock is the paragraph of the raw Whois text with lowercase keys
+type ipBlock struct {
+	fields   map[string]string
+	rng      IPRange
+	prefixes []netip.Prefix
+}
+
+// get returns the value of the first key present
+func (b ipBlock) get(keys ...string) string {
+	for _, k := range keys {
+		if v := b.fields[k]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// abuseContact returns the abuse contact of ARIN or RPSL paragraphs
+func (b ipBlock) abuseContact() Contact {
+	return Contact{
+		Name:      b.get("orgabusename"),
+		Email:     b.get("orgabuseemail", "abuse-mailbox"),
+		Telephone: b.get("orgabusephone"),
+	}
+}
+
+// setRange parses netblock fields of the paragraph
+func (b *ipBlock) setRange() {
+	for _, p := range strings.Split(b.get("cidr"), ",") {
+		if prefix, err := netip.ParsePrefix(strings.TrimSpace(p)); err == nil {
+			b.prefixes = append(b.prefixes, prefix.Masked())
+		}
+	}
+
+	b.rng = parseIPRange(b.get("netrange", "inetnum", "inet6num"))
+	if !b.rng.IsValid() && len(b.prefixes) > 0 {
+		b.rng = prefixRange(b.prefixes[0])
+	}
+	if b.rng.IsValid() && len(b.prefixes) == 0 {
+		if prefix, err := netip.ParsePrefix(b.get("inet6num", "inetnum")); err == nil {
+			b.prefixes = append(b.prefixes, prefix.Masked())
+		}
+	}
+}
+
+// parseIPBlocks splits the raw Whois text into paragraphs of "key: value" lines
+func parseIPBlocks(raw string) []ipBlock {
+	var blocks []ipBlock
+	block := ipBlock{fields: make(map[string]string)}
+
+	flush := func() {
+		if len(block.fields) > 0 {
+			block.setRange()
+			blocks = append(blocks, block)
+		}
+		block = ipBlock{fields: make(map[string]string)}
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(raw))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			flush()
+			continue
+		}
+		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "%") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		if _, exists := block.fields[key]; !exists {
+			block.fields[key] = strings.TrimSpace(value)
+		}
+	}
+	flush()
+
+	return blocks
+}
+
+// customFieldsBlock returns the paragraph made of the custom fields of the record
+func customFieldsBlock(rec *WhoisRecord) ipBlock {
+	block := ipBlock{fields: make(map[string]string)}
+	for _, f := range [][2]string{
+		{rec.Custom1FieldName, rec.Custom1FieldValue},
+		{rec.Custom2FieldName, rec.Custom2FieldValue},
+		{rec.Custom3FieldName, rec.Custom3FieldValue},
+	} {
+		if f[0] != "" && f[1] != "" {
+			block.fields[strings.ToLower(f[0])] = f[1]
+		}
+	}
+	block.setRange()
+	return block
+}
+
+// parseIPRange parses "From - To" ranges and CIDR prefixes
+func parseIPRange(s string) IPRange {
+	if from, to, ok := strings.Cut(s, "-"); ok {
+		r := IPRange{}
+		r.From, _ = netip.ParseAddr(strings.TrimSpace(from))
+		r.To, _ = netip.ParseAddr(strings.TrimSpace(to))
+		if r.IsValid() {
+			return r
+		}
+		return IPRange{}
+	}
+	if prefix, err := netip.ParsePrefix(strings.TrimSpace(s)); err == nil {
+		return prefixRange(prefix)
+	}
+	return IPRange{}
+}
+
+// prefixRange returns the range of the prefix
+func prefixRange(prefix netip.Prefix) IPRange {
+	prefix = prefix.Masked()
+	to := prefix.Addr().AsSlice()
+	for bit := prefix.Bits(); bit < len(to)*8; bit++ {
+		to[bit/8] |= 1 << (7 - bit%8)
+	}
+	last, _ := netip.AddrFromSlice(to)
+	return IPRange{From: prefix.Addr(), To: last}
+}
+
+// parseASN parses the first ASN of "AS15169" or "15169" forms, 0 if there is none
+func parseASN(s string) uint32 {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	if len(fields) == 0 {
+		return 0
+	}
+
+	n, err := strconv.ParseUint(strings.TrimPrefix(strings.ToUpper(fields[0]), "AS"), 10, 32)
+	if err != nil {
+		return 0
+	}
+	return uint32(n)
+}
+
+// detectRIR detects the registry by the source or the Whois server name
+func detectRIR(s string) RIR {
+	s = strings.ToUpper(s)
+	for _, rir := range []RIR{RIRARIN, RIRRIPE, RIRAPNIC, RIRLACNIC, RIRAFRINIC} {
+		if strings.Contains(s, string(rir)) {
+			return rir
+		}
+	}
+	return RIRUnknown
+}
@@ -0,0 +1,275 @@
+package whoisapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+// arinRawText is the sample ARIN response with the parent and the child netblock
+const arinRawText = `#
+# ARIN WHOIS data and services are subject to the Terms of Use
+#
+
+NetRange:       8.0.0.0 - 8.127.255.255
+CIDR:           8.0.0.0/9
+NetName:        LVLT-ORG-8-8
+OriginAS:
+Organization:   Level 3 Parent, LLC (LPL-141)
+Ref:            https://rdap.arin.net/registry/ip/8.0.0.0
+
+NetRange:       8.8.8.0 - 8.8.8.255
+CIDR:           8.8.8.0/24
+NetName:        GOGL
+OriginAS:       AS15169
+Organization:   Google LLC (GOGL)
+Ref:            https://rdap.arin.net/registry/ip/8.8.8.0
+
+OrgAbuseHandle: ABUSE5250-ARIN
+OrgAbuseName:   Abuse
+OrgAbusePhone:  +1-650-253-0000
+OrgAbuseEmail:  network-abuse@google.com
+`
+
+// ripeRawText is the sample RIPE response with a route object
+const ripeRawText = `% This is the RIPE Database query service.
+
+inetnum:        193.0.0.0 - 193.0.7.255
+netname:        RIPE-NCC
+country:        NL
+abuse-mailbox:  abuse@ripe.net
+source:         RIPE
+
+route:          193.0.0.0/21
+origin:         AS3333
+source:         RIPE
+`
+
+// TestNewIPWhoisRecord tests parsing of IP-specific fields
+func TestNewIPWhoisRecord(t *testing.T) {
+	tests := []struct {
+		name string
+		ip   string
+		rec  WhoisRecord
+		want IPWhoisRecord
+	}{
+		{
+			name: "arin",
+			ip:   "8.8.8.8",
+			rec: func() WhoisRecord {
+				rec := WhoisRecord{}
+				rec.RawText = arinRawText
+				rec.Registrant = Contact{Organization: "Google LLC"}
+				rec.TechnicalContact = Contact{Name: "Google LLC", Email: "arin-contact@google.com"}
+				return rec
+			}(),
+			want: IPWhoisRecord{
+				Range:         IPRange{netip.MustParseAddr("8.8.8.0"), netip.MustParseAddr("8.8.8.255")},
+				Prefixes:      []netip.Prefix{netip.MustParsePrefix("8.8.8.0/24")},
+				ASN:           15169,
+				NetworkName:   "GOGL",
+				RIR:           RIRARIN,
+				Registrant:    Contact{Organization: "Google LLC"},
+				AbuseContacts: []Contact{{Name: "Abuse", Email: "network-abuse@google.com", Telephone: "+1-650-253-0000"}},
+			},
+		},
+		{
+			name: "ripe in sub-record",
+			ip:   "193.0.6.139",
+			rec: func() WhoisRecord {
+				sub := WhoisRecord{}
+				sub.RegistryData.RawText = ripeRawText
+				sub.Registrant = Contact{Organization: "Reseaux IP Europeens Network Coordination Centre"}
+				return WhoisRecord{SubRecords: []WhoisRecord{sub}}
+			}(),
+			want: IPWhoisRecord{
+				Range:         IPRange{netip.MustParseAddr("193.0.0.0"), netip.MustParseAddr("193.0.7.255")},
+				ASN:           3333,
+				NetworkName:   "RIPE-NCC",
+				RIR:           RIRRIPE,
+				Registrant:    Contact{Organization: "Reseaux IP Europeens Network Coordination Centre"},
+				AbuseContacts: []Contact{{Email: "abuse@ripe.net"}},
+			},
+		},
+		{
+			name: "custom fields",
+			ip:   "2001:db8::1",
+			rec: WhoisRecord{
+				Custom1FieldName:  "inet6num",
+				Custom1FieldValue: "2001:db8::/32",
+				Custom2FieldName:  "netname",
+				Custom2FieldValue: "DOCUMENTATION",
+				Custom3FieldName:  "source",
+				Custom3FieldValue: "APNIC",
+			},
+			want: IPWhoisRecord{
+				Range: IPRange{
+					netip.MustParseAddr("2001:db8::"),
+					netip.MustParseAddr("2001:db8:ffff:ffff:ffff:ffff:ffff:ffff"),
+				},
+				Prefixes:    []netip.Prefix{netip.MustParsePrefix("2001:db8::/32")},
+				NetworkName: "DOCUMENTATION",
+				RIR:         RIRAPNIC,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ip := netip.MustParseAddr(tt.ip)
+			got := NewIPWhoisRecord(ip, &tt.rec)
+
+			tt.want.IP = ip
+			tt.want.Record = &tt.rec
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("NewIPWhoisRecord() got  = %+v", *got)
+				t.Errorf("NewIPWhoisRecord() want = %+v", tt.want)
+			}
+		})
+	}
+}
+
+// TestIPData tests the IPData method
+func TestIPData(t *testing.T) {
+	var names []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		names = append(names, req.URL.Query().Get("domainName"))
+
+		rec := WhoisRecord{}
+		rec.DomainName = req.URL.Query().Get("domainName")
+		rec.RawText = arinRawText
+		_ = json.NewEncoder(w).Encode(whoisApiResponse{WhoisRecord: &rec})
+	}))
+	defer server.Close()
+
+	apiURL, _ := url.Parse(server.URL)
+	client := NewClient(apiKey, ClientParams{HTTPClient: server.Client(), WhoisBaseURL: apiURL})
+
+	tests := []struct {
+		name    string
+		ip      netip.Addr
+		wantErr string
+	}{
+		{"ipv4", netip.MustParseAddr("8.8.8.8"), ""},
+		{"ipv4-mapped", netip.MustParseAddr("::ffff:8.8.4.4"), ""},
+		{"invalid", netip.Addr{}, `invalid argument: "ip" is not valid`},
+		{"private", netip.MustParseAddr("10.0.0.1"), `invalid argument: "ip" 10.0.0.1 is not a public address`},
+		{"loopback", netip.MustParseAddr("::1"), `invalid argument: "ip" ::1 is not a public address`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, _, err := client.IPData(context.Background(), tt.ip)
+			checkErr(t, err, tt.wantErr)
+			if err == nil && (rec.ASN != 15169 || rec.Record.DomainName != tt.ip.Unmap().String()) {
+				t.Errorf("IPData() got = %+v", rec)
+			}
+		})
+	}
+
+	if !reflect.DeepEqual(names, []string{"8.8.8.8", "8.8.4.4"}) {
+		t.Errorf("names sent got = %v", names)
+	}
+}
+
+// TestPrefixData tests the PrefixData method
+func TestPrefixData(t *testing.T) {
+	var names []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		names = append(names, req.URL.Query().Get("domainName"))
+
+		rec := WhoisRecord{}
+		rec.DomainName = req.URL.Query().Get("domainName")
+		rec.RawText = arinRawText
+		_ = json.NewEncoder(w).Encode(whoisApiResponse{WhoisRecord: &rec})
+	}))
+	defer server.Close()
+
+	apiURL, _ := url.Parse(server.URL)
+	client := NewClient(apiKey, ClientParams{HTTPClient: server.Client(), WhoisBaseURL: apiURL})
+
+	tests := []struct {
+		name        string
+		prefix      netip.Prefix
+		wantPrefix  string
+		wantRange   string
+		wantNetwork string
+	}{
+		{"inside the netblock", netip.MustParsePrefix("8.8.8.128/25"), "8.8.8.128/25", "8.8.8.0 - 8.8.8.255", "GOGL"},
+		{"larger than the netblock", netip.MustParsePrefix("8.8.0.0/16"), "8.8.0.0/16", "8.0.0.0 - 8.127.255.255", "LVLT-ORG-8-8"},
+		{"host bits and mapped", netip.MustParsePrefix("::ffff:8.8.8.8/120"), "8.8.8.0/24", "8.8.8.0 - 8.8.8.255", "GOGL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, _, err := client.PrefixData(context.Background(), tt.prefix)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rec.Prefix.String() != tt.wantPrefix || rec.IP != rec.Prefix.Addr() {
+				t.Errorf("Prefix got = %v, %v, want %s", rec.Prefix, rec.IP, tt.wantPrefix)
+			}
+			if rec.Range.String() != tt.wantRange || rec.NetworkName != tt.wantNetwork {
+				t.Errorf("PrefixData() got = %v, %q", rec.Range, rec.NetworkName)
+			}
+		})
+	}
+
+	// the /9 netblock is smaller than the prefix
+	_, _, err := client.PrefixData(context.Background(), netip.MustParsePrefix("8.0.0.0/8"))
+	if !errors.Is(err, ErrPrefixNotCovered) {
+		t.Errorf("PrefixData() error = %v, want %v", err, ErrPrefixNotCovered)
+	}
+
+	if !reflect.DeepEqual(names, []string{"8.8.8.128", "8.8.0.0", "8.8.8.0", "8.0.0.0"}) {
+		t.Errorf("names sent got = %v", names)
+	}
+}
+
+// TestNormalizePrefix tests the NormalizePrefix function
+func TestNormalizePrefix(t *testing.T) {
+	tests := []struct {
+		in      netip.Prefix
+		want    string
+		wantErr string
+	}{
+		{netip.MustParsePrefix("193.0.6.139/21"), "193.0.0.0/21", ""},
+		{netip.MustParsePrefix("::ffff:193.0.0.0/117"), "193.0.0.0/21", ""},
+		{netip.MustParsePrefix("2001:67c:2e8::/48"), "2001:67c:2e8::/48", ""},
+		{netip.Prefix{}, "invalid Prefix", `invalid argument: "prefix" is not valid`},
+		{netip.MustParsePrefix("10.1.0.0/16"), "invalid Prefix", `invalid argument: "prefix" 10.1.0.0/16 is not a public prefix`},
+		{netip.MustParsePrefix("0.0.0.0/0"), "invalid Prefix", `invalid argument: "prefix" 0.0.0.0/0 is not a public prefix`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in.String(), func(t *testing.T) {
+			got, err := NormalizePrefix(tt.in)
+			checkErr(t, err, tt.wantErr)
+			if got.String() != tt.want {
+				t.Errorf("NormalizePrefix() got = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseIPRange tests the parseIPRange function
+func TestParseIPRange(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"8.8.8.0 - 8.8.8.255", "8.8.8.0 - 8.8.8.255"},
+		{"8.8.8.0/22", "8.8.8.0 - 8.8.11.255"},
+		{"2001:db8::/126", "2001:db8:: - 2001:db8::3"},
+		{"8.8.8.255 - 8.8.8.0", ""},
+		{"8.8.8.0 - 2001:db8::", ""},
+		{"not a range", ""},
+	}
+	for _, tt := range tests {
+		if got := parseIPRange(tt.in).String(); got != tt.want {
+			t.Errorf("parseIPRange(%v) got = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
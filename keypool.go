@@ -0,0 +1,303 @@
+package whoisapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultKeyCooldown is the default time a key stays out of rotation after a quota or auth error
+	DefaultKeyCooldown = time.Hour
+
+	// DefaultRateLimitBackoff is the default time a key stays out of rotation after a 429 response
+	// without the Retry-After header
+	DefaultRateLimitBackoff = time.Second
+)
+
+// ErrNoAvailableKeys is returned when every key of the KeyPool is cooling down
+var ErrNoAvailableKeys = errors.New("no API keys available: all keys are cooling down")
+
+// KeyStrategy defines how KeyPool picks a key for a request
+type KeyStrategy int
+
+const (
+	// KeyStrategyRoundRobin uses available keys in turn
+	KeyStrategyRoundRobin KeyStrategy = iota
+
+	// KeyStrategyWeighted uses available keys in proportion to their weights
+	KeyStrategyWeighted
+
+	// KeyStrategyFailover uses the first available key in the order they were added
+	KeyStrategyFailover
+)
+
+// exhaustedStatusCodes are the HTTP status codes that take a key out of rotation
+var exhaustedStatusCodes = map[int]bool{
+	http.StatusUnauthorized:    true,
+	http.StatusPaymentRequired: true,
+	http.StatusForbidden:       true,
+}
+
+// APIKey is a key of the KeyPool
+type APIKey struct {
+	// Key is the API key
+	Key string
+
+	// Weight is the share of requests for KeyStrategyWeighted, 1 is used if not positive
+	Weight int
+}
+
+// KeyStats are usage statistics of a pool key
+type KeyStats struct {
+	// Key is the masked API key
+	Key string
+
+	// Requests is the number of requests made with the key
+	Requests int64
+
+	// Exhausted is the number of quota or auth errors returned for the key
+	Exhausted int64
+
+	// RateLimited is the number of 429 responses returned for the key
+	RateLimited int64
+
+	// CooldownUntil is the time the key returns to rotation, zero if it's available
+	CooldownUntil time.Time
+}
+
+// poolKey is the key with its state
+type poolKey struct {
+	APIKey
+	current       int
+	requests      int64
+	exhausted     int64
+	rateLimited   int64
+	cooldownUntil time.Time
+}
+
+// KeyPool picks an API key for every request and takes keys out of rotation
+// when they return quota or auth errors. A key answered with 429 Too Many Requests
+// only backs off for Retry-After. It is safe for concurrent use
+type KeyPool struct {
+	// Cooldown is the time a key stays out of rotation, DefaultKeyCooldown is used if zero
+	Cooldown time.Duration
+
+	// RateLimitBackoff is the time a key stays out of rotation after a 429 response without Retry-After,
+	// DefaultRateLimitBackoff is used if zero
+	RateLimitBackoff time.Duration
+
+	// ExhaustedCodes are the ErrorMessage error codes returned with 200 OK that mean the key
+	// is out of credits or not authorized. 401, 402 and 403 responses are always treated so
+	ExhaustedCodes []string
+
+	// IsExhausted reports whether the Whois API error message means the key is out of credits
+	// or not authorized. If it's nil then the error code is looked up in ExhaustedCodes
+	IsExhausted func(e ErrorMessage) bool
+
+	strategy KeyStrategy
+	now      func() time.Time
+
+	mu   sync.Mutex
+	keys []*poolKey
+	next int
+}
+
+// NewKeyPool creates KeyPool with the strategy and the keys
+func NewKeyPool(strategy KeyStrategy, keys ...APIKey) *KeyPool {
+	pool := &KeyPool{
+		strategy: strategy,
+		now:      time.Now,
+	}
+	for _, k := range keys {
+		if k.Weight <= 0 {
+			k.Weight = 1
+		}
+		pool.keys = append(pool.keys, &poolKey{APIKey: k})
+	}
+	return pool
+}
+
+// pick returns the key for the next request
+func (p *KeyPool) pick() (*poolKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	available := func(k *poolKey) bool {
+		return !now.Before(k.cooldownUntil)
+	}
+
+	var picked *poolKey
+	switch p.strategy {
+	case KeyStrategyWeighted:
+		// smooth weighted round-robin
+		total := 0
+		for _, k := range p.keys {
+			if !available(k) {
+				continue
+			}
+			k.current += k.Weight
+			total += k.Weight
+			if picked == nil || k.current > picked.current {
+				picked = k
+			}
+		}
+		if picked != nil {
+			picked.current -= total
+		}
+	case KeyStrategyFailover:
+		for _, k := range p.keys {
+			if available(k) {
+				picked = k
+				break
+			}
+		}
+	default:
+		for i := 0; i < len(p.keys); i++ {
+			k := p.keys[(p.next+i)%len(p.keys)]
+			if available(k) {
+				picked = k
+				p.next = (p.next + i + 1) % len(p.keys)
+				break
+			}
+		}
+	}
+
+	if picked == nil {
+		return nil, ErrNoAvailableKeys
+	}
+
+	picked.cooldownUntil = time.Time{}
+	picked.requests++
+	return picked, nil
+}
+
+// size returns the number of keys
+func (p *KeyPool) size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.keys)
+}
+
+// check takes the key out of rotation if the response means it is out of credits, not authorized
+// or rate limited. It reports whether that happened
+func (p *KeyPool) check(k *poolKey, resp *Response) bool {
+	if resp.Response != nil && resp.StatusCode == http.StatusTooManyRequests {
+		p.backoff(k, resp.Header.Get("Retry-After"))
+		return true
+	}
+
+	exhausted := resp.Response != nil && exhaustedStatusCodes[resp.StatusCode]
+	if !exhausted {
+		var parsed struct {
+			ErrorMessage *ErrorMessage `json:"ErrorMessage"`
+		}
+		if json.Unmarshal(resp.Body, &parsed) == nil && parsed.ErrorMessage != nil {
+			isExhausted := p.IsExhausted
+			if isExhausted == nil {
+				isExhausted = p.isExhaustedCode
+			}
+			exhausted = isExhausted(*parsed.ErrorMessage)
+		}
+	}
+	if !exhausted {
+		return false
+	}
+
+	cooldown := p.Cooldown
+	if cooldown <= 0 {
+		cooldown = DefaultKeyCooldown
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	k.exhausted++
+	k.cooldownUntil = p.now().Add(cooldown)
+
+	return true
+}
+
+// backoff takes the rate limited key out of rotation for the Retry-After value
+// in seconds or as an HTTP date, RateLimitBackoff is used if it's missing or invalid
+func (p *KeyPool) backoff(k *poolKey, retryAfter string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	until := time.Time{}
+	if seconds, err := strconv.Atoi(strings.TrimSpace(retryAfter)); err == nil && seconds >= 0 {
+		until = now.Add(time.Duration(seconds) * time.Second)
+	} else if date, err := http.ParseTime(retryAfter); err == nil {
+		until = date
+	}
+	if until.IsZero() {
+		backoff := p.RateLimitBackoff
+		if backoff <= 0 {
+			backoff = DefaultRateLimitBackoff
+		}
+		until = now.Add(backoff)
+	}
+
+	k.rateLimited++
+	if until.After(k.cooldownUntil) {
+		k.cooldownUntil = until
+	}
+}
+
+// isExhaustedCode reports whether the error code is one of ExhaustedCodes
+func (p *KeyPool) isExhaustedCode(e ErrorMessage) bool {
+	for _, code := range p.ExhaustedCodes {
+		if strings.EqualFold(e.ErrorCode, code) {
+			return true
+		}
+	}
+	return false
+}
+
+// Stats returns usage statistics of every key in the order they were added
+func (p *KeyPool) Stats() []KeyStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	stats := make([]KeyStats, 0, len(p.keys))
+	for _, k := range p.keys {
+		st := KeyStats{
+			Key:         maskKey(k.Key),
+			Requests:    k.requests,
+			Exhausted:   k.exhausted,
+			RateLimited: k.rateLimited,
+		}
+		if now.Before(k.cooldownUntil) {
+			st.CooldownUntil = k.cooldownUntil
+		}
+		stats = append(stats, st)
+	}
+	return stats
+}
+
+// redact replaces all pool keys in the string
+func (p *KeyPool) redact(s string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, k := range p.keys {
+		if k.Key != "" {
+			s = strings.ReplaceAll(s, k.Key, redactedValue)
+		}
+	}
+	return s
+}
+
+// maskKey returns the key with only its first and last characters visible
+func maskKey(key string) string {
+	if len(key) <= 8 {
+		return strings.Repeat("*", len(key))
+	}
+	return key[:3] + strings.Repeat("*", len(key)-7) + key[len(key)-4:]
+}
@@ -0,0 +1,224 @@
+package whoisapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestKeyPoolStrategies tests the order keys are picked in
+func TestKeyPoolStrategies(t *testing.T) {
+	keys := []APIKey{{Key: "a", Weight: 3}, {Key: "b"}, {Key: "c", Weight: 1}}
+
+	tests := []struct {
+		name     string
+		strategy KeyStrategy
+		want     string
+	}{
+		{
+			name:     "round-robin",
+			strategy: KeyStrategyRoundRobin,
+			want:     "abcabc",
+		},
+		{
+			name:     "weighted",
+			strategy: KeyStrategyWeighted,
+			want:     "abacaabaca",
+		},
+		{
+			name:     "failover",
+			strategy: KeyStrategyFailover,
+			want:     "aaaa",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := NewKeyPool(tt.strategy, keys...)
+
+			var got string
+			for range tt.want {
+				k, err := pool.pick()
+				if err != nil {
+					t.Fatal(err)
+				}
+				got += k.Key
+			}
+
+			if got != tt.want {
+				t.Errorf("pick() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestKeyPoolCooldown tests that exhausted keys are taken out of rotation and come back
+func TestKeyPoolCooldown(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	pool := NewKeyPool(KeyStrategyFailover, APIKey{Key: "at_first_key_0001"}, APIKey{Key: "at_second_key_002"})
+	pool.now = func() time.Time { return now }
+	pool.Cooldown = time.Minute
+	pool.ExhaustedCodes = []string{"WHOIS_02"}
+
+	tests := []struct {
+		name       string
+		status     int
+		retryAfter string
+		body       string
+		want       bool
+		wantUntil  time.Time
+	}{
+		{"success", 200, "", `{"WhoisRecord": {}}`, false, time.Time{}},
+		{"other error code", 200, "", `{"ErrorMessage": {"errorCode": "WHOIS_01", "msg": "Invalid domain name"}}`, false, time.Time{}},
+		{"quota phrase of other code", 200, "", `{"ErrorMessage": {"errorCode": "WHOIS_01", "msg": "Limit exceeded"}}`, false, time.Time{}},
+		{"exhausted code", 200, "", `{"ErrorMessage": {"errorCode": "WHOIS_02", "msg": "Insufficient credits"}}`, true, now.Add(time.Minute)},
+		{"forbidden", 403, "", ``, true, now.Add(time.Minute)},
+		{"rate limited", 429, "", ``, true, now.Add(DefaultRateLimitBackoff)},
+		{"retry after seconds", 429, "5", ``, true, now.Add(5 * time.Second)},
+		{"retry after date", 429, now.Add(time.Minute).Format(http.TimeFormat), ``, true, now.Add(time.Minute)},
+		{"invalid retry after", 429, "soon", ``, true, now.Add(DefaultRateLimitBackoff)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := pool.keys[0]
+			resp := &Response{
+				Response: &http.Response{StatusCode: tt.status, Header: http.Header{}},
+				Body:     []byte(tt.body),
+			}
+			if tt.retryAfter != "" {
+				resp.Header.Set("Retry-After", tt.retryAfter)
+			}
+			if got := pool.check(k, resp); got != tt.want {
+				t.Errorf("check() got = %v, want %v", got, tt.want)
+			}
+			if !k.cooldownUntil.Equal(tt.wantUntil) {
+				t.Errorf("cooldown got = %v, want %v", k.cooldownUntil, tt.wantUntil)
+			}
+			k.cooldownUntil = time.Time{}
+		})
+	}
+
+	pool.check(pool.keys[0], statusResponse(401))
+	if k, _ := pool.pick(); k.Key != "at_second_key_002" {
+		t.Errorf("pick() got = %v, want the second key", k.Key)
+	}
+
+	pool.check(pool.keys[1], statusResponse(401))
+	if _, err := pool.pick(); !errors.Is(err, ErrNoAvailableKeys) {
+		t.Errorf("pick() error = %v, want %v", err, ErrNoAvailableKeys)
+	}
+
+	now = now.Add(time.Minute)
+	if k, _ := pool.pick(); k.Key != "at_first_key_0001" {
+		t.Errorf("pick() got = %v, want the first key after cooldown", k.Key)
+	}
+
+	want := []KeyStats{
+		{Key: "at_**********0001", Requests: 1, Exhausted: 3, RateLimited: 4},
+		{Key: "at_**********_002", Requests: 1, Exhausted: 1},
+	}
+	if got := pool.Stats(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Stats() got  = %v", got)
+		t.Errorf("Stats() want = %v", want)
+	}
+}
+
+// TestKeyPoolClient tests that the client retries the request with another key
+func TestKeyPoolClient(t *testing.T) {
+	const exhaustedKey = "at_exhausted_key_000"
+	const validKey = "at_valid_key_0000001"
+
+	var mu sync.Mutex
+	var used []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		key := req.URL.Query().Get("apiKey")
+
+		mu.Lock()
+		used = append(used, key)
+		mu.Unlock()
+
+		if key == exhaustedKey {
+			_, _ = w.Write([]byte(`{"ErrorMessage": {"errorCode": "WHOIS_02", "msg": "Your API key has run out of credits"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"WhoisRecord": {"domainName": "whoisxmlapi.com"}}`))
+	}))
+	defer server.Close()
+
+	apiURL, _ := url.Parse(server.URL)
+	pool := NewKeyPool(KeyStrategyRoundRobin, APIKey{Key: exhaustedKey}, APIKey{Key: validKey})
+	pool.ExhaustedCodes = []string{"WHOIS_02"}
+	// every concurrent lookup picks its own key
+	client := NewClient("", ClientParams{
+		HTTPClient:           server.Client(),
+		WhoisBaseURL:         apiURL,
+		KeyPool:              pool,
+		DisableDeduplication: true,
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec, _, err := client.Data(context.Background(), "whoisxmlapi.com")
+			if err != nil || rec == nil {
+				t.Errorf("Data() got = %v, %v", rec, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	exhausted := 0
+	for _, key := range used {
+		if key == exhaustedKey {
+			exhausted++
+		}
+	}
+	if exhausted == 0 || exhausted > 10 || len(used) != 10+exhausted {
+		t.Errorf("keys used = %v", used)
+	}
+
+	if stats := pool.Stats(); stats[0].CooldownUntil.IsZero() || stats[1].Requests < 10 {
+		t.Errorf("Stats() got = %v", stats)
+	}
+
+	// only the exhausted key is left
+	singlePool := NewKeyPool(KeyStrategyFailover, APIKey{Key: exhaustedKey})
+	singlePool.ExhaustedCodes = []string{"WHOIS_02"}
+	single := NewClient("", ClientParams{
+		HTTPClient:   server.Client(),
+		WhoisBaseURL: apiURL,
+		KeyPool:      singlePool,
+	})
+	_, _, err := single.Data(context.Background(), "whoisxmlapi.com")
+	checkErr(t, err, "API error: [WHOIS_02] Your API key has run out of credits")
+	_, _, err = single.Data(context.Background(), "whoisxmlapi.com")
+	checkErr(t, err, ErrNoAvailableKeys.Error())
+}
+
+// statusResponse returns the Response with the status code and no body
+func statusResponse(status int) *Response {
+	return &Response{Response: &http.Response{StatusCode: status, Header: http.Header{}}}
+}
+
+// TestMaskKey tests the maskKey function
+func TestMaskKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"at_LoremIpsumDolorSitAmetConsect", "at_*************************sect"},
+		{"short", "*****"},
+	}
+	for _, tt := range tests {
+		if got := maskKey(tt.key); got != tt.want {
+			t.Errorf("maskKey(%v) got = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package whoisapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// redactedValue replaces secrets in logged URLs and messages
+const redactedValue = "REDACTED"
+
+// maxLoggedBody is the maximum number of response body bytes kept for logging
+const maxLoggedBody = 64 << 10
+
+// RedactURL returns a copy of the URL with the apiKey query parameter value replaced.
+// Use it before logging Response.Request.URL
+func RedactURL(u *url.URL) *url.URL {
+	if u == nil {
+		return nil
+	}
+
+	redacted := *u
+	query := redacted.Query()
+	if _, ok := query["apiKey"]; ok {
+		query.Set("apiKey", redactedValue)
+		redacted.RawQuery = query.Encode()
+	}
+
+	return &redacted
+}
+
+// capWriter keeps the first max bytes written to it
+type capWriter struct {
+	buf bytes.Buffer
+	max int
+}
+
+// Write keeps the bytes that fit and always reports success
+func (w *capWriter) Write(p []byte) (int, error) {
+	if room := w.max - w.buf.Len(); room > 0 {
+		if len(p) > room {
+			w.buf.Write(p[:room])
+		} else {
+			w.buf.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+// logRequest logs the completed HTTP request
+func (c *Client) logRequest(ctx context.Context, req *http.Request, resp *http.Response,
+	start time.Time, n int64, body []byte, err error) {
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", RedactURL(req.URL).String()),
+		slog.Duration("duration", time.Since(start)),
+		slog.Int64("size", n),
+	}
+
+	level := slog.LevelInfo
+	if resp != nil {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			level = slog.LevelWarn
+		}
+	}
+
+	var parsed struct {
+		ErrorMessage *ErrorMessage `json:"ErrorMessage"`
+	}
+	if json.Unmarshal(body, &parsed) == nil && parsed.ErrorMessage != nil {
+		attrs = append(attrs, slog.String("errorCode", parsed.ErrorMessage.ErrorCode))
+		level = slog.LevelWarn
+	}
+
+	if c.logBodies && len(body) > 0 {
+		attrs = append(attrs, slog.String("body", string(body)))
+	}
+
+	if err != nil {
+		attrs = append(attrs, slog.String("error", c.redact(err.Error())))
+		level = slog.LevelError
+	}
+
+	c.logger.LogAttrs(ctx, level, "whoisapi request", attrs...)
+}
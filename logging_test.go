@@ -0,0 +1,147 @@
+package whoisapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// TestLogging tests the request logging
+func TestLogging(t *testing.T) {
+	const resp = `{"WhoisRecord": {"domainName": "whoisxmlapi.com"}}`
+	const errResp = `{"ErrorMessage": {"errorCode": "WHOIS_00", "msg": "test error message"}}`
+
+	server := whoisServer(resp, "<xml/>", errResp)
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		path      string
+		logBodies bool
+		want      map[string]interface{}
+	}{
+		{
+			name: "successful request",
+			path: pathWhoisResponseOK,
+			want: map[string]interface{}{
+				"level":  "INFO",
+				"method": "GET",
+				"status": float64(200),
+				"size":   float64(len(resp)),
+			},
+		},
+		{
+			name:      "error message with body",
+			path:      pathWhoisResponseError,
+			logBodies: true,
+			want: map[string]interface{}{
+				"level":     "WARN",
+				"status":    float64(400),
+				"errorCode": "WHOIS_00",
+				"body":      errResp,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			apiURL, _ := url.Parse(server.URL + tt.path)
+			client := NewClient(apiKey, ClientParams{
+				HTTPClient:   server.Client(),
+				WhoisBaseURL: apiURL,
+				Logger:       slog.New(slog.NewJSONHandler(&out, nil)),
+				LogBodies:    tt.logBodies,
+			})
+
+			_, _ = client.RawData(context.Background(), "whoisxmlapi.com")
+
+			if strings.Contains(out.String(), apiKey) {
+				t.Fatalf("log contains the api key: %s", out.String())
+			}
+
+			var got map[string]interface{}
+			if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("%v got = %v, want %v", k, got[k], v)
+				}
+			}
+			if _, ok := got["body"]; ok != tt.logBodies {
+				t.Errorf("body logged = %v, want %v", ok, tt.logBodies)
+			}
+
+			u, err := url.Parse(got["url"].(string))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if u.Query().Get("apiKey") != redactedValue || u.Query().Get("domainName") != "whoisxmlapi.com" {
+				t.Errorf("url got = %v", u)
+			}
+		})
+	}
+}
+
+// TestLoggingTransportError tests that transport errors are logged without the API key
+func TestLoggingTransportError(t *testing.T) {
+	server := whoisServer("", "", "")
+	server.Close()
+
+	var out bytes.Buffer
+	apiURL, _ := url.Parse(server.URL + pathWhoisResponseOK)
+	client := NewClient(apiKey, ClientParams{
+		HTTPClient:   server.Client(),
+		WhoisBaseURL: apiURL,
+		Logger:       slog.New(slog.NewTextHandler(&out, nil)),
+	})
+
+	_, err := client.RawData(context.Background(), "whoisxmlapi.com")
+	if err == nil {
+		t.Fatal("expected transport error")
+	}
+
+	if !strings.Contains(out.String(), "level=ERROR") || strings.Contains(out.String(), apiKey) {
+		t.Errorf("log got = %s", out.String())
+	}
+}
+
+// TestRedactURL tests the RedactURL function
+func TestRedactURL(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{
+			in:   "https://www.whoisxmlapi.com/whoisserver/WhoisService?apiKey=at_secret&domainName=example.com",
+			want: "https://www.whoisxmlapi.com/whoisserver/WhoisService?apiKey=REDACTED&domainName=example.com",
+		},
+		{
+			in:   "https://www.whoisxmlapi.com/whoisserver/WhoisService?domainName=example.com",
+			want: "https://www.whoisxmlapi.com/whoisserver/WhoisService?domainName=example.com",
+		},
+	}
+	for _, tt := range tests {
+		u, err := url.Parse(tt.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := RedactURL(u).String(); got != tt.want {
+			t.Errorf("RedactURL() got = %v, want %v", got, tt.want)
+		}
+		if u.String() != tt.in {
+			t.Errorf("RedactURL() modified the original URL: %v", u)
+		}
+	}
+
+	if RedactURL(nil) != nil {
+		t.Errorf("RedactURL(nil) expected nil")
+	}
+}
@@ -112,6 +112,9 @@ type Contact struct {
 
 	// Unparsable is the part of the raw text that is not parsable by our whois parser
 	Unparsable string `json:"unparsable"`
+
+	// Extra holds the keys of the response not known by this version of the package
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 // NameServers is part of the Whois API response
@@ -135,6 +138,21 @@ type RegistryData struct {
 
 	// ReferralURL is the referral URL
 	ReferralURL string `json:"referralURL"`
+
+	// Extra holds the keys of the response not known by this version of the package
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// AsRegistryData returns the common fields of the record as RegistryData,
+// e.g. to fill the registry part of a record built from another source
+func (r *WhoisRecord) AsRegistryData() RegistryData {
+	return RegistryData{baseWhoisRecord: r.baseWhoisRecord}
+}
+
+// AsWhoisRecord returns the common fields of the registry data as WhoisRecord,
+// e.g. to pass it where a record is expected
+func (r *RegistryData) AsWhoisRecord() WhoisRecord {
+	return WhoisRecord{baseWhoisRecord: r.baseWhoisRecord}
 }
 
 // baseWhoisRecord is the base part of the Whois record
@@ -259,6 +277,12 @@ type WhoisRecord struct {
 
 	// SubRecords are sub-records for this Whois record
 	SubRecords []WhoisRecord `json:"subRecords"`
+
+	// PrivateWhoisProxy is the proxy/Whois guard data returned with OptionCheckProxyData
+	PrivateWhoisProxy *Contact `json:"privateWhoisProxy,omitempty"`
+
+	// Extra holds the keys of the response not known by this version of the package
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 // ErrorMessage is an error message
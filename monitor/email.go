@@ -0,0 +1,169 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// EmailNotifier sends events by email over SMTP
+type EmailNotifier struct {
+	// Addr is the SMTP server address in the host:port form
+	Addr string
+
+	// Auth is the SMTP authentication. No authentication is used if nil
+	Auth smtp.Auth
+
+	// TLSConfig is used for STARTTLS if the server supports it.
+	// The config with ServerName set to the Addr host is used if nil
+	TLSConfig *tls.Config
+
+	// From is the sender address
+	From string
+
+	// To are the recipient addresses
+	To []string
+
+	// Subject is used to render the message subject. DefaultSubjectTemplate is used if nil
+	Subject *template.Template
+
+	// Template is used to render the message body. DefaultTextTemplate is used if nil
+	Template *template.Template
+
+	// Retry is the delivery retry policy
+	Retry RetryPolicy
+
+	dedupe deduper
+}
+
+var _ Notifier = &EmailNotifier{}
+
+// Notify sends the event message unless it was already delivered
+func (n *EmailNotifier) Notify(ctx context.Context, event Event) error {
+	return n.dedupe.deliver(ctx, event, func() error {
+		msg, err := n.message(event)
+		if err != nil {
+			return err
+		}
+
+		return n.Retry.do(ctx, func(ctx context.Context) error {
+			return n.send(ctx, msg)
+		})
+	})
+}
+
+// message renders the email message with headers
+func (n *EmailNotifier) message(event Event) ([]byte, error) {
+	subjectTmpl := n.Subject
+	if subjectTmpl == nil {
+		subjectTmpl = DefaultSubjectTemplate
+	}
+	bodyTmpl := n.Template
+	if bodyTmpl == nil {
+		bodyTmpl = DefaultTextTemplate
+	}
+
+	subject, err := render(subjectTmpl, event)
+	if err != nil {
+		return nil, err
+	}
+	body, err := render(bodyTmpl, event)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	b.WriteString("From: " + n.From + "\r\n")
+	b.WriteString("To: " + strings.Join(n.To, ", ") + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", subject) + "\r\n")
+	b.WriteString("Date: " + event.Time.Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+
+	return b.Bytes(), nil
+}
+
+// send delivers the message. SMTP 5xx replies are permanent errors
+func (n *EmailNotifier) send(ctx context.Context, msg []byte) error {
+	err := n.transaction(ctx, msg)
+
+	var tpErr *textproto.Error
+	if errors.As(err, &tpErr) && tpErr.Code >= 500 {
+		return &permanentError{err}
+	}
+	return err
+}
+
+// transaction runs the SMTP transaction
+func (n *EmailNotifier) transaction(ctx context.Context, msg []byte) error {
+	host, _, err := net.SplitHostPort(n.Addr)
+	if err != nil {
+		return &permanentError{err}
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", n.Addr)
+	if err != nil {
+		return fmt.Errorf("cannot connect: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		config := n.TLSConfig
+		if config == nil {
+			config = &tls.Config{ServerName: host}
+		}
+		if err := c.StartTLS(config); err != nil {
+			return err
+		}
+	}
+
+	if n.Auth != nil {
+		if err := c.Auth(n.Auth); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(n.From); err != nil {
+		return err
+	}
+	for _, to := range n.To {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
@@ -0,0 +1,435 @@
+// Package monitor watches a portfolio of domain names using Whois API
+// and notifies about expiring domains and changed Whois records
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	whoisapi "github.com/whois-api-llc/whois-api-go"
+)
+
+const (
+	// DefaultInterval is the default period between refreshes of the same domain name
+	DefaultInterval = 24 * time.Hour
+
+	// DefaultRetryInterval is the default delay before refreshing a domain name after a failed lookup
+	DefaultRetryInterval = time.Hour
+)
+
+// DefaultThresholds are the default numbers of days before expiry when notifiers are called
+var DefaultThresholds = []int{60, 30, 14, 7, 3, 1, 0}
+
+// EventType is the type of the monitor event
+type EventType string
+
+const (
+	// EventExpiring is sent when the domain name passes one of the expiry thresholds
+	EventExpiring EventType = "expiring"
+
+	// EventChanged is sent when the Whois record differs from the one seen on the previous refresh
+	EventChanged EventType = "changed"
+)
+
+// Event is passed to notifiers
+type Event struct {
+	// Type is the event type
+	Type EventType `json:"type"`
+
+	// Domain is the monitored domain name
+	Domain string `json:"domain"`
+
+	// Time is the time the event was detected
+	Time time.Time `json:"time"`
+
+	// ExpiresAt is the expiry date of the domain name, the zero time if it's unknown
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	// DaysLeft is the number of days until expiry, negative for expired domains
+	DaysLeft int `json:"daysLeft"`
+
+	// Threshold is the threshold in days that triggered the EventExpiring event
+	Threshold int `json:"threshold,omitempty"`
+
+	// Changes are the changes of the Whois record for the EventChanged event
+	Changes []whoisapi.Change `json:"changes,omitempty"`
+
+	// Record is the current Whois record
+	Record *whoisapi.WhoisRecord `json:"-"`
+}
+
+// Notifier is called for every monitor event
+type Notifier interface {
+	// Notify delivers the event
+	Notify(ctx context.Context, event Event) error
+}
+
+// NotifierFunc is an adapter to allow the use of ordinary functions as notifiers
+type NotifierFunc func(ctx context.Context, event Event) error
+
+// Notify calls f(ctx, event)
+func (f NotifierFunc) Notify(ctx context.Context, event Event) error {
+	return f(ctx, event)
+}
+
+// Clock is the source of time for the monitor
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+
+	// After waits for the duration to elapse and then sends the current time on the returned channel
+	After(d time.Duration) <-chan time.Time
+}
+
+// systemClock is the Clock implementation based on the time package
+type systemClock struct{}
+
+// Now returns the current time
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// After waits for the duration to elapse
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Params is used to create Monitor. Only Service and Domains are mandatory
+type Params struct {
+	// Service is used to fetch Whois records
+	Service whoisapi.WhoisService
+
+	// Domains is the portfolio of domain names to monitor
+	Domains []string
+
+	// Options are passed to every WhoisService.Data call
+	Options []whoisapi.Option
+
+	// Interval is the period between refreshes of the same domain name. DefaultInterval is used if zero
+	Interval time.Duration
+
+	// RetryInterval is the delay before refreshing a domain name after a failed lookup.
+	// DefaultRetryInterval is used if zero
+	RetryInterval time.Duration
+
+	// Jitter is the maximum random delay added to every scheduled refresh, including the first one
+	// of every domain name, to spread the load. One tenth of Interval is used if zero, negative value disables jitter
+	Jitter time.Duration
+
+	// Thresholds are the numbers of days before expiry when notifiers are called.
+	// DefaultThresholds are used if empty
+	Thresholds []int
+
+	// Notifiers are called for every event
+	Notifiers []Notifier
+
+	// Store persists the monitor state between runs. The state is kept in memory only if nil
+	Store Store
+
+	// Clock is the source of time. The system clock is used if nil
+	Clock Clock
+}
+
+// Monitor periodically refreshes Whois records of the domain names and calls notifiers
+type Monitor struct {
+	params Params
+	store  Store
+	clock  Clock
+
+	mu    sync.Mutex
+	rand  *rand.Rand
+	state State
+}
+
+// New creates Monitor with specified parameters and loads its state from the Store
+func New(params Params) (*Monitor, error) {
+	if params.Service == nil {
+		return nil, &whoisapi.ArgError{Name: "Service", Message: "cannot be nil"}
+	}
+
+	if params.Interval <= 0 {
+		params.Interval = DefaultInterval
+	}
+	if params.RetryInterval <= 0 {
+		params.RetryInterval = DefaultRetryInterval
+	}
+	if params.Jitter == 0 {
+		params.Jitter = params.Interval / 10
+	}
+	if len(params.Thresholds) == 0 {
+		params.Thresholds = DefaultThresholds
+	}
+
+	thresholds := make([]int, len(params.Thresholds))
+	copy(thresholds, params.Thresholds)
+	sort.Sort(sort.Reverse(sort.IntSlice(thresholds)))
+	params.Thresholds = thresholds
+
+	m := &Monitor{
+		params: params,
+		store:  params.Store,
+		clock:  params.Clock,
+	}
+
+	if m.store == nil {
+		m.store = &MemoryStore{}
+	}
+	if m.clock == nil {
+		m.clock = systemClock{}
+	}
+
+	m.rand = rand.New(rand.NewSource(m.clock.Now().UnixNano()))
+
+	state, err := m.store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("cannot load state: %w", err)
+	}
+	if state == nil {
+		state = State{}
+	}
+	m.state = state
+
+	return m, nil
+}
+
+// Run refreshes domain names as they become due until the context is cancelled
+func (m *Monitor) Run(ctx context.Context) error {
+	for {
+		if err := m.RunOnce(ctx); err != nil {
+			return err
+		}
+
+		wait := m.nextDue().Sub(m.clock.Now())
+		if wait < 0 {
+			wait = 0
+		}
+		if wait > m.params.Interval {
+			wait = m.params.Interval
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-m.clock.After(wait):
+		}
+	}
+}
+
+// RunOnce refreshes all domain names that are due, calls notifiers and saves the state.
+// Lookup and notifier failures are recorded in the domain state, the returned error is
+// either the context error or the Store error
+func (m *Monitor) RunOnce(ctx context.Context) error {
+	for _, domain := range m.params.Domains {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if domain == "" || !m.due(domain) {
+			continue
+		}
+
+		m.refresh(ctx, domain)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.store.Save(m.state); err != nil {
+		return fmt.Errorf("cannot save state: %w", err)
+	}
+
+	return nil
+}
+
+// State returns a copy of the domain name state
+func (m *Monitor) State(domain string) (DomainState, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	st, ok := m.state[strings.ToLower(domain)]
+	if !ok {
+		return DomainState{}, false
+	}
+	return *st, true
+}
+
+// due reports whether the domain name needs to be refreshed. The first refreshes of new domain names
+// are spread over Jitter, so a new portfolio doesn't hit the API at once
+func (m *Monitor) due(domain string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.clock.Now()
+	st, ok := m.state[domain]
+	if !ok {
+		st = &DomainState{NextCheck: m.schedule(now, 0)}
+		m.state[domain] = st
+	}
+	return !now.Before(st.NextCheck)
+}
+
+// nextDue returns the earliest time when any of the domain names needs to be refreshed
+func (m *Monitor) nextDue() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	next := m.clock.Now().Add(m.params.Interval)
+	for _, domain := range m.params.Domains {
+		st, ok := m.state[strings.ToLower(strings.TrimSpace(domain))]
+		if !ok {
+			return m.clock.Now()
+		}
+		if st.NextCheck.Before(next) {
+			next = st.NextCheck
+		}
+	}
+	return next
+}
+
+// schedule returns the time of the next refresh after the delay, with jitter applied
+func (m *Monitor) schedule(now time.Time, delay time.Duration) time.Time {
+	if m.params.Jitter > 0 {
+		delay += time.Duration(m.rand.Int63n(int64(m.params.Jitter)))
+	}
+	return now.Add(delay)
+}
+
+// refresh fetches the Whois record of the domain name and calls notifiers
+func (m *Monitor) refresh(ctx context.Context, domain string) {
+	rec, _, err := m.params.Service.Data(ctx, domain, m.params.Options...)
+
+	m.mu.Lock()
+	now := m.clock.Now()
+
+	st, ok := m.state[domain]
+	if !ok {
+		st = &DomainState{}
+		m.state[domain] = st
+	}
+	st.LastChecked = now
+
+	if err != nil {
+		st.LastError = err.Error()
+		st.NextCheck = m.schedule(now, m.params.RetryInterval)
+		m.mu.Unlock()
+		return
+	}
+
+	st.LastError = ""
+	st.NextCheck = m.schedule(now, m.params.Interval)
+
+	var events []Event
+
+	// a missing record is not compared, so its fields are not reported as removed
+	if st.Record != nil && rec != nil {
+		if changes := whoisapi.Diff(st.Record, rec); len(changes) > 0 {
+			events = append(events, Event{
+				Type:    EventChanged,
+				Domain:  domain,
+				Time:    now,
+				Changes: changes,
+				Record:  rec,
+			})
+		}
+	}
+
+	// the last known expiry date is kept while the date is unknown, so the thresholds are not reported again
+	expiresAt, ok := ExpiresAt(rec)
+	if ok && !expiresAt.Equal(st.ExpiresAt) {
+		// the domain name was renewed or its expiry date became known
+		st.ExpiresAt = expiresAt
+		st.Notified = nil
+	}
+
+	var threshold int
+	var crossed []int
+	if ok {
+		daysLeft := DaysLeft(expiresAt, now)
+		for _, th := range m.params.Thresholds {
+			if daysLeft <= th && !st.notified(th) {
+				threshold = th
+				crossed = append(crossed, th)
+			}
+		}
+		if len(crossed) > 0 {
+			events = append(events, Event{
+				Type:      EventExpiring,
+				Domain:    domain,
+				Time:      now,
+				ExpiresAt: expiresAt,
+				DaysLeft:  daysLeft,
+				Threshold: threshold,
+				Record:    rec,
+			})
+		}
+	}
+	m.mu.Unlock()
+
+	var notifyErr error
+	failed := make(map[EventType]bool)
+	for _, event := range events {
+		if err := m.notify(ctx, event); err != nil {
+			failed[event.Type] = true
+			if notifyErr == nil {
+				notifyErr = err
+			}
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// the record is kept until the changes are delivered, so they are reported again on the next refresh
+	if !failed[EventChanged] && rec != nil {
+		st.Record = rec
+	}
+	// the thresholds are not marked as notified until delivered, so they are retried on the next refresh.
+	// Only the closest threshold is reported, the farther ones are not reported later
+	if !failed[EventExpiring] {
+		st.Notified = append(st.Notified, crossed...)
+	}
+
+	if notifyErr != nil {
+		st.LastError = notifyErr.Error()
+		st.NextCheck = m.schedule(now, m.params.RetryInterval)
+	}
+}
+
+// notify calls all notifiers and returns the first error
+func (m *Monitor) notify(ctx context.Context, event Event) error {
+	var firstErr error
+	for _, n := range m.params.Notifiers {
+		if err := n.Notify(ctx, event); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("cannot notify about %s %s: %w", event.Type, event.Domain, err)
+		}
+	}
+	return firstErr
+}
+
+// DaysLeft returns the number of whole days from now until the expiry date,
+// negative if the date is in the past
+func DaysLeft(expiresAt, now time.Time) int {
+	d := expiresAt.Sub(now)
+	days := int(d / (24 * time.Hour))
+	if d < 0 && d%(24*time.Hour) != 0 {
+		days--
+	}
+	return days
+}
+
+// ExpiresAt returns the expiry date of the Whois record. It uses ExpiresDateNormalized,
+// then parses ExpiresDate and then does the same for RegistryData
+func ExpiresAt(rec *whoisapi.WhoisRecord) (time.Time, bool) {
+	if rec == nil {
+		return time.Time{}, false
+	}
+
+	t, source := rec.Expires()
+	return t, source != whoisapi.DateSourceNone
+}
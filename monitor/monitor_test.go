@@ -0,0 +1,492 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	whoisapi "github.com/whois-api-llc/whois-api-go"
+)
+
+// fakeClock is the Clock implementation for testing
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// Now returns the fake time
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After moves the fake time forward and fires immediately
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+// Advance moves the fake time forward
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// fakeService is the WhoisService implementation for testing
+type fakeService struct {
+	mu      sync.Mutex
+	records map[string]*whoisapi.WhoisRecord
+	calls   int
+}
+
+// Data returns the stored record
+func (s *fakeService) Data(_ context.Context, name string, _ ...whoisapi.Option) (*whoisapi.WhoisRecord, *whoisapi.Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	rec, ok := s.records[name]
+	if !ok {
+		return nil, nil, whoisapi.ErrorMessage{ErrorCode: "WHOIS_01", Message: "not found"}
+	}
+	if rec == nil {
+		return nil, nil, nil
+	}
+	cp := *rec
+	return &cp, nil, nil
+}
+
+// RawData is not used by the monitor
+func (s *fakeService) RawData(context.Context, string, ...whoisapi.Option) (*whoisapi.Response, error) {
+	return nil, errors.New("not implemented")
+}
+
+// IPData is not used by the monitor
+func (s *fakeService) IPData(context.Context, netip.Addr, ...whoisapi.Option) (*whoisapi.IPWhoisRecord, *whoisapi.Response, error) {
+	return nil, nil, errors.New("not implemented")
+}
+
+// record returns the Whois record with the expiry date
+func record(domain string, expires time.Time) *whoisapi.WhoisRecord {
+	rec := &whoisapi.WhoisRecord{}
+	rec.DomainName = domain
+	rec.ExpiresDateNormalized = whoisapi.Time(expires)
+	return rec
+}
+
+// TestMonitorThresholds tests the expiry notifications
+func TestMonitorThresholds(t *testing.T) {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := &fakeClock{now: start}
+
+	service := &fakeService{records: map[string]*whoisapi.WhoisRecord{
+		"example.com": record("example.com", start.Add(20*24*time.Hour)),
+	}}
+
+	var events []Event
+	m, err := New(Params{
+		Service:    service,
+		Domains:    []string{"Example.com", "missing.com"},
+		Interval:   24 * time.Hour,
+		Jitter:     -1,
+		Thresholds: []int{7, 30, 14},
+		Notifiers: []Notifier{NotifierFunc(func(_ context.Context, event Event) error {
+			events = append(events, event)
+			return nil
+		})},
+		Clock: clock,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 20 days left: only the 30 days threshold is crossed
+	checkErr(t, m.RunOnce(context.Background()), "")
+
+	// not due yet
+	checkErr(t, m.RunOnce(context.Background()), "")
+
+	// 13 days left: the 14 days threshold is crossed
+	clock.Advance(7 * 24 * time.Hour)
+	checkErr(t, m.RunOnce(context.Background()), "")
+
+	// 12 days left: nothing new
+	clock.Advance(24 * time.Hour)
+	checkErr(t, m.RunOnce(context.Background()), "")
+
+	var got []int
+	for _, event := range events {
+		if event.Type != EventExpiring {
+			t.Errorf("unexpected event %v", event)
+		}
+		got = append(got, event.Threshold, event.DaysLeft)
+	}
+	if want := []int{30, 20, 14, 13}; !reflect.DeepEqual(got, want) {
+		t.Errorf("thresholds and days got = %v, want %v", got, want)
+	}
+
+	if service.calls != 6 {
+		t.Errorf("calls got = %v, want 6", service.calls)
+	}
+
+	st, ok := m.State("missing.com")
+	if !ok || st.LastError == "" {
+		t.Errorf("State() got = %v, expected the error", st)
+	}
+	if !st.NextCheck.Equal(clock.Now().Add(DefaultRetryInterval)) {
+		t.Errorf("State().NextCheck got = %v, expected retry", st.NextCheck)
+	}
+}
+
+// TestMonitorRenewalAndChanges tests that renewal resets thresholds and changes are reported
+func TestMonitorRenewalAndChanges(t *testing.T) {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := &fakeClock{now: start}
+
+	service := &fakeService{records: map[string]*whoisapi.WhoisRecord{
+		"example.com": record("example.com", start.Add(5*24*time.Hour)),
+	}}
+
+	var events []Event
+	fail := true
+	m, err := New(Params{
+		Service:    service,
+		Domains:    []string{"example.com"},
+		Jitter:     -1,
+		Thresholds: []int{7},
+		Notifiers: []Notifier{NotifierFunc(func(_ context.Context, event Event) error {
+			if fail {
+				fail = false
+				return errors.New("unavailable")
+			}
+			events = append(events, event)
+			return nil
+		})},
+		Clock: clock,
+		Store: &FileStore{Path: filepath.Join(t.TempDir(), "state.json")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the notifier fails, so the threshold is retried
+	checkErr(t, m.RunOnce(context.Background()), "")
+	clock.Advance(DefaultRetryInterval)
+	checkErr(t, m.RunOnce(context.Background()), "")
+
+	// the domain is renewed
+	service.records["example.com"] = record("example.com", start.Add(370*24*time.Hour))
+	clock.Advance(DefaultInterval)
+	checkErr(t, m.RunOnce(context.Background()), "")
+
+	if len(events) != 2 {
+		t.Fatalf("events got = %v, want 2", events)
+	}
+	if events[0].Type != EventExpiring || events[0].DaysLeft != 4 {
+		t.Errorf("events[0] got = %v", events[0])
+	}
+	if events[1].Type != EventChanged || len(events[1].Changes) != 1 ||
+		events[1].Changes[0].Path != "expiresDateNormalized" {
+		t.Errorf("events[1] got = %v", events[1])
+	}
+
+	st, _ := m.State("example.com")
+	if st.Notified != nil {
+		t.Errorf("State().Notified got = %v, want nil after renewal", st.Notified)
+	}
+}
+
+// TestMonitorChangeRetried tests that a change is reported again when its delivery fails
+func TestMonitorChangeRetried(t *testing.T) {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := &fakeClock{now: start}
+
+	service := &fakeService{records: map[string]*whoisapi.WhoisRecord{
+		"example.com": record("example.com", start.Add(300*24*time.Hour)),
+	}}
+
+	var events []Event
+	fail := false
+	m, err := New(Params{
+		Service:    service,
+		Domains:    []string{"example.com"},
+		Jitter:     -1,
+		Thresholds: []int{7},
+		Notifiers: []Notifier{NotifierFunc(func(_ context.Context, event Event) error {
+			if fail {
+				fail = false
+				return errors.New("unavailable")
+			}
+			events = append(events, event)
+			return nil
+		})},
+		Clock: clock,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkErr(t, m.RunOnce(context.Background()), "")
+
+	// the record changes and the first delivery fails
+	service.records["example.com"] = record("example.com", start.Add(600*24*time.Hour))
+	fail = true
+	clock.Advance(DefaultInterval)
+	checkErr(t, m.RunOnce(context.Background()), "")
+
+	st, _ := m.State("example.com")
+	if st.LastError == "" || !st.NextCheck.Equal(clock.Now().Add(DefaultRetryInterval)) {
+		t.Errorf("State() got = %+v, expected the notifier error and retry", st)
+	}
+
+	clock.Advance(DefaultRetryInterval)
+	checkErr(t, m.RunOnce(context.Background()), "")
+
+	// the change is delivered once
+	clock.Advance(DefaultInterval)
+	checkErr(t, m.RunOnce(context.Background()), "")
+
+	if len(events) != 1 || events[0].Type != EventChanged || len(events[0].Changes) != 1 {
+		t.Fatalf("events got = %v, want one change", events)
+	}
+	if st, _ := m.State("example.com"); st.LastError != "" {
+		t.Errorf("State().LastError got = %q, want empty", st.LastError)
+	}
+}
+
+// TestMonitorUnknownExpiry tests that a missing expiry date or record doesn't reset the notifications
+func TestMonitorUnknownExpiry(t *testing.T) {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := &fakeClock{now: start}
+	expires := start.Add(20 * 24 * time.Hour)
+
+	service := &fakeService{records: map[string]*whoisapi.WhoisRecord{
+		"example.com": record("example.com", expires),
+	}}
+
+	var events []Event
+	m, err := New(Params{
+		Service:    service,
+		Domains:    []string{"example.com"},
+		Jitter:     -1,
+		Thresholds: []int{30, 7},
+		Notifiers: []Notifier{NotifierFunc(func(_ context.Context, event Event) error {
+			events = append(events, event)
+			return nil
+		})},
+		Clock: clock,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkErr(t, m.RunOnce(context.Background()), "")
+
+	// the expiry date is missing, then there is no record at all
+	unknown := record("example.com", time.Time{})
+	unknown.Status = "clientTransferProhibited"
+	for _, rec := range []*whoisapi.WhoisRecord{unknown, nil, record("example.com", expires)} {
+		service.records["example.com"] = rec
+		clock.Advance(DefaultInterval)
+		checkErr(t, m.RunOnce(context.Background()), "")
+
+		if st, _ := m.State("example.com"); !st.ExpiresAt.Equal(expires) || st.Record == nil {
+			t.Errorf("State() got = %+v, want the last known expiry date and record", st)
+		}
+	}
+
+	var types []EventType
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+	// the status is added and removed, the 30 days threshold is reported once
+	want := []EventType{EventExpiring, EventChanged, EventChanged}
+	if !reflect.DeepEqual(types, want) {
+		t.Errorf("events got = %v, want %v", types, want)
+	}
+}
+
+// TestMonitorRun tests the scheduler loop
+func TestMonitorRun(t *testing.T) {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := &fakeClock{now: start}
+
+	service := &fakeService{records: map[string]*whoisapi.WhoisRecord{
+		"example.com": record("example.com", start.Add(100*24*time.Hour)),
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m, err := New(Params{
+		Service:  service,
+		Domains:  []string{"example.com"},
+		Interval: time.Hour,
+		Notifiers: []Notifier{NotifierFunc(func(_ context.Context, event Event) error {
+			return nil
+		})},
+		Clock: clock,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		for {
+			if clock.Now().Sub(start) > 10*time.Hour {
+				cancel()
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	err = m.Run(ctx)
+	checkErr(t, err, context.Canceled.Error())
+
+	st, _ := m.State("example.com")
+	if d := st.NextCheck.Sub(st.LastChecked); d < time.Hour || d >= time.Hour+6*time.Minute {
+		t.Errorf("NextCheck got = %v after LastChecked, expected interval with jitter", d)
+	}
+	if service.calls < 9 {
+		t.Errorf("calls got = %v, expected hourly refreshes", service.calls)
+	}
+}
+
+// TestMonitorInitialJitter tests that the first refreshes of the domain names are spread over Jitter
+func TestMonitorInitialJitter(t *testing.T) {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := &fakeClock{now: start}
+
+	domains := []string{"a.com", "b.com", "c.com", "d.com", "e.com"}
+	service := &fakeService{records: map[string]*whoisapi.WhoisRecord{}}
+	for _, domain := range domains {
+		service.records[domain] = record(domain, start.Add(100*24*time.Hour))
+	}
+
+	m, err := New(Params{
+		Service: service,
+		Domains: domains,
+		Jitter:  time.Hour,
+		Clock:   clock,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkErr(t, m.RunOnce(context.Background()), "")
+
+	seen := make(map[time.Time]bool)
+	for _, domain := range domains {
+		st, _ := m.State(domain)
+		if st.NextCheck.Before(start) || !st.NextCheck.Before(start.Add(time.Hour)) {
+			t.Errorf("%s NextCheck got = %v, want within the jitter", domain, st.NextCheck)
+		}
+		seen[st.NextCheck] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("NextCheck got = %v, want the first refreshes spread", seen)
+	}
+
+	clock.Advance(time.Hour)
+	checkErr(t, m.RunOnce(context.Background()), "")
+	if service.calls != len(domains) {
+		t.Errorf("calls got = %d, want %d", service.calls, len(domains))
+	}
+}
+
+// TestExpiresAt tests the expiry date fallbacks
+func TestExpiresAt(t *testing.T) {
+	want := time.Date(2027, 3, 19, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		modify func(rec *whoisapi.WhoisRecord)
+		ok     bool
+	}{
+		{
+			name: "normalized",
+			modify: func(rec *whoisapi.WhoisRecord) {
+				rec.ExpiresDateNormalized = whoisapi.Time(want)
+			},
+			ok: true,
+		},
+		{
+			name: "raw",
+			modify: func(rec *whoisapi.WhoisRecord) {
+				rec.ExpiresDate = "2027-03-19T00:00:00Z"
+			},
+			ok: true,
+		},
+		{
+			name: "registry normalized",
+			modify: func(rec *whoisapi.WhoisRecord) {
+				rec.RegistryData.ExpiresDateNormalized = whoisapi.Time(want)
+			},
+			ok: true,
+		},
+		{
+			name: "registry raw",
+			modify: func(rec *whoisapi.WhoisRecord) {
+				rec.RegistryData.ExpiresDate = "19-Mar-2027"
+			},
+			ok: true,
+		},
+		{
+			name: "unknown",
+			modify: func(rec *whoisapi.WhoisRecord) {
+				rec.ExpiresDate = "soon"
+			},
+			ok: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &whoisapi.WhoisRecord{}
+			tt.modify(rec)
+
+			got, ok := ExpiresAt(rec)
+			if ok != tt.ok {
+				t.Fatalf("ExpiresAt() ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && !got.Equal(want) {
+				t.Errorf("ExpiresAt() got = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+// TestDaysLeft tests the DaysLeft function
+func TestDaysLeft(t *testing.T) {
+	now := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		expires time.Time
+		want    int
+	}{
+		{now.Add(30*24*time.Hour + time.Hour), 30},
+		{now.Add(time.Hour), 0},
+		{now, 0},
+		{now.Add(-time.Hour), -1},
+		{now.Add(-48 * time.Hour), -2},
+	}
+	for _, tt := range tests {
+		if got := DaysLeft(tt.expires, now); got != tt.want {
+			t.Errorf("DaysLeft(%v) got = %v, want %v", tt.expires, got, tt.want)
+		}
+	}
+}
+
+// checkErr checks for an error
+func checkErr(t *testing.T, err error, want string) {
+	if (err != nil || want != "") && (err == nil || err.Error() != want) {
+		t.Errorf("error = %v, wantErr %v", err, want)
+	}
+}
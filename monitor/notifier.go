@@ -0,0 +1,248 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"text/template"
+	"time"
+)
+
+const (
+	// DefaultRetryAttempts is the default number of delivery attempts
+	DefaultRetryAttempts = 3
+
+	// DefaultRetryBackoff is the default delay before the second delivery attempt.
+	// The delay is doubled for every following attempt
+	DefaultRetryBackoff = time.Second
+)
+
+// DefaultSubjectTemplate is the default template of the message subject
+var DefaultSubjectTemplate = template.Must(template.New("subject").Parse(
+	`{{if eq .Type "expiring"}}{{.Domain}} expires in {{.DaysLeft}} days{{else}}{{.Domain}} Whois record changed{{end}}`))
+
+// DefaultTextTemplate is the default template of the message text.
+// Templates are executed with Event, so any WhoisRecord field is available as {{.Record.FieldName}}
+var DefaultTextTemplate = template.Must(template.New("text").Parse(
+	`{{if eq .Type "expiring"}}{{.Domain}} expires in {{.DaysLeft}} days on {{.ExpiresAt.Format "2006-01-02"}}` +
+		`{{with .Record}}{{if .RegistrarName}}, registrar: {{.RegistrarName}}{{end}}{{end}}` +
+		`{{else}}{{.Domain}} Whois record changed:{{range .Changes}}` + "\n" + `- {{.}}{{end}}{{end}}`))
+
+// ID returns the event identifier used to detect duplicates. Events with the same type,
+// domain name, expiry date, threshold and changes have the same identifier
+func (e Event) ID() string {
+	h := sha256.New()
+	_, _ = io.WriteString(h, string(e.Type)+"\n"+e.Domain+"\n"+e.ExpiresAt.UTC().Format(time.RFC3339)+"\n")
+	if e.Type == EventExpiring {
+		_, _ = io.WriteString(h, strconv.Itoa(e.Threshold)+"\n")
+	}
+	for _, c := range e.Changes {
+		_, _ = io.WriteString(h, c.String()+"\n")
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// render executes the template with the event
+func render(tmpl *template.Template, event Event) (string, error) {
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, event); err != nil {
+		return "", fmt.Errorf("cannot render template %q: %w", tmpl.Name(), err)
+	}
+	return b.String(), nil
+}
+
+// RetryPolicy describes how many times and how often the delivery is retried
+type RetryPolicy struct {
+	// Attempts is the total number of attempts. DefaultRetryAttempts is used if zero
+	Attempts int
+
+	// Backoff is the delay before the second attempt, doubled for every following one.
+	// DefaultRetryBackoff is used if zero
+	Backoff time.Duration
+}
+
+// permanentError is the delivery error that is not retried
+type permanentError struct {
+	err error
+}
+
+// Error returns error message as a string
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// do calls fn until it succeeds, returns a permanent error or the attempts are exhausted
+func (p RetryPolicy) do(ctx context.Context, fn func(ctx context.Context) error) error {
+	attempts := p.Attempts
+	if attempts <= 0 {
+		attempts = DefaultRetryAttempts
+	}
+	backoff := p.Backoff
+	if backoff <= 0 {
+		backoff = DefaultRetryBackoff
+	}
+
+	var err error
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		err = fn(ctx)
+
+		var perm *permanentError
+		if err == nil || errors.As(err, &perm) {
+			return err
+		}
+	}
+
+	return fmt.Errorf("giving up after %d attempts: %w", attempts, err)
+}
+
+const (
+	// dedupTTL is the time a delivered event is remembered
+	dedupTTL = 30 * 24 * time.Hour
+
+	// dedupSize is the maximum number of remembered events, the oldest ones are forgotten first
+	dedupSize = 10000
+)
+
+// deduper remembers delivered events for dedupTTL. The zero value is ready to use
+type deduper struct {
+	mu       sync.Mutex
+	sent     map[string]time.Time
+	order    []string
+	inflight map[string]chan struct{}
+
+	// now and size replace time.Now and dedupSize in tests
+	now  func() time.Time
+	size int
+}
+
+// deliver calls fn unless the event was already delivered. Concurrent calls with the same event
+// wait for the one in flight and call fn only if it failed
+func (d *deduper) deliver(ctx context.Context, event Event, fn func() error) error {
+	id := event.ID()
+
+	var done chan struct{}
+	for done == nil {
+		d.mu.Lock()
+		d.prune()
+		if _, ok := d.sent[id]; ok {
+			d.mu.Unlock()
+			return nil
+		}
+		wait, ok := d.inflight[id]
+		if !ok {
+			done = make(chan struct{})
+			if d.inflight == nil {
+				d.inflight = make(map[string]chan struct{})
+			}
+			d.inflight[id] = done
+		}
+		d.mu.Unlock()
+
+		if ok {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-wait:
+			}
+		}
+	}
+
+	err := fn()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.inflight, id)
+	close(done)
+	if err != nil {
+		return err
+	}
+
+	if d.sent == nil {
+		d.sent = make(map[string]time.Time)
+	}
+	d.sent[id] = d.timeNow()
+	d.order = append(d.order, id)
+	d.prune()
+
+	return nil
+}
+
+// prune forgets the events delivered more than dedupTTL ago and the oldest ones above the size limit
+func (d *deduper) prune() {
+	size := d.size
+	if size <= 0 {
+		size = dedupSize
+	}
+	now := d.timeNow()
+
+	n := 0
+	for n < len(d.order) && (len(d.order)-n > size || now.Sub(d.sent[d.order[n]]) >= dedupTTL) {
+		delete(d.sent, d.order[n])
+		n++
+	}
+	d.order = d.order[n:]
+}
+
+// timeNow returns the current time
+func (d *deduper) timeNow() time.Time {
+	if d.now == nil {
+		return time.Now()
+	}
+	return d.now()
+}
+
+// post sends the JSON body and checks the response status code.
+// Client errors except 429 are permanent
+func post(ctx context.Context, client *http.Client, url string, body []byte, header http.Header) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return &permanentError{err}
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if c := resp.StatusCode; c >= 200 && c <= 299 {
+		return nil
+	}
+
+	err = errors.New("webhook failed with status code: " + strconv.Itoa(resp.StatusCode))
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		return &permanentError{err}
+	}
+	return err
+}
@@ -0,0 +1,402 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	whoisapi "github.com/whois-api-llc/whois-api-go"
+)
+
+// testEvents returns the events for testing
+func testEvents() (Event, Event) {
+	rec := &whoisapi.WhoisRecord{}
+	rec.DomainName = "example.com"
+	rec.RegistrarName = "GoDaddy.com, LLC"
+
+	expiring := Event{
+		Type:      EventExpiring,
+		Domain:    "example.com",
+		Time:      time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		ExpiresAt: time.Date(2022, 1, 8, 0, 0, 0, 0, time.UTC),
+		DaysLeft:  7,
+		Threshold: 7,
+		Record:    rec,
+	}
+	changed := Event{
+		Type:   EventChanged,
+		Domain: "example.com",
+		Time:   time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		Changes: []whoisapi.Change{
+			{Path: "registrarName", Kind: whoisapi.ChangeModified, Old: "A", New: "B"},
+		},
+		Record: rec,
+	}
+	return expiring, changed
+}
+
+// webhookServer is the sample webhook receiver that fails the first failures requests
+func webhookServer(failures int, status int) (*httptest.Server, *[]*http.Request, *[][]byte) {
+	var mu sync.Mutex
+	var requests []*http.Request
+	var bodies [][]byte
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		body, _ := io.ReadAll(req.Body)
+		requests = append(requests, req)
+		bodies = append(bodies, body)
+
+		if len(requests) <= failures {
+			w.WriteHeader(status)
+		}
+	}))
+
+	return server, &requests, &bodies
+}
+
+// TestWebhookNotifier tests the signed webhook delivery, retries and deduplication
+func TestWebhookNotifier(t *testing.T) {
+	expiring, changed := testEvents()
+
+	tests := []struct {
+		name      string
+		failures  int
+		status    int
+		wantCalls int
+		wantErr   string
+	}{
+		{
+			name:      "delivered",
+			wantCalls: 1,
+		},
+		{
+			name:      "retried",
+			failures:  2,
+			status:    http.StatusServiceUnavailable,
+			wantCalls: 3,
+		},
+		{
+			name:      "gave up",
+			failures:  3,
+			status:    http.StatusInternalServerError,
+			wantCalls: 3,
+			wantErr:   "giving up after 3 attempts: webhook failed with status code: 500",
+		},
+		{
+			name:      "permanent error",
+			failures:  1,
+			status:    http.StatusBadRequest,
+			wantCalls: 1,
+			wantErr:   "webhook failed with status code: 400",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests, bodies := webhookServer(tt.failures, tt.status)
+			defer server.Close()
+
+			secret := []byte("secret")
+			n := &WebhookNotifier{
+				URL:        server.URL,
+				Secret:     secret,
+				HTTPClient: server.Client(),
+				Retry:      RetryPolicy{Backoff: time.Millisecond},
+			}
+
+			err := n.Notify(context.Background(), expiring)
+			checkErr(t, err, tt.wantErr)
+			if len(*requests) != tt.wantCalls {
+				t.Fatalf("calls got = %v, want %v", len(*requests), tt.wantCalls)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+
+			// the same event is not delivered twice, another one is
+			checkErr(t, n.Notify(context.Background(), expiring), "")
+			checkErr(t, n.Notify(context.Background(), changed), "")
+			if len(*requests) != tt.wantCalls+1 {
+				t.Fatalf("calls got = %v, want %v", len(*requests), tt.wantCalls+1)
+			}
+
+			req, body := (*requests)[0], (*bodies)[0]
+			if !VerifySignature(secret, body, req.Header.Get(SignatureHeader)) {
+				t.Errorf("invalid signature %v", req.Header.Get(SignatureHeader))
+			}
+			if req.Header.Get(EventIDHeader) != expiring.ID() {
+				t.Errorf("event id got = %v, want %v", req.Header.Get(EventIDHeader), expiring.ID())
+			}
+
+			var payload WebhookPayload
+			checkErr(t, json.Unmarshal(body, &payload), "")
+			want := "example.com expires in 7 days on 2022-01-08, registrar: GoDaddy.com, LLC"
+			if payload.Text != want {
+				t.Errorf("text got = %v, want %v", payload.Text, want)
+			}
+			if payload.Record == nil || payload.Record.DomainName != "example.com" {
+				t.Errorf("record got = %v", payload.Record)
+			}
+		})
+	}
+}
+
+// TestSlackNotifier tests the Slack-compatible webhook delivery
+func TestSlackNotifier(t *testing.T) {
+	_, changed := testEvents()
+
+	server, requests, bodies := webhookServer(0, 0)
+	defer server.Close()
+
+	n := &SlackNotifier{WebhookURL: server.URL, HTTPClient: server.Client()}
+
+	checkErr(t, n.Notify(context.Background(), changed), "")
+	checkErr(t, n.Notify(context.Background(), changed), "")
+	if len(*requests) != 1 {
+		t.Fatalf("calls got = %v, want 1", len(*requests))
+	}
+
+	want := `{"text":"example.com Whois record changed:\n- registrarName: \"A\" -\u003e \"B\""}`
+	if got := string((*bodies)[0]); got != want {
+		t.Errorf("body got = %v, want %v", got, want)
+	}
+}
+
+// smtpServer is the sample SMTP server that rejects the first failures recipients with the code
+func smtpServer(t *testing.T, failures int, code int) (string, *[]string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	var mu sync.Mutex
+	var messages []string
+	rejected := 0
+
+	serve := func(conn net.Conn) {
+		defer conn.Close()
+		tp := textproto.NewConn(conn)
+		_ = tp.PrintfLine("220 localhost ESMTP")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.Fields(line)[0])
+			switch cmd {
+			case "EHLO", "HELO":
+				_ = tp.PrintfLine("250-localhost")
+				_ = tp.PrintfLine("250 8BITMIME")
+			case "RCPT":
+				mu.Lock()
+				reject := rejected < failures
+				rejected++
+				mu.Unlock()
+				if reject {
+					_ = tp.PrintfLine("%d try later", code)
+					continue
+				}
+				_ = tp.PrintfLine("250 OK")
+			case "DATA":
+				_ = tp.PrintfLine("354 go ahead")
+				data, err := tp.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				mu.Lock()
+				messages = append(messages, string(data))
+				mu.Unlock()
+				_ = tp.PrintfLine("250 OK")
+			case "QUIT":
+				_ = tp.PrintfLine("221 bye")
+				return
+			default:
+				_ = tp.PrintfLine("250 OK")
+			}
+		}
+	}
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serve(conn)
+		}
+	}()
+
+	return ln.Addr().String(), &messages
+}
+
+// TestEmailNotifier tests the email delivery
+func TestEmailNotifier(t *testing.T) {
+	expiring, _ := testEvents()
+
+	tests := []struct {
+		name     string
+		failures int
+		code     int
+		wantErr  string
+		wantSent int
+	}{
+		{
+			name:     "delivered",
+			wantSent: 1,
+		},
+		{
+			name:     "retried",
+			failures: 1,
+			code:     451,
+			wantSent: 1,
+		},
+		{
+			name:     "permanent error",
+			failures: 1,
+			code:     550,
+			wantErr:  `550 "try later"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, messages := smtpServer(t, tt.failures, tt.code)
+
+			n := &EmailNotifier{
+				Addr:  addr,
+				From:  "monitor@example.com",
+				To:    []string{"admin@example.com"},
+				Retry: RetryPolicy{Backoff: time.Millisecond},
+			}
+
+			checkErr(t, n.Notify(context.Background(), expiring), tt.wantErr)
+			if tt.wantErr == "" {
+				checkErr(t, n.Notify(context.Background(), expiring), "")
+			}
+
+			if len(*messages) != tt.wantSent {
+				t.Fatalf("messages got = %v, want %v", len(*messages), tt.wantSent)
+			}
+			if tt.wantSent == 0 {
+				return
+			}
+
+			msg := (*messages)[0]
+			for _, want := range []string{
+				"Subject: example.com expires in 7 days\n",
+				"To: admin@example.com\n",
+				"example.com expires in 7 days on 2022-01-08, registrar: GoDaddy.com, LLC",
+			} {
+				if !strings.Contains(msg, want) {
+					t.Errorf("message %q does not contain %q", msg, want)
+				}
+			}
+		})
+	}
+}
+
+// TestEventID tests the event identifiers
+func TestEventID(t *testing.T) {
+	expiring, changed := testEvents()
+
+	if expiring.ID() == changed.ID() {
+		t.Errorf("ID() expected different identifiers")
+	}
+
+	other := expiring
+	other.Time = other.Time.Add(time.Hour)
+	other.DaysLeft = 6
+	if other.ID() != expiring.ID() {
+		t.Errorf("ID() expected the same identifier for the same threshold")
+	}
+
+	other.Threshold = 3
+	if other.ID() == expiring.ID() {
+		t.Errorf("ID() expected different identifiers for different thresholds")
+	}
+}
+
+// TestDeduper tests that concurrent deliveries of an event send it once and that delivered events are forgotten
+func TestDeduper(t *testing.T) {
+	expiring, changed := testEvents()
+
+	// the concurrent deliveries wait for the one in flight
+	var d deduper
+	var calls int32
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := d.deliver(context.Background(), expiring, func() error {
+				atomic.AddInt32(&calls, 1)
+				<-release
+				return nil
+			})
+			checkErr(t, err, "")
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if calls != 1 {
+		t.Errorf("calls got = %d, want 1", calls)
+	}
+
+	// a waiting delivery sends the event if the one in flight failed
+	var failed deduper
+	started := make(chan struct{})
+	errs := make(chan error, 1)
+	go func() {
+		errs <- failed.deliver(context.Background(), changed, func() error {
+			close(started)
+			time.Sleep(10 * time.Millisecond)
+			return errors.New("unavailable")
+		})
+	}()
+	<-started
+	retried := false
+	checkErr(t, failed.deliver(context.Background(), changed, func() error {
+		retried = true
+		return nil
+	}), "")
+	checkErr(t, <-errs, "unavailable")
+	if !retried {
+		t.Error("the event wasn't delivered after the failure")
+	}
+
+	// the delivered events are forgotten after dedupTTL and above the size limit
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	bounded := deduper{now: func() time.Time { return now }, size: 1}
+	send := func(event Event) bool {
+		sent := false
+		checkErr(t, bounded.deliver(context.Background(), event, func() error {
+			sent = true
+			return nil
+		}), "")
+		return sent
+	}
+	if !send(expiring) || send(expiring) {
+		t.Error("expiring event is not delivered once")
+	}
+	if !send(changed) || !send(expiring) {
+		t.Error("the oldest event is not forgotten above the size limit")
+	}
+	now = now.Add(dedupTTL)
+	if !send(expiring) || len(bounded.sent) != 1 {
+		t.Errorf("the event is not forgotten after dedupTTL, remembered %d", len(bounded.sent))
+	}
+}
@@ -0,0 +1,55 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"text/template"
+)
+
+// SlackNotifier posts events to a Slack-compatible incoming webhook
+type SlackNotifier struct {
+	// WebhookURL is the incoming webhook URL
+	WebhookURL string
+
+	// HTTPClient is the client used to call the webhook. http.DefaultClient is used if nil
+	HTTPClient *http.Client
+
+	// Template is used to render the message text. DefaultTextTemplate is used if nil
+	Template *template.Template
+
+	// Retry is the delivery retry policy
+	Retry RetryPolicy
+
+	dedupe deduper
+}
+
+var _ Notifier = &SlackNotifier{}
+
+// slackMessage is the incoming webhook payload
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+// Notify posts the event message to the webhook unless it was already delivered
+func (n *SlackNotifier) Notify(ctx context.Context, event Event) error {
+	return n.dedupe.deliver(ctx, event, func() error {
+		tmpl := n.Template
+		if tmpl == nil {
+			tmpl = DefaultTextTemplate
+		}
+		text, err := render(tmpl, event)
+		if err != nil {
+			return err
+		}
+
+		body, err := json.Marshal(slackMessage{Text: text})
+		if err != nil {
+			return err
+		}
+
+		return n.Retry.do(ctx, func(ctx context.Context) error {
+			return post(ctx, n.HTTPClient, n.WebhookURL, body, nil)
+		})
+	})
+}
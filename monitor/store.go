@@ -0,0 +1,142 @@
+package monitor
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	whoisapi "github.com/whois-api-llc/whois-api-go"
+)
+
+// DomainState is the persisted state of the monitored domain name
+type DomainState struct {
+	// LastChecked is the time of the last refresh
+	LastChecked time.Time `json:"lastChecked"`
+
+	// NextCheck is the time of the next scheduled refresh
+	NextCheck time.Time `json:"nextCheck"`
+
+	// LastError is the error of the last refresh, if any
+	LastError string `json:"lastError,omitempty"`
+
+	// ExpiresAt is the last known expiry date of the domain name, the zero time if it was never known
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	// Notified are the thresholds already reported for ExpiresAt
+	Notified []int `json:"notified,omitempty"`
+
+	// Record is the Whois record fetched on the last successful refresh
+	Record *whoisapi.WhoisRecord `json:"record,omitempty"`
+}
+
+// notified reports whether the threshold was already reported
+func (st *DomainState) notified(threshold int) bool {
+	for _, th := range st.Notified {
+		if th == threshold {
+			return true
+		}
+	}
+	return false
+}
+
+// State is the monitor state keyed by the domain name
+type State map[string]*DomainState
+
+// Store persists the monitor state between runs
+type Store interface {
+	// Load returns the saved state. It returns an empty state if nothing was saved yet
+	Load() (State, error)
+
+	// Save saves the state
+	Save(state State) error
+}
+
+// MemoryStore keeps the state in memory
+type MemoryStore struct {
+	mu    sync.Mutex
+	state []byte
+}
+
+var _ Store = &MemoryStore{}
+
+// Load returns the saved state
+func (s *MemoryStore) Load() (State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := State{}
+	if s.state == nil {
+		return state, nil
+	}
+	if err := json.Unmarshal(s.state, &state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// Save saves the state
+func (s *MemoryStore) Save(state State) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	s.state = b
+	return nil
+}
+
+// FileStore keeps the state in a JSON file
+type FileStore struct {
+	// Path is the path of the state file
+	Path string
+}
+
+var _ Store = &FileStore{}
+
+// Load reads the state from the file. It returns an empty state if the file does not exist
+func (s *FileStore) Load() (State, error) {
+	state := State{}
+
+	b, err := os.ReadFile(s.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, &state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// Save writes the state to a temporary file and renames it, so the file is never left half-written
+func (s *FileStore) Save(state State) error {
+	b, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.Path)
+}
@@ -0,0 +1,105 @@
+package monitor
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"text/template"
+
+	whoisapi "github.com/whois-api-llc/whois-api-go"
+)
+
+const (
+	// SignatureHeader is the header with the HMAC-SHA256 signature of the webhook body
+	SignatureHeader = "X-Whois-Signature"
+
+	// EventIDHeader is the header with the event identifier
+	EventIDHeader = "X-Whois-Event-Id"
+)
+
+// WebhookPayload is the JSON body sent by WebhookNotifier
+type WebhookPayload struct {
+	// ID is the event identifier
+	ID string `json:"id"`
+
+	// Event is the monitor event
+	Event Event `json:"event"`
+
+	// Text is the event rendered with the template
+	Text string `json:"text"`
+
+	// Record is the current Whois record
+	Record *whoisapi.WhoisRecord `json:"record,omitempty"`
+}
+
+// WebhookNotifier posts events as JSON to a generic webhook
+type WebhookNotifier struct {
+	// URL is the webhook URL
+	URL string
+
+	// Secret is the key used to sign the body. The body is not signed if empty
+	Secret []byte
+
+	// HTTPClient is the client used to call the webhook. http.DefaultClient is used if nil
+	HTTPClient *http.Client
+
+	// Template is used to render the Text field. DefaultTextTemplate is used if nil
+	Template *template.Template
+
+	// Retry is the delivery retry policy
+	Retry RetryPolicy
+
+	dedupe deduper
+}
+
+var _ Notifier = &WebhookNotifier{}
+
+// Notify posts the event to the webhook unless it was already delivered
+func (n *WebhookNotifier) Notify(ctx context.Context, event Event) error {
+	return n.dedupe.deliver(ctx, event, func() error {
+		tmpl := n.Template
+		if tmpl == nil {
+			tmpl = DefaultTextTemplate
+		}
+		text, err := render(tmpl, event)
+		if err != nil {
+			return err
+		}
+
+		body, err := json.Marshal(WebhookPayload{
+			ID:     event.ID(),
+			Event:  event,
+			Text:   text,
+			Record: event.Record,
+		})
+		if err != nil {
+			return err
+		}
+
+		header := http.Header{}
+		header.Set(EventIDHeader, event.ID())
+		if len(n.Secret) > 0 {
+			header.Set(SignatureHeader, Sign(n.Secret, body))
+		}
+
+		return n.Retry.do(ctx, func(ctx context.Context) error {
+			return post(ctx, n.HTTPClient, n.URL, body, header)
+		})
+	})
+}
+
+// Sign returns the signature of the body in the "sha256=<hex>" form
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature reports whether the signature matches the body. Webhook receivers can use it
+// to check the SignatureHeader value
+func VerifySignature(secret, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
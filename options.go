@@ -1,6 +1,7 @@
 package whoisapi
 
 import (
+	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
@@ -9,6 +10,20 @@ import (
 // Option adds parameters to the query
 type Option func(v url.Values)
 
+// RequestOption changes how a single call is sent, e.g. its API key or headers.
+// Use it with Client.With
+type RequestOption func(r *requestValues)
+
+// requestValues are the query parameters with per-call request overrides
+type requestValues struct {
+	url.Values
+
+	apiKey      string
+	baseURL     *url.URL
+	modifiers   []func(req *http.Request)
+	registrable bool
+}
+
 var _ = []Option{
 	OptionOutputFormat("JSON"),
 	OptionPreferFresh(0),
@@ -20,6 +35,14 @@ var _ = []Option{
 	OptionIgnoreRawTexts(0),
 }
 
+var _ = []RequestOption{
+	OptionAPIKey(""),
+	OptionBaseURL(nil),
+	OptionHeader("", ""),
+	OptionRequest(nil),
+	OptionRegistrableDomain(),
+}
+
 // OptionOutputFormat to set Response output format JSON | XML
 func OptionOutputFormat(outputFormat string) Option {
 	return func(v url.Values) {
@@ -76,3 +99,41 @@ func OptionIgnoreRawTexts(value int) Option {
 		v.Set("ignoreRawTexts", strconv.Itoa(value))
 	}
 }
+
+// OptionAPIKey to use a different API key for this call, e.g. for billing attribution
+func OptionAPIKey(apiKey string) RequestOption {
+	return func(r *requestValues) {
+		r.apiKey = apiKey
+	}
+}
+
+// OptionBaseURL to send this call to a different endpoint, e.g. a regional mirror
+func OptionBaseURL(baseURL *url.URL) RequestOption {
+	return func(r *requestValues) {
+		r.baseURL = baseURL
+	}
+}
+
+// OptionHeader to set the HTTP header of the outgoing request, e.g. a request ID
+func OptionHeader(key, value string) RequestOption {
+	return OptionRequest(func(req *http.Request) {
+		req.Header.Set(key, value)
+	})
+}
+
+// OptionRequest to modify the outgoing request after all parameters are set
+func OptionRequest(modify func(req *http.Request)) RequestOption {
+	return func(r *requestValues) {
+		if modify != nil {
+			r.modifiers = append(r.modifiers, modify)
+		}
+	}
+}
+
+// OptionRegistrableDomain to look up the registrable domain of the name, e.g. "example.co.uk" for
+// "mail.eu.example.co.uk". The subdomain is kept in Response.Subdomain, IP addresses are sent as is
+func OptionRegistrableDomain() RequestOption {
+	return func(r *requestValues) {
+		r.registrable = true
+	}
+}
@@ -1,8 +1,12 @@
 package whoisapi
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -73,3 +77,108 @@ func TestOptions(t *testing.T) {
 		})
 	}
 }
+
+// TestRequestOptions tests the per-call overrides of the API key, the base URL and headers
+func TestRequestOptions(t *testing.T) {
+	var got *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		got = req
+		_, _ = w.Write([]byte(`{"WhoisRecord": {"domainName": "whoisxmlapi.com"}}`))
+	}))
+	defer server.Close()
+
+	defaultURL, _ := url.Parse(server.URL + "/default")
+	mirrorURL, _ := url.Parse(server.URL + "/mirror")
+
+	tests := []struct {
+		name     string
+		params   ClientParams
+		reqOpts  []RequestOption
+		opts     []Option
+		wantPath string
+		wantKey  string
+		header   string
+	}{
+		{
+			name:     "no overrides",
+			wantPath: "/default",
+			wantKey:  apiKey,
+		},
+		{
+			name:     "api key",
+			reqOpts:  []RequestOption{OptionAPIKey("at_billing_key")},
+			wantPath: "/default",
+			wantKey:  "at_billing_key",
+		},
+		{
+			name:     "api key overrides the pool",
+			params:   ClientParams{KeyPool: NewKeyPool(KeyStrategyRoundRobin, APIKey{Key: "at_pool_key"})},
+			reqOpts:  []RequestOption{OptionAPIKey("at_billing_key")},
+			wantPath: "/default",
+			wantKey:  "at_billing_key",
+		},
+		{
+			name:     "base url and header",
+			reqOpts:  []RequestOption{OptionBaseURL(mirrorURL), OptionHeader("X-Request-Id", "42")},
+			opts:     []Option{OptionDA(2)},
+			wantPath: "/mirror",
+			wantKey:  apiKey,
+			header:   "42",
+		},
+		{
+			name: "request",
+			reqOpts: []RequestOption{OptionRequest(func(req *http.Request) {
+				req.Header.Set("X-Request-Id", "7")
+			})},
+			wantPath: "/default",
+			wantKey:  apiKey,
+			header:   "7",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := tt.params
+			params.HTTPClient = server.Client()
+			params.WhoisBaseURL = defaultURL
+
+			client := NewClient(apiKey, params)
+			if _, err := client.With(tt.reqOpts...).RawData(context.Background(), "whoisxmlapi.com", tt.opts...); err != nil {
+				t.Fatal(err)
+			}
+
+			if got.URL.Path != tt.wantPath {
+				t.Errorf("path got = %v, want %v", got.URL.Path, tt.wantPath)
+			}
+			if key := got.URL.Query().Get("apiKey"); key != tt.wantKey {
+				t.Errorf("apiKey got = %v, want %v", key, tt.wantKey)
+			}
+			if h := got.Header.Get("X-Request-Id"); h != tt.header {
+				t.Errorf("X-Request-Id got = %v, want %v", h, tt.header)
+			}
+		})
+	}
+
+	// the overrides don't leak into the calls of the client itself
+	client := NewClient(apiKey, ClientParams{HTTPClient: server.Client(), WhoisBaseURL: defaultURL})
+	client.With(OptionAPIKey("at_billing_key"), OptionHeader("X-Request-Id", "42"))
+	if _, err := client.RawData(context.Background(), "whoisxmlapi.com"); err != nil {
+		t.Fatal(err)
+	}
+	if key := got.URL.Query().Get("apiKey"); key != apiKey || got.Header.Get("X-Request-Id") != "" {
+		t.Errorf("request got = %v, %v", got.URL, got.Header)
+	}
+}
+
+// TestRequestOptionsTransportError tests that the per-call API key is not in transport errors
+func TestRequestOptionsTransportError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	apiURL, _ := url.Parse(server.URL)
+	client := NewClient(apiKey, ClientParams{HTTPClient: server.Client(), WhoisBaseURL: apiURL})
+
+	_, err := client.With(OptionAPIKey("at_billing_key")).RawData(context.Background(), "whoisxmlapi.com")
+	if err == nil || strings.Contains(err.Error(), "at_billing_key") {
+		t.Errorf("RawData() error = %v", err)
+	}
+}
@@ -0,0 +1,200 @@
+package rdap
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// IANA bootstrap files, RFC 9224
+const (
+	// BootstrapDNS is the URL of the IANA bootstrap file for domain names
+	BootstrapDNS = "https://data.iana.org/rdap/dns.json"
+
+	// BootstrapIPv4 is the URL of the IANA bootstrap file for IPv4 addresses
+	BootstrapIPv4 = "https://data.iana.org/rdap/ipv4.json"
+
+	// BootstrapIPv6 is the URL of the IANA bootstrap file for IPv6 addresses
+	BootstrapIPv6 = "https://data.iana.org/rdap/ipv6.json"
+)
+
+// DefaultIPServer is the RDAP server queried for IP addresses not covered by the bootstrap.
+// The RIRs redirect queries for the addresses of other regions
+const DefaultIPServer = "https://rdap.arin.net/registry/"
+
+//go:generate go run ./internal/bootstrapgen
+
+// The IANA bootstrap files bundled with the package, refresh them with go generate
+var (
+	//go:embed dns.json
+	bundledDNS []byte
+
+	//go:embed ipv4.json
+	bundledIPv4 []byte
+
+	//go:embed ipv6.json
+	bundledIPv6 []byte
+)
+
+// bootstrapFile is the bootstrap file format
+type bootstrapFile struct {
+	Services [][][]string `json:"services"`
+}
+
+// prefixService is the list of base URLs for the IP prefix
+type prefixService struct {
+	prefix netip.Prefix
+	urls   []string
+}
+
+// Bootstrap maps TLDs and IP prefixes to the base URLs of RDAP servers
+type Bootstrap struct {
+	domains  map[string][]string
+	prefixes []prefixService
+}
+
+// defaultBootstrap is the bundled bootstrap parsed on first use
+var defaultBootstrap = sync.OnceValue(func() *Bootstrap {
+	b, err := LoadBootstrap(bytes.NewReader(bundledDNS), bytes.NewReader(bundledIPv4), bytes.NewReader(bundledIPv6))
+	if err != nil {
+		panic(err)
+	}
+	return b
+})
+
+// DefaultBootstrap returns the bootstrap bundled with the package. The bundled files may be older
+// than the ones published by IANA, load the current ones with FetchBootstrap or LoadBootstrapFile
+func DefaultBootstrap() *Bootstrap {
+	return defaultBootstrap()
+}
+
+// LoadBootstrap loads the bootstrap from the files in the IANA format: dns.json, ipv4.json and ipv6.json.
+// Entries that are neither domain labels nor IP prefixes, like AS number ranges, are skipped
+func LoadBootstrap(files ...io.Reader) (*Bootstrap, error) {
+	b := &Bootstrap{domains: make(map[string][]string)}
+
+	for _, r := range files {
+		var f bootstrapFile
+		if err := json.NewDecoder(r).Decode(&f); err != nil {
+			return nil, fmt.Errorf("cannot parse bootstrap: %w", err)
+		}
+
+		for _, service := range f.Services {
+			if len(service) != 2 {
+				return nil, fmt.Errorf("cannot parse bootstrap: invalid service %q", service)
+			}
+			urls := baseURLs(service[1])
+			for _, entry := range service[0] {
+				if prefix, err := netip.ParsePrefix(entry); err == nil {
+					b.prefixes = append(b.prefixes, prefixService{prefix: prefix.Masked(), urls: urls})
+					continue
+				}
+				if strings.ContainsAny(entry, "-/:") {
+					continue
+				}
+				b.domains[strings.Trim(strings.ToLower(entry), ".")] = urls
+			}
+		}
+	}
+
+	// the most specific prefix wins
+	sort.SliceStable(b.prefixes, func(i, j int) bool {
+		return b.prefixes[i].prefix.Bits() > b.prefixes[j].prefix.Bits()
+	})
+
+	return b, nil
+}
+
+// LoadBootstrapFile loads the bootstrap from the files in the IANA format
+func LoadBootstrapFile(paths ...string) (*Bootstrap, error) {
+	files := make([]io.Reader, 0, len(paths))
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot open bootstrap: %w", err)
+		}
+		defer f.Close()
+		files = append(files, f)
+	}
+
+	return LoadBootstrap(files...)
+}
+
+// FetchBootstrap downloads the bootstrap files, e.g. BootstrapDNS, BootstrapIPv4 and BootstrapIPv6.
+// http.DefaultClient is used if client is nil
+func FetchBootstrap(ctx context.Context, client *http.Client, urls ...string) (*Bootstrap, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	files := make([]io.Reader, 0, len(urls))
+	for _, u := range urls {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("cannot fetch bootstrap: %w", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("cannot fetch bootstrap: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("cannot fetch bootstrap %s: %s", u, resp.Status)
+		}
+
+		files = append(files, bytes.NewReader(body))
+	}
+
+	return LoadBootstrap(files...)
+}
+
+// baseURLs returns the URLs with the trailing slash, HTTPS first
+func baseURLs(urls []string) []string {
+	result := make([]string, 0, len(urls))
+	for _, u := range urls {
+		if !strings.HasSuffix(u, "/") {
+			u += "/"
+		}
+		result = append(result, u)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return strings.HasPrefix(result[i], "https:") && !strings.HasPrefix(result[j], "https:")
+	})
+	return result
+}
+
+// DomainServers returns the base URLs of the RDAP servers for the domain name, the longest matching entry wins
+func (b *Bootstrap) DomainServers(name string) []string {
+	labels := strings.Split(strings.Trim(strings.ToLower(name), "."), ".")
+	for i := range labels {
+		if urls, ok := b.domains[strings.Join(labels[i:], ".")]; ok {
+			return urls
+		}
+	}
+	return nil
+}
+
+// IPServers returns the base URLs of the RDAP servers for the IP address, the most specific prefix wins
+func (b *Bootstrap) IPServers(ip netip.Addr) []string {
+	ip = ip.Unmap()
+	for _, p := range b.prefixes {
+		if p.prefix.Contains(ip) {
+			return p.urls
+		}
+	}
+	return nil
+}
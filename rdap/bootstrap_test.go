@@ -0,0 +1,116 @@
+package rdap
+
+import (
+	"encoding/json"
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestBootstrap tests the DomainServers and IPServers methods
+func TestBootstrap(t *testing.T) {
+	b, err := LoadBootstrap(
+		strings.NewReader(`{"services": [
+			[["uk"], ["https://rdap.nominet.uk/uk"]],
+			[["co.uk", "ORG.UK"], ["http://rdap.example/couk/", "https://rdap.example/couk/"]]
+		]}`),
+		strings.NewReader(`{"services": [
+			[["193.0.0.0/8"], ["https://rdap.db.ripe.net/"]],
+			[["193.0.0.0/16"], ["https://rdap.example/ripe-ncc/"]],
+			[["2001:4000::/23"], ["https://rdap.db.ripe.net/"]],
+			[["1-1876"], ["https://rdap.arin.net/registry/"]]
+		]}`),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	domains := []struct {
+		name string
+		want []string
+	}{
+		{"example.uk", []string{"https://rdap.nominet.uk/uk/"}},
+		{"example.co.uk", []string{"https://rdap.example/couk/", "http://rdap.example/couk/"}},
+		{"EXAMPLE.ORG.UK.", []string{"https://rdap.example/couk/", "http://rdap.example/couk/"}},
+		{"example.com", nil},
+	}
+	for _, tt := range domains {
+		if got := b.DomainServers(tt.name); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("DomainServers(%q) got = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	ips := []struct {
+		ip   string
+		want []string
+	}{
+		{"193.0.6.139", []string{"https://rdap.example/ripe-ncc/"}},
+		{"193.1.0.1", []string{"https://rdap.db.ripe.net/"}},
+		{"::ffff:193.1.0.1", []string{"https://rdap.db.ripe.net/"}},
+		{"2001:4000::1", []string{"https://rdap.db.ripe.net/"}},
+		{"8.8.8.8", nil},
+	}
+	for _, tt := range ips {
+		if got := b.IPServers(netip.MustParseAddr(tt.ip)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("IPServers(%q) got = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+
+	_, err = LoadBootstrap(strings.NewReader(`{"services": [[["com"]]]}`))
+	if err == nil {
+		t.Error("LoadBootstrap() error = nil for the invalid service")
+	}
+}
+
+// TestDefaultBootstrap tests the bundled bootstrap
+func TestDefaultBootstrap(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"whoisxmlapi.com", "https://rdap.verisign.com/com/v1/"},
+		{"example.org", "https://rdap.publicinterestregistry.org/rdap/"},
+		{"example.app", "https://pubapi.registry.google/rdap/"},
+	}
+	for _, tt := range tests {
+		got := DefaultBootstrap().DomainServers(tt.name)
+		if len(got) == 0 || got[0] != tt.want {
+			t.Errorf("DomainServers(%q) got = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	ips := []struct {
+		ip   string
+		want string
+	}{
+		{"8.8.8.8", "https://rdap.arin.net/registry/"},
+		{"193.0.6.139", "https://rdap.db.ripe.net/"},
+		{"2001:dc0::1", "https://rdap.apnic.net/"},
+	}
+	for _, tt := range ips {
+		got := DefaultBootstrap().IPServers(netip.MustParseAddr(tt.ip))
+		if len(got) == 0 || got[0] != tt.want {
+			t.Errorf("IPServers(%q) got = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+// TestDefaultBootstrapTLDs tests that common TLDs resolve through the bundled IANA files
+func TestDefaultBootstrapTLDs(t *testing.T) {
+	for name, file := range map[string][]byte{"dns.json": bundledDNS, "ipv4.json": bundledIPv4, "ipv6.json": bundledIPv6} {
+		var f struct {
+			Publication string `json:"publication"`
+		}
+		if err := json.Unmarshal(file, &f); err != nil || f.Publication == "" {
+			t.Skipf("the bundled %s is not an IANA file, refresh the files with go generate ./rdap", name)
+		}
+	}
+
+	// gTLD registries are required to run RDAP, so they are always in the IANA file
+	for _, tld := range []string{"com", "net", "org", "info", "biz", "xyz", "online", "app", "dev", "shop"} {
+		if got := DefaultBootstrap().DomainServers("example." + tld); len(got) == 0 {
+			t.Errorf("DomainServers(%q) got = [], want servers", "example."+tld)
+		}
+	}
+}
@@ -0,0 +1,341 @@
+package rdap
+
+import (
+	"net/netip"
+	"strings"
+	"unicode"
+
+	whoisapi "github.com/whois-api-llc/whois-api-go"
+)
+
+// rdapToEPP are the RDAP status values that don't map to EPP by joining the words, RFC 8056
+var rdapToEPP = map[string]string{
+	"active":     "ok",
+	"associated": "linked",
+}
+
+// StatusToEPP converts the RDAP status value, e.g. "client transfer prohibited",
+// to the EPP status code used in Whois records, e.g. "clientTransferProhibited"
+func StatusToEPP(status string) string {
+	status = strings.ToLower(strings.TrimSpace(status))
+	if epp, ok := rdapToEPP[status]; ok {
+		return epp
+	}
+
+	var b strings.Builder
+	for i, word := range strings.Fields(status) {
+		if i > 0 {
+			r := []rune(word)
+			r[0] = unicode.ToUpper(r[0])
+			word = string(r)
+		}
+		b.WriteString(word)
+	}
+	return b.String()
+}
+
+// eventDate returns the raw and the normalized date of the first event with the action
+func eventDate(events []Event, action string) (string, whoisapi.Time) {
+	for _, e := range events {
+		if !strings.EqualFold(e.EventAction, action) || e.EventDate == "" {
+			continue
+		}
+		t, err := whoisapi.ParseDate(e.EventDate)
+		if err != nil {
+			return e.EventDate, whoisapi.Time{}
+		}
+		return e.EventDate, whoisapi.Time(t.UTC())
+	}
+	return "", whoisapi.Time{}
+}
+
+// NewContact maps the jCard of the entity onto the Whois contact
+func NewContact(e *Entity) whoisapi.Contact {
+	var c whoisapi.Contact
+	card := e.VCardArray
+
+	if p, ok := card.Get("fn"); ok {
+		c.Name = p.Text()
+	}
+	if p, ok := card.Get("org"); ok {
+		c.Organization = p.Text()
+	}
+	if p, ok := card.Get("kind"); ok && p.Text() == "org" && c.Organization == "" {
+		// the formatted name of an organization is its name
+		c.Organization, c.Name = c.Name, ""
+	}
+
+	if p, ok := card.Get("adr"); ok {
+		if cc := p.Param("cc"); len(cc) > 0 {
+			c.CountryCode = strings.ToUpper(cc[0])
+		}
+		if len(p.Values) > 0 {
+			if adr, ok := p.Values[0].([]interface{}); ok && len(adr) == 7 {
+				var streets []string
+				switch street := adr[2].(type) {
+				case string:
+					streets = append(streets, street)
+				case []interface{}:
+					for _, s := range street {
+						streets = append(streets, flatten(s))
+					}
+				}
+				setStreets(&c, streets)
+				c.City = flatten(adr[3])
+				c.State = flatten(adr[4])
+				c.PostalCode = flatten(adr[5])
+				c.Country = flatten(adr[6])
+			}
+		}
+	}
+
+	if p, ok := card.Get("email"); ok {
+		c.Email = p.Text()
+	}
+
+	for _, p := range card {
+		if p.Name != "tel" {
+			continue
+		}
+		number, ext := parseTel(p.Text())
+		isFax := false
+		for _, t := range p.Param("type") {
+			isFax = isFax || strings.EqualFold(t, "fax")
+		}
+		switch {
+		case isFax && c.Fax == "":
+			c.Fax, c.FaxExt = number, ext
+		case !isFax && c.Telephone == "":
+			c.Telephone, c.TelephoneExt = number, ext
+		}
+	}
+
+	return c
+}
+
+// setStreets sets the street lines of the contact, the lines after the fourth are joined
+func setStreets(c *whoisapi.Contact, streets []string) {
+	var lines []string
+	for _, s := range streets {
+		if s = strings.TrimSpace(s); s != "" {
+			lines = append(lines, s)
+		}
+	}
+	for i, line := range lines {
+		switch i {
+		case 0:
+			c.Street1 = line
+		case 1:
+			c.Street2 = line
+		case 2:
+			c.Street3 = line
+		default:
+			c.Street4 = strings.Join(lines[3:], ", ")
+			return
+		}
+	}
+}
+
+// parseTel splits the "tel:+1.4805058800;ext=123" URI into the number and the extension
+func parseTel(s string) (number, ext string) {
+	s = strings.TrimPrefix(s, "tel:")
+	number, params, _ := strings.Cut(s, ";")
+	for _, param := range strings.Split(params, ";") {
+		if v, ok := strings.CutPrefix(param, "ext="); ok {
+			ext = v
+		}
+	}
+	return strings.TrimSpace(number), ext
+}
+
+// findEntity returns the first entity with the role, searching nested entities too
+func findEntity(entities []Entity, role string) *Entity {
+	for i := range entities {
+		if entities[i].HasRole(role) {
+			return &entities[i]
+		}
+	}
+	for i := range entities {
+		if e := findEntity(entities[i].Entities, role); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// contactByRole returns the contact of the first entity with the role
+func contactByRole(entities []Entity, role string) whoisapi.Contact {
+	if e := findEntity(entities, role); e != nil {
+		return NewContact(e)
+	}
+	return whoisapi.Contact{}
+}
+
+// NewWhoisRecord maps the RDAP domain onto the Whois record, raw is kept as RawText
+func NewWhoisRecord(d *Domain, raw []byte) *whoisapi.WhoisRecord {
+	rec := &whoisapi.WhoisRecord{}
+	rec.RawText = string(raw)
+
+	rec.DomainName = strings.ToLower(strings.TrimSuffix(d.LDHName, "."))
+	if rec.DomainName == "" {
+		rec.DomainName = strings.ToLower(strings.TrimSuffix(d.UnicodeName, "."))
+	}
+
+	rec.CreatedDate, rec.CreatedDateNormalized = eventDate(d.Events, "registration")
+	rec.UpdatedDate, rec.UpdatedDateNormalized = eventDate(d.Events, "last changed")
+	rec.ExpiresDate, rec.ExpiresDateNormalized = eventDate(d.Events, "expiration")
+
+	status := make([]string, 0, len(d.Status))
+	for _, s := range d.Status {
+		status = append(status, StatusToEPP(s))
+	}
+	rec.Status = strings.Join(status, " ")
+
+	for _, ns := range d.Nameservers {
+		host := strings.ToLower(strings.TrimSuffix(ns.LDHName, "."))
+		if host == "" {
+			continue
+		}
+		rec.NameServers.HostNames = append(rec.NameServers.HostNames, host)
+		rec.NameServers.RawText += host + "\n"
+		if ns.IPAddresses != nil {
+			rec.NameServers.Ips = append(rec.NameServers.Ips, ns.IPAddresses.V4...)
+			rec.NameServers.Ips = append(rec.NameServers.Ips, ns.IPAddresses.V6...)
+		}
+	}
+
+	if registrar := findEntity(d.Entities, "registrar"); registrar != nil {
+		c := NewContact(registrar)
+		rec.RegistrarName = c.Organization
+		if rec.RegistrarName == "" {
+			rec.RegistrarName = c.Name
+		}
+		for _, id := range registrar.PublicIDs {
+			if strings.EqualFold(id.Type, "IANA Registrar ID") {
+				rec.RegistrarIANAID = id.Identifier
+			}
+		}
+	}
+
+	rec.Registrant = contactByRole(d.Entities, "registrant")
+	rec.AdministrativeContact = contactByRole(d.Entities, "administrative")
+	rec.TechnicalContact = contactByRole(d.Entities, "technical")
+	rec.BillingContact = contactByRole(d.Entities, "billing")
+	rec.ZoneContact = contactByRole(d.Entities, "noc")
+
+	for _, c := range []whoisapi.Contact{
+		rec.Registrant, rec.AdministrativeContact, rec.TechnicalContact, contactByRole(d.Entities, "abuse"),
+	} {
+		if c.Email != "" {
+			rec.ContactEmail = c.Email
+			break
+		}
+	}
+
+	if i := strings.LastIndexByte(rec.DomainName, '.'); i >= 0 {
+		rec.DomainNameExt = rec.DomainName[i:]
+	}
+	rec.DomainAvailability = "UNAVAILABLE"
+
+	return rec
+}
+
+// registrarLink returns the URL of the registrar's RDAP record of the domain
+func registrarLink(d *Domain) string {
+	for _, l := range d.Links {
+		if strings.EqualFold(l.Rel, "related") && strings.Contains(l.Type, "rdap+json") &&
+			strings.Contains(strings.ToLower(l.Href), "/domain/") && l.Href != l.Value {
+			return l.Href
+		}
+	}
+	return ""
+}
+
+// rirs are the hosts of RIR servers by registry
+var rirs = map[string]whoisapi.RIR{
+	"arin":    whoisapi.RIRARIN,
+	"ripe":    whoisapi.RIRRIPE,
+	"apnic":   whoisapi.RIRAPNIC,
+	"lacnic":  whoisapi.RIRLACNIC,
+	"afrinic": whoisapi.RIRAFRINIC,
+}
+
+// detectRIR detects the registry by the names of its servers
+func detectRIR(servers ...string) whoisapi.RIR {
+	for _, s := range servers {
+		s = strings.ToLower(s)
+		for name, rir := range rirs {
+			if strings.Contains(s, name) {
+				return rir
+			}
+		}
+	}
+	return whoisapi.RIRUnknown
+}
+
+// NewIPWhoisRecord maps the RDAP IP network onto the IP Whois record.
+// The server is the URL the network is fetched from, it's used to detect the RIR
+func NewIPWhoisRecord(ip netip.Addr, n *IPNetwork, raw []byte, server string) *whoisapi.IPWhoisRecord {
+	rec := &whoisapi.WhoisRecord{}
+	rec.RawText = string(raw)
+	rec.DomainName = ip.String()
+	rec.CreatedDate, rec.CreatedDateNormalized = eventDate(n.Events, "registration")
+	rec.UpdatedDate, rec.UpdatedDateNormalized = eventDate(n.Events, "last changed")
+	status := make([]string, 0, len(n.Status))
+	for _, s := range n.Status {
+		status = append(status, StatusToEPP(s))
+	}
+	rec.Status = strings.Join(status, " ")
+	rec.Registrant = contactByRole(n.Entities, "registrant")
+	rec.AdministrativeContact = contactByRole(n.Entities, "administrative")
+	rec.TechnicalContact = contactByRole(n.Entities, "technical")
+
+	result := &whoisapi.IPWhoisRecord{
+		IP:          ip,
+		NetworkName: n.Name,
+		RIR:         detectRIR(n.Port43, server),
+		Registrant:  rec.Registrant,
+		Record:      rec,
+	}
+
+	from, errFrom := netip.ParseAddr(n.StartAddress)
+	to, errTo := netip.ParseAddr(n.EndAddress)
+	if errFrom == nil && errTo == nil {
+		result.Range = whoisapi.IPRange{From: from, To: to}
+	}
+
+	for _, c := range n.CIDRs {
+		addr := c.V4Prefix
+		if addr == "" {
+			addr = c.V6Prefix
+		}
+		if a, err := netip.ParseAddr(addr); err == nil {
+			if p, err := a.Prefix(c.Length); err == nil {
+				result.Prefixes = append(result.Prefixes, p)
+			}
+		}
+	}
+
+	if len(n.OriginAutnums) > 0 {
+		result.ASN = n.OriginAutnums[0]
+	}
+
+	seen := make(map[string]bool)
+	var collect func(entities []Entity)
+	collect = func(entities []Entity) {
+		for i := range entities {
+			e := &entities[i]
+			if e.HasRole("abuse") {
+				c := NewContact(e)
+				if key := strings.ToLower(c.Email); key != "" && !seen[key] {
+					seen[key] = true
+					result.AbuseContacts = append(result.AbuseContacts, c)
+				}
+			}
+			collect(e.Entities)
+		}
+	}
+	collect(n.Entities)
+
+	return result
+}
@@ -0,0 +1,336 @@
+package rdap
+
+import (
+	"net/netip"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	whoisapi "github.com/whois-api-llc/whois-api-go"
+)
+
+// eppToRDAP are the EPP status codes that don't map to RDAP by splitting the words, RFC 8056
+var eppToRDAP = map[string]string{
+	"ok":     "active",
+	"linked": "associated",
+}
+
+// StatusToRDAP converts the EPP status code used in Whois records, e.g. "clientTransferProhibited",
+// to the RDAP status value, e.g. "client transfer prohibited"
+func StatusToRDAP(status string) string {
+	status = strings.TrimSpace(status)
+	if rdap, ok := eppToRDAP[strings.ToLower(status)]; ok {
+		return rdap
+	}
+
+	var b strings.Builder
+	for i, r := range status {
+		if unicode.IsUpper(r) && i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// contactRoles are the RDAP roles of the Whois contacts, the zone contact is the "noc" one
+var contactRoles = []struct {
+	role    string
+	contact func(r *whoisapi.WhoisRecord) whoisapi.Contact
+}{
+	{"registrant", func(r *whoisapi.WhoisRecord) whoisapi.Contact { return r.Registrant }},
+	{"administrative", func(r *whoisapi.WhoisRecord) whoisapi.Contact { return r.AdministrativeContact }},
+	{"technical", func(r *whoisapi.WhoisRecord) whoisapi.Contact { return r.TechnicalContact }},
+	{"billing", func(r *whoisapi.WhoisRecord) whoisapi.Contact { return r.BillingContact }},
+	{"noc", func(r *whoisapi.WhoisRecord) whoisapi.Contact { return r.ZoneContact }},
+}
+
+// NewDomain converts the Whois record to the RDAP domain. Dates, status, name servers and the registrar
+// missing in the record are taken from RegistryData, which is converted on its own by NewRegistryDomain.
+// The result is mapped back by NewWhoisRecord
+func NewDomain(rec *whoisapi.WhoisRecord) *Domain {
+	d := newDomain(rec)
+
+	registry := &rec.RegistryData
+	if len(d.Status) == 0 {
+		d.Status = rdapStatus(registry.Status)
+	}
+	if len(d.Nameservers) == 0 {
+		d.Nameservers = nameservers(registry.NameServers)
+	}
+	if !hasEntity(d.Entities, "registrar") {
+		if e := registrarEntity(registry.RegistrarName, registry.RegistrarIANAID); e != nil {
+			d.Entities = append([]Entity{*e}, d.Entities...)
+		}
+	}
+
+	if rec.ContactEmail != "" && !hasEmail(d.Entities, rec.ContactEmail) {
+		d.Entities = append(d.Entities, Entity{
+			ObjectClassName: "entity",
+			VCardArray:      newVCard(whoisapi.Contact{Email: rec.ContactEmail}),
+			Roles:           []string{"abuse"},
+		})
+	}
+
+	return d
+}
+
+// NewRegistryDomain converts the registry's Whois record to the RDAP domain.
+// WhoisServer becomes Port43 and ReferralURL the "related" link.
+// The result is mapped back by NewRegistryData
+func NewRegistryDomain(r *whoisapi.RegistryData) *Domain {
+	rec := r.AsWhoisRecord()
+	d := newDomain(&rec)
+
+	d.Port43 = r.WhoisServer
+	if r.ReferralURL != "" {
+		link := Link{Rel: "related", Href: r.ReferralURL, Type: "text/html"}
+		if strings.Contains(strings.ToLower(r.ReferralURL), "/domain/") {
+			link.Type = mediaType
+		}
+		d.Links = append(d.Links, link)
+	}
+
+	return d
+}
+
+// NewRegistryData maps the registry's RDAP domain onto RegistryData, raw is kept as RawText
+func NewRegistryData(d *Domain, raw []byte) whoisapi.RegistryData {
+	r := NewWhoisRecord(d, raw).AsRegistryData()
+	r.WhoisServer = d.Port43
+	r.ReferralURL = registrarLink(d)
+	if r.ReferralURL == "" {
+		for _, l := range d.Links {
+			if strings.EqualFold(l.Rel, "related") {
+				r.ReferralURL = l.Href
+				break
+			}
+		}
+	}
+	return r
+}
+
+// newDomain converts the fields common to the record and RegistryData
+func newDomain(rec *whoisapi.WhoisRecord) *Domain {
+	d := &Domain{
+		Conformance:     []string{"rdap_level_0"},
+		ObjectClassName: "domain",
+		LDHName:         rec.DomainName,
+		Status:          rdapStatus(rec.Status),
+		Nameservers:     nameservers(rec.NameServers),
+	}
+	if ldh, err := whoisapi.NormalizeDomain(rec.DomainName); err == nil && ldh != strings.ToLower(rec.DomainName) {
+		d.LDHName, d.UnicodeName = ldh, rec.DomainName
+	}
+
+	for _, e := range []struct {
+		action string
+		date   func() (time.Time, whoisapi.DateSource)
+	}{
+		{"registration", rec.Created},
+		{"expiration", rec.Expires},
+		{"last changed", rec.Updated},
+	} {
+		if t, source := e.date(); source != whoisapi.DateSourceNone {
+			d.Events = append(d.Events, Event{EventAction: e.action, EventDate: t.UTC().Format(time.RFC3339)})
+		}
+	}
+	if t := time.Time(rec.Audit.UpdatedDate); !t.IsZero() {
+		d.Events = append(d.Events, Event{
+			EventAction: "last update of RDAP database",
+			EventDate:   t.UTC().Format(time.RFC3339),
+		})
+	}
+
+	if e := registrarEntity(rec.RegistrarName, rec.RegistrarIANAID); e != nil {
+		d.Entities = append(d.Entities, *e)
+	}
+
+	// the same contact in several roles is one entity
+	for _, r := range contactRoles {
+		c := r.contact(rec)
+		if c.IsEmpty() {
+			continue
+		}
+		card := newVCard(c)
+
+		merged := false
+		for i := range d.Entities {
+			e := &d.Entities[i]
+			if !e.HasRole("registrar") && reflect.DeepEqual(e.VCardArray, card) {
+				e.Roles = append(e.Roles, r.role)
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			d.Entities = append(d.Entities, Entity{
+				ObjectClassName: "entity",
+				VCardArray:      card,
+				Roles:           []string{r.role},
+			})
+		}
+	}
+
+	return d
+}
+
+// rdapStatus converts the space separated EPP status codes, URLs following the codes are skipped
+func rdapStatus(status string) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, s := range strings.Fields(status) {
+		if strings.Contains(s, "://") || strings.HasPrefix(s, "(") {
+			continue
+		}
+		if s = StatusToRDAP(s); !seen[s] {
+			seen[s] = true
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// nameservers converts the name servers. The Whois record doesn't tell which server the addresses
+// belong to, so they are kept only when there is one address per server or a single server
+func nameservers(ns whoisapi.NameServers) []Nameserver {
+	var result []Nameserver
+	for i, host := range ns.HostNames {
+		host = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(host), "."))
+		if host == "" {
+			continue
+		}
+		n := Nameserver{ObjectClassName: "nameserver", LDHName: host}
+
+		var ips []string
+		switch {
+		case len(ns.HostNames) == 1:
+			ips = ns.Ips
+		case len(ns.Ips) == len(ns.HostNames):
+			ips = ns.Ips[i : i+1]
+		}
+		for _, s := range ips {
+			ip, err := netip.ParseAddr(strings.TrimSpace(s))
+			if err != nil {
+				continue
+			}
+			if n.IPAddresses == nil {
+				n.IPAddresses = &IPAddresses{}
+			}
+			if ip.Is4() {
+				n.IPAddresses.V4 = append(n.IPAddresses.V4, ip.String())
+			} else {
+				n.IPAddresses.V6 = append(n.IPAddresses.V6, ip.String())
+			}
+		}
+
+		result = append(result, n)
+	}
+	return result
+}
+
+// registrarEntity returns the registrar entity, nil if both the name and the IANA ID are empty
+func registrarEntity(name, ianaID string) *Entity {
+	if name == "" && ianaID == "" {
+		return nil
+	}
+	e := &Entity{
+		ObjectClassName: "entity",
+		VCardArray:      newVCard(whoisapi.Contact{Organization: name}),
+		Roles:           []string{"registrar"},
+	}
+	if ianaID != "" {
+		e.Handle = ianaID
+		e.PublicIDs = []PublicID{{Type: "IANA Registrar ID", Identifier: ianaID}}
+	}
+	return e
+}
+
+// hasEntity reports whether there is an entity with the role
+func hasEntity(entities []Entity, role string) bool {
+	return findEntity(entities, role) != nil
+}
+
+// hasEmail reports whether an entity has the email address
+func hasEmail(entities []Entity, email string) bool {
+	for i := range entities {
+		if p, ok := entities[i].VCardArray.Get("email"); ok && strings.EqualFold(p.Text(), email) {
+			return true
+		}
+	}
+	return false
+}
+
+// newVCard returns the jCard of the contact. Organizations without a person's name
+// have the "org" kind and their name as the formatted name, the inverse of NewContact
+func newVCard(c whoisapi.Contact) VCard {
+	card := VCard{text("version", "4.0")}
+
+	if c.Name == "" && c.Organization != "" {
+		card = append(card, text("fn", c.Organization), text("kind", "org"))
+	} else {
+		card = append(card, text("fn", c.Name))
+		if c.Organization != "" {
+			card = append(card, text("org", c.Organization))
+		}
+	}
+
+	var streets []interface{}
+	for _, s := range []string{c.Street1, c.Street2, c.Street3, c.Street4} {
+		if s != "" {
+			streets = append(streets, s)
+		}
+	}
+	if len(streets) > 0 || c.City != "" || c.State != "" || c.PostalCode != "" || c.Country != "" || c.CountryCode != "" {
+		var street interface{} = ""
+		switch len(streets) {
+		case 0:
+		case 1:
+			street = streets[0]
+		default:
+			street = streets
+		}
+		adr := VCardProperty{
+			Name:   "adr",
+			Params: map[string]interface{}{},
+			Type:   "text",
+			Values: []interface{}{[]interface{}{"", "", street, c.City, c.State, c.PostalCode, c.Country}},
+		}
+		if c.CountryCode != "" {
+			adr.Params["cc"] = strings.ToUpper(c.CountryCode)
+		}
+		card = append(card, adr)
+	}
+
+	if c.Email != "" {
+		card = append(card, text("email", c.Email))
+	}
+	if c.Telephone != "" {
+		card = append(card, tel("voice", c.Telephone, c.TelephoneExt))
+	}
+	if c.Fax != "" {
+		card = append(card, tel("fax", c.Fax, c.FaxExt))
+	}
+
+	return card
+}
+
+// text returns the jCard text property
+func text(name, value string) VCardProperty {
+	return VCardProperty{Name: name, Params: map[string]interface{}{}, Type: "text", Values: []interface{}{value}}
+}
+
+// tel returns the jCard tel property of the type with the number as a tel URI
+func tel(typ, number, ext string) VCardProperty {
+	uri := "tel:" + strings.TrimPrefix(strings.TrimSpace(number), "tel:")
+	if ext != "" {
+		uri += ";ext=" + ext
+	}
+	return VCardProperty{
+		Name:   "tel",
+		Params: map[string]interface{}{"type": []interface{}{typ}},
+		Type:   "uri",
+		Values: []interface{}{uri},
+	}
+}
@@ -0,0 +1,199 @@
+package rdap
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	whoisapi "github.com/whois-api-llc/whois-api-go"
+)
+
+// newExportRecord returns the Whois record with all the exported fields set
+func newExportRecord() *whoisapi.WhoisRecord {
+	rec := &whoisapi.WhoisRecord{}
+	rec.DomainName = "whoisxmlapi.com"
+	rec.CreatedDateNormalized = whoisapi.Time(time.Date(2013, 2, 28, 18, 42, 12, 0, time.UTC))
+	rec.ExpiresDateNormalized = whoisapi.Time(time.Date(2027, 2, 28, 18, 42, 12, 0, time.UTC))
+	rec.UpdatedDate = "2023-01-29T17:44:23Z"
+	rec.Status = "clientTransferProhibited https://icann.org/epp#clientTransferProhibited ok"
+	rec.NameServers.HostNames = []string{"NS1.WHOISXMLAPI.COM", "ns2.whoisxmlapi.com."}
+	rec.NameServers.Ips = []string{"192.0.2.1", "2001:db8::2"}
+	rec.RegistrarName = "GoDaddy.com, LLC"
+	rec.RegistrarIANAID = "146"
+	rec.Registrant = whoisapi.Contact{
+		Name:         "Registration Private",
+		Organization: "Whois API, Inc.",
+		Street1:      "340 S Lemon Ave",
+		Street2:      "#1717",
+		City:         "Walnut",
+		State:        "California",
+		PostalCode:   "91789",
+		Country:      "UNITED STATES",
+		CountryCode:  "US",
+		Email:        "support@whoisxmlapi.com",
+		Telephone:    "+1.8003102051",
+		TelephoneExt: "12",
+		Fax:          "+1.8003102052",
+		RawText:      "Registrant Name: Registration Private",
+	}
+	tech := whoisapi.Contact{Organization: "Whois API", Email: "tech@whoisxmlapi.com", Country: "UNITED STATES"}
+	rec.AdministrativeContact = tech
+	rec.TechnicalContact = tech
+	rec.BillingContact = whoisapi.Contact{Name: "Billing Department", Street1: "PO Box 1"}
+	rec.ZoneContact = whoisapi.Contact{Name: "Zone Admin", CountryCode: "us"}
+	rec.ContactEmail = "support@whoisxmlapi.com"
+
+	rec.RegistryData.DomainName = "whoisxmlapi.com"
+	rec.RegistryData.CreatedDate = "2013-02-28T18:42:12Z"
+	rec.RegistryData.Status = "clientTransferProhibited"
+	rec.RegistryData.RegistrarName = "GoDaddy.com, LLC"
+	rec.RegistryData.RegistrarIANAID = "146"
+	rec.RegistryData.WhoisServer = "whois.verisign-grs.com"
+	rec.RegistryData.ReferralURL = "https://rdap.godaddy.com/v1/domain/WHOISXMLAPI.COM"
+	return rec
+}
+
+// TestExportRoundTrip tests that the main fields survive the conversion to RDAP JSON and back
+func TestExportRoundTrip(t *testing.T) {
+	rec := newExportRecord()
+
+	b, err := json.Marshal(NewDomain(rec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var d Domain
+	if err := json.Unmarshal(b, &d); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err = json.Marshal(NewRegistryDomain(&rec.RegistryData))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var registry Domain
+	if err := json.Unmarshal(b, &registry); err != nil {
+		t.Fatal(err)
+	}
+
+	got := NewWhoisRecord(&d, nil)
+	got.RegistryData = NewRegistryData(&registry, nil)
+
+	checks := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"DomainName", got.DomainName, "whoisxmlapi.com"},
+		{"CreatedDateNormalized", got.CreatedDateNormalized, rec.CreatedDateNormalized},
+		{"ExpiresDateNormalized", got.ExpiresDateNormalized, rec.ExpiresDateNormalized},
+		{"UpdatedDateNormalized", got.UpdatedDateNormalized, whoisapi.Time(time.Date(2023, 1, 29, 17, 44, 23, 0, time.UTC))},
+		{"Status", got.Status, "clientTransferProhibited ok"},
+		{"NameServers.HostNames", got.NameServers.HostNames, []string{"ns1.whoisxmlapi.com", "ns2.whoisxmlapi.com"}},
+		{"NameServers.Ips", got.NameServers.Ips, []string{"192.0.2.1", "2001:db8::2"}},
+		{"RegistrarName", got.RegistrarName, rec.RegistrarName},
+		{"RegistrarIANAID", got.RegistrarIANAID, rec.RegistrarIANAID},
+		{"ContactEmail", got.ContactEmail, rec.ContactEmail},
+		{"RegistryData.CreatedDateNormalized", got.RegistryData.CreatedDateNormalized, rec.CreatedDateNormalized},
+		{"RegistryData.Status", got.RegistryData.Status, "clientTransferProhibited"},
+		{"RegistryData.RegistrarIANAID", got.RegistryData.RegistrarIANAID, "146"},
+		{"RegistryData.WhoisServer", got.RegistryData.WhoisServer, rec.RegistryData.WhoisServer},
+		{"RegistryData.ReferralURL", got.RegistryData.ReferralURL, rec.RegistryData.ReferralURL},
+	}
+	for _, c := range checks {
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Errorf("%s got = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+
+	contacts := []struct {
+		name      string
+		got, want whoisapi.Contact
+	}{
+		{"Registrant", got.Registrant, rec.Registrant},
+		{"AdministrativeContact", got.AdministrativeContact, rec.AdministrativeContact},
+		{"TechnicalContact", got.TechnicalContact, rec.TechnicalContact},
+		{"BillingContact", got.BillingContact, rec.BillingContact},
+		{"ZoneContact", got.ZoneContact, rec.ZoneContact},
+	}
+	for _, c := range contacts {
+		c.want.RawText = ""
+		c.want.CountryCode = strings.ToUpper(c.want.CountryCode)
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Errorf("%s got = %+v, want %+v", c.name, c.got, c.want)
+		}
+	}
+}
+
+// TestNewDomain tests the RDAP domain made from the Whois record
+func TestNewDomain(t *testing.T) {
+	rec := newExportRecord()
+	rec.Status = ""
+	rec.NameServers.Ips = []string{"192.0.2.1"}
+	rec.RegistrarName, rec.RegistrarIANAID = "", ""
+	rec.ContactEmail = "abuse@godaddy.com"
+	rec.Audit.UpdatedDate = whoisapi.Time(time.Date(2026, 10, 18, 12, 0, 0, 0, time.FixedZone("EEST", 3*3600)))
+
+	d := NewDomain(rec)
+
+	if d.ObjectClassName != "domain" || d.LDHName != "whoisxmlapi.com" || d.UnicodeName != "" {
+		t.Errorf("domain got = %q, %q, %q", d.ObjectClassName, d.LDHName, d.UnicodeName)
+	}
+	if !reflect.DeepEqual(d.Status, []string{"client transfer prohibited"}) {
+		t.Errorf("Status got = %v, want the registry's one", d.Status)
+	}
+	for _, ns := range d.Nameservers {
+		if ns.IPAddresses != nil {
+			t.Errorf("IPAddresses of %s got = %+v, want nil", ns.LDHName, ns.IPAddresses)
+		}
+	}
+
+	wantEvents := []Event{
+		{EventAction: "registration", EventDate: "2013-02-28T18:42:12Z"},
+		{EventAction: "expiration", EventDate: "2027-02-28T18:42:12Z"},
+		{EventAction: "last changed", EventDate: "2023-01-29T17:44:23Z"},
+		{EventAction: "last update of RDAP database", EventDate: "2026-10-18T09:00:00Z"},
+	}
+	if !reflect.DeepEqual(d.Events, wantEvents) {
+		t.Errorf("Events got = %+v, want %+v", d.Events, wantEvents)
+	}
+
+	var roles [][]string
+	for _, e := range d.Entities {
+		roles = append(roles, e.Roles)
+	}
+	wantRoles := [][]string{{"registrar"}, {"registrant"}, {"administrative", "technical"}, {"billing"}, {"noc"}, {"abuse"}}
+	if !reflect.DeepEqual(roles, wantRoles) {
+		t.Errorf("entity roles got = %v, want %v", roles, wantRoles)
+	}
+	if d.Entities[0].PublicIDs[0].Identifier != "146" {
+		t.Errorf("registrar got = %+v, want the registry's one", d.Entities[0])
+	}
+
+	rec.DomainName = "bücher.de"
+	d = NewDomain(rec)
+	if d.LDHName != "xn--bcher-kva.de" || d.UnicodeName != "bücher.de" {
+		t.Errorf("names got = %q, %q", d.LDHName, d.UnicodeName)
+	}
+}
+
+// TestStatusToRDAP tests the StatusToRDAP function
+func TestStatusToRDAP(t *testing.T) {
+	tests := []string{
+		"ok",
+		"linked",
+		"clientTransferProhibited",
+		"serverHold",
+		"autoRenewPeriod",
+		"pendingDelete",
+		"inactive",
+	}
+	for _, status := range tests {
+		if got := StatusToEPP(StatusToRDAP(status)); got != status {
+			t.Errorf("StatusToEPP(StatusToRDAP(%q)) got = %q", status, got)
+		}
+	}
+	if got := StatusToRDAP("OK"); got != "active" {
+		t.Errorf("StatusToRDAP(OK) got = %q, want active", got)
+	}
+}
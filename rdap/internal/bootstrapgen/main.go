@@ -0,0 +1,63 @@
+// Command bootstrapgen downloads the IANA RDAP bootstrap files bundled with the rdap package.
+// The files are written unmodified, run it with go generate in the rdap directory
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"time"
+)
+
+// files are the IANA bootstrap files bundled with the package
+var files = []string{
+	"https://data.iana.org/rdap/dns.json",
+	"https://data.iana.org/rdap/ipv4.json",
+	"https://data.iana.org/rdap/ipv6.json",
+}
+
+func main() {
+	client := &http.Client{Timeout: time.Minute}
+	for _, u := range files {
+		if err := download(client, u); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// download saves the file to the current directory after checking it's a bootstrap file
+func download(client *http.Client, u string) error {
+	resp, err := client.Get(u)
+	if err != nil {
+		return fmt.Errorf("cannot fetch %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("cannot fetch %s: %s", u, resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("cannot fetch %s: %w", u, err)
+	}
+
+	var f struct {
+		Publication string          `json:"publication"`
+		Services    [][][]string    `json:"services"`
+		Version     json.RawMessage `json:"version"`
+	}
+	if err := json.Unmarshal(body, &f); err != nil || len(f.Services) == 0 {
+		return fmt.Errorf("cannot parse %s: not a bootstrap file", u)
+	}
+
+	name := path.Base(u)
+	if err := os.WriteFile(name, body, 0o644); err != nil {
+		return fmt.Errorf("cannot write %s: %w", name, err)
+	}
+	log.Printf("%s: %d services, published %s", name, len(f.Services), f.Publication)
+	return nil
+}
@@ -0,0 +1,321 @@
+// Package rdap implements whoisapi.WhoisService using the Registration Data Access Protocol, RFC 7480-9083.
+// Servers are found with the IANA bootstrap, registrar RDAP records are followed from the registry ones
+package rdap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+
+	whoisapi "github.com/whois-api-llc/whois-api-go"
+)
+
+const (
+	// mediaType is the RDAP media type, RFC 7480
+	mediaType = "application/rdap+json"
+
+	// maxResponseSize is the limit of a single server response in bytes
+	maxResponseSize = 4 << 20
+)
+
+// ErrNoServer is returned when the bootstrap has no RDAP server for the name
+var ErrNoServer = errors.New("no RDAP server for the name")
+
+// Params is used to create Client. All fields are optional
+type Params struct {
+	// HTTPClient is used to query the servers. http.DefaultClient is used if nil
+	HTTPClient *http.Client
+
+	// Bootstrap finds the servers for names. DefaultBootstrap is used if nil
+	Bootstrap *Bootstrap
+
+	// IPServer is the base URL queried for IP addresses not covered by Bootstrap.
+	// DefaultIPServer is used if empty
+	IPServer string
+
+	// UserAgent is the User-Agent header of the requests
+	UserAgent string
+}
+
+// Client is the WhoisService querying RDAP servers
+type Client struct {
+	params Params
+}
+
+var _ whoisapi.WhoisService = &Client{}
+var _ whoisapi.PrefixService = &Client{}
+
+// New creates Client with specified parameters
+func New(params Params) *Client {
+	if params.HTTPClient == nil {
+		params.HTTPClient = http.DefaultClient
+	}
+	if params.Bootstrap == nil {
+		params.Bootstrap = DefaultBootstrap()
+	}
+	if params.IPServer == "" {
+		params.IPServer = DefaultIPServer
+	}
+	if !strings.HasSuffix(params.IPServer, "/") {
+		params.IPServer += "/"
+	}
+
+	return &Client{params: params}
+}
+
+// Data returns the Whois record mapped from the registrar's RDAP record, or the registry's one
+// if there is no registrar link or OptionThinWhois is set. The registry's record is in RegistryData.
+// Names that are not registered are returned with DomainAvailability "AVAILABLE"
+func (c *Client) Data(
+	ctx context.Context,
+	name string,
+	opts ...whoisapi.Option,
+) (*whoisapi.WhoisRecord, *whoisapi.Response, error) {
+
+	r, err := whoisapi.WhoisRequestFromOptions(name, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	normalized, err := whoisapi.NormalizeDomain(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if ip, err := netip.ParseAddr(normalized); err == nil {
+		ipRec, resp, err := c.ipData(ctx, name, ip, ip.String())
+		if err != nil {
+			return nil, resp, err
+		}
+		return clearRawTexts(ipRec.Record, r.IgnoreRawTexts), resp, nil
+	}
+
+	servers := c.params.Bootstrap.DomainServers(normalized)
+	if len(servers) == 0 {
+		return nil, nil, fmt.Errorf("cannot look up %s: %w", normalized, ErrNoServer)
+	}
+
+	var registry Domain
+	resp, server, err := c.fetch(ctx, servers, "domain/"+normalized, &registry)
+	if resp != nil {
+		resp.Name, resp.NormalizedName = name, normalized
+	}
+	var rdapErr *Error
+	if errors.As(err, &rdapErr) && rdapErr.ErrorCode == http.StatusNotFound {
+		rec := &whoisapi.WhoisRecord{}
+		rec.DomainName = normalized
+		rec.RegistryData.DomainName = normalized
+		rec.DomainAvailability = "AVAILABLE"
+		if i := strings.LastIndexByte(normalized, '.'); i >= 0 {
+			rec.DomainNameExt = normalized[i:]
+		}
+		return rec, resp, nil
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	registryBody := resp.Body
+	rec := NewWhoisRecord(&registry, registryBody)
+
+	link := registrarLink(&registry)
+	if link != "" && !r.ThinWhois {
+		var registrar Domain
+		registrarResp, _, err := c.fetch(ctx, []string{resolve(server, link)}, "", &registrar)
+		switch {
+		case err == nil:
+			registrarResp.Name, registrarResp.NormalizedName = name, normalized
+			resp = registrarResp
+			rec = NewWhoisRecord(&registrar, resp.Body)
+		case ctx.Err() != nil:
+			return nil, resp, err
+		}
+		// the registrar's server is down or doesn't know the domain, the registry's record is returned
+	}
+
+	rec.RegistryData = NewRegistryData(&registry, registryBody)
+
+	return clearRawTexts(rec, r.IgnoreRawTexts), resp, nil
+}
+
+// RawData returns the RDAP JSON of the last server in Response.Body
+func (c *Client) RawData(ctx context.Context, name string, opts ...whoisapi.Option) (*whoisapi.Response, error) {
+	_, resp, err := c.Data(ctx, name, opts...)
+	return resp, err
+}
+
+// IPData returns the Whois record of the IP address mapped from the RDAP IP network
+func (c *Client) IPData(
+	ctx context.Context,
+	ip netip.Addr,
+	_ ...whoisapi.Option,
+) (*whoisapi.IPWhoisRecord, *whoisapi.Response, error) {
+
+	if !ip.IsValid() {
+		return nil, nil, &whoisapi.ArgError{Name: "ip", Message: "is not valid"}
+	}
+	ip = ip.Unmap().WithZone("")
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return nil, nil, &whoisapi.ArgError{Name: "ip", Message: ip.String() + " is not a public address"}
+	}
+
+	return c.ipData(ctx, ip.String(), ip, ip.String())
+}
+
+// PrefixData returns the Whois record of the smallest RDAP IP network containing the whole CIDR prefix.
+// whoisapi.ErrPrefixNotCovered is returned if the network of the response doesn't contain the whole prefix
+func (c *Client) PrefixData(
+	ctx context.Context,
+	prefix netip.Prefix,
+	_ ...whoisapi.Option,
+) (*whoisapi.IPWhoisRecord, *whoisapi.Response, error) {
+
+	prefix, err := whoisapi.NormalizePrefix(prefix)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rec, resp, err := c.ipData(ctx, prefix.String(), prefix.Addr(), prefix.String())
+	if err != nil {
+		return nil, resp, err
+	}
+	if !rec.Range.ContainsPrefix(prefix) {
+		return nil, resp, fmt.Errorf("cannot look up %s: %w", prefix, whoisapi.ErrPrefixNotCovered)
+	}
+	rec.Prefix = prefix
+	rec.Record.DomainName = prefix.String()
+	return rec, resp, nil
+}
+
+// ipData fetches the IP network of the query, the address or the CIDR prefix starting with the address
+func (c *Client) ipData(
+	ctx context.Context,
+	name string,
+	ip netip.Addr,
+	query string,
+) (*whoisapi.IPWhoisRecord, *whoisapi.Response, error) {
+
+	servers := c.params.Bootstrap.IPServers(ip)
+	if len(servers) == 0 {
+		servers = []string{c.params.IPServer}
+	}
+
+	var network IPNetwork
+	resp, server, err := c.fetch(ctx, servers, "ip/"+query, &network)
+	if resp != nil {
+		resp.Name, resp.NormalizedName = name, query
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	// the RIR that answered after redirects
+	if resp.Request != nil {
+		server = resp.Request.URL.String()
+	}
+
+	return NewIPWhoisRecord(ip, &network, resp.Body, server), resp, nil
+}
+
+// fetch queries the servers in order until one responds and decodes the object into v.
+// Transport errors, 5xx and 429 responses move on to the next server, other responses are definitive.
+// The path is appended to the base URL of the server
+func (c *Client) fetch(ctx context.Context, servers []string, path string, v interface{}) (*whoisapi.Response, string, error) {
+	var (
+		lastResp   *whoisapi.Response
+		lastServer string
+		lastErr    error
+	)
+	for _, server := range servers {
+		resp, err := c.get(ctx, server+path)
+		if err != nil {
+			lastErr = err
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+
+		if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+			lastResp, lastServer, lastErr = resp, server, newError(resp)
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			return resp, server, newError(resp)
+		}
+		if err := json.Unmarshal(resp.Body, v); err != nil {
+			return resp, server, fmt.Errorf("cannot parse response: %w", err)
+		}
+		return resp, server, nil
+	}
+
+	return lastResp, lastServer, lastErr
+}
+
+// get sends the RDAP request
+func (c *Client) get(ctx context.Context, u string) (*whoisapi.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", mediaType+", application/json")
+	if c.params.UserAgent != "" {
+		req.Header.Set("User-Agent", c.params.UserAgent)
+	}
+
+	resp, err := c.params.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot query %s: %w", req.URL.Host, err)
+	}
+	defer resp.Body.Close()
+
+	var b bytes.Buffer
+	if _, err := io.Copy(&b, io.LimitReader(resp.Body, maxResponseSize)); err != nil {
+		return nil, fmt.Errorf("cannot read response of %s: %w", req.URL.Host, err)
+	}
+
+	return &whoisapi.Response{Response: resp, Body: b.Bytes()}, nil
+}
+
+// newError returns the RDAP error of the response, or one made from the status code
+func newError(resp *whoisapi.Response) error {
+	rdapErr := &Error{}
+	if err := json.Unmarshal(resp.Body, rdapErr); err != nil || rdapErr.ErrorCode == 0 {
+		rdapErr = &Error{ErrorCode: resp.StatusCode}
+	}
+	if rdapErr.Title == "" {
+		rdapErr.Title = http.StatusText(resp.StatusCode)
+	}
+	return rdapErr
+}
+
+// resolve returns the link resolved against the base URL
+func resolve(base, link string) string {
+	b, err := url.Parse(base)
+	if err != nil {
+		return link
+	}
+	l, err := b.Parse(link)
+	if err != nil {
+		return link
+	}
+	return l.String()
+}
+
+// clearRawTexts removes raw texts from the record if requested
+func clearRawTexts(rec *whoisapi.WhoisRecord, clear bool) *whoisapi.WhoisRecord {
+	if clear {
+		rec.RawText = ""
+		rec.NameServers.RawText = ""
+		rec.RegistryData.RawText = ""
+		rec.RegistryData.NameServers.RawText = ""
+	}
+	return rec
+}
@@ -0,0 +1,425 @@
+package rdap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	whoisapi "github.com/whois-api-llc/whois-api-go"
+)
+
+const registryDomain = `{
+  "objectClassName": "domain",
+  "handle": "1781014932_DOMAIN_COM-VRSN",
+  "ldhName": "WHOISXMLAPI.COM",
+  "links": [
+    {"value": "{{registry}}/com/v1/domain/WHOISXMLAPI.COM", "rel": "self", "href": "{{registry}}/com/v1/domain/WHOISXMLAPI.COM", "type": "application/rdap+json"},
+    {"value": "{{registry}}/com/v1/domain/WHOISXMLAPI.COM", "rel": "related", "href": "{{registrar}}/rdap/domain/WHOISXMLAPI.COM", "type": "application/rdap+json"}
+  ],
+  "status": ["client transfer prohibited", "client update prohibited"],
+  "entities": [{
+    "objectClassName": "entity",
+    "handle": "146",
+    "roles": ["registrar"],
+    "publicIds": [{"type": "IANA Registrar ID", "identifier": "146"}],
+    "vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "GoDaddy.com, LLC"]]],
+    "entities": [{
+      "objectClassName": "entity",
+      "roles": ["abuse"],
+      "vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", ""],
+        ["tel", {"type": "voice"}, "uri", "tel:480-624-2505"], ["email", {}, "text", "abuse@godaddy.com"]]]
+    }]
+  }],
+  "events": [
+    {"eventAction": "registration", "eventDate": "2013-02-28T18:42:12Z"},
+    {"eventAction": "expiration", "eventDate": "2027-02-28T18:42:12Z"},
+    {"eventAction": "last changed", "eventDate": "2023-01-29T17:44:23Z"},
+    {"eventAction": "last update of RDAP database", "eventDate": "2026-10-18T10:00:00Z"}
+  ],
+  "nameservers": [
+    {"objectClassName": "nameserver", "ldhName": "NS1.WHOISXMLAPI.COM"},
+    {"objectClassName": "nameserver", "ldhName": "NS2.WHOISXMLAPI.COM", "ipAddresses": {"v4": ["192.0.2.2"], "v6": ["2001:db8::2"]}}
+  ],
+  "port43": "whois.verisign-grs.com"
+}`
+
+const registrarDomain = `{
+  "objectClassName": "domain",
+  "ldhName": "whoisxmlapi.com",
+  "status": ["active"],
+  "entities": [
+    {
+      "objectClassName": "entity",
+      "roles": ["registrant"],
+      "vcardArray": ["vcard", [
+        ["version", {}, "text", "4.0"],
+        ["fn", {}, "text", "Registration Private"],
+        ["org", {}, "text", "Whois API, Inc."],
+        ["adr", {"cc": "us"}, "text", ["", "", ["340 S Lemon Ave", "#1717"], "Walnut", "California", "91789", "United States"]],
+        ["tel", {"type": ["voice"]}, "uri", "tel:+1.8003102051;ext=12"],
+        ["tel", {"type": ["fax"]}, "uri", "tel:+1.8003102052"],
+        ["email", {}, "text", "support@whoisxmlapi.com"]
+      ]]
+    },
+    {
+      "objectClassName": "entity",
+      "roles": ["technical", "administrative"],
+      "vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "Whois API"], ["kind", {}, "text", "org"]]]
+    }
+  ],
+  "events": [
+    {"eventAction": "registration", "eventDate": "2013-02-28T12:42:12-06:00"},
+    {"eventAction": "expiration", "eventDate": "2027-02-28T12:42:12-06:00"}
+  ]
+}`
+
+const ipNetwork = `{
+  "objectClassName": "ip network",
+  "handle": "193.0.0.0 - 193.0.7.255",
+  "startAddress": "193.0.0.0",
+  "endAddress": "193.0.7.255",
+  "ipVersion": "v4",
+  "name": "RIPE-NCC",
+  "country": "NL",
+  "cidr0_cidrs": [{"v4prefix": "193.0.0.0", "length": 21}],
+  "arin_originas0_originautnums": [3333],
+  "entities": [
+    {
+      "objectClassName": "entity",
+      "roles": ["registrant"],
+      "vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "RIPE Network Coordination Centre"], ["kind", {}, "text", "org"]]],
+      "entities": [{
+        "objectClassName": "entity",
+        "roles": ["abuse"],
+        "vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "Abuse contact"], ["email", {}, "text", "abuse@ripe.net"]]]
+      }]
+    },
+    {
+      "objectClassName": "entity",
+      "roles": ["abuse"],
+      "vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["email", {}, "text", "ABUSE@ripe.net"]]]
+    }
+  ],
+  "port43": "whois.ripe.net"
+}`
+
+// stub is the RDAP server stand-in
+type stub struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+// newStub starts the server responding with the objects by path, other paths are not found
+func newStub(t *testing.T, objects map[string]string) *stub {
+	s := &stub{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, req)
+		s.mu.Unlock()
+
+		w.Header().Set("Content-Type", mediaType)
+		body, ok := objects[req.URL.Path]
+		switch {
+		case !ok:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errorCode": 404, "title": "Not Found", "description": ["The domain is not registered"]}`))
+		case body == "":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			_, _ = w.Write([]byte(body))
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// count returns the number of requests received
+func (s *stub) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.requests)
+}
+
+// newTestClient starts the registry and the registrar stand-ins and returns the client using them
+func newTestClient(t *testing.T, registrarUp bool) (*Client, *stub, *stub) {
+	registrarBody := registrarDomain
+	if !registrarUp {
+		registrarBody = ""
+	}
+	registrar := newStub(t, map[string]string{"/rdap/domain/WHOISXMLAPI.COM": registrarBody})
+
+	objects := map[string]string{}
+	registry := newStub(t, objects)
+	objects["/com/v1/domain/whoisxmlapi.com"] = strings.NewReplacer(
+		"{{registry}}", registry.URL,
+		"{{registrar}}", registrar.URL,
+	).Replace(registryDomain)
+
+	bootstrap, err := LoadBootstrap(strings.NewReader(
+		`{"services": [[["com"], ["` + registry.URL + `/com/v1"]]], "version": "1.0"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return New(Params{Bootstrap: bootstrap, UserAgent: "whoisapi-test"}), registry, registrar
+}
+
+// TestData tests the lookup of the domain via the registry and the registrar
+func TestData(t *testing.T) {
+	client, registry, registrar := newTestClient(t, true)
+
+	rec, resp, err := client.Data(context.Background(), "WhoisXMLAPI.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := registry.requests[0]
+	if req.Header.Get("Accept") != "application/rdap+json, application/json" || req.UserAgent() != "whoisapi-test" {
+		t.Errorf("request headers got = %v", req.Header)
+	}
+	if registrar.count() != 1 {
+		t.Errorf("registrar requests got = %d, want 1", registrar.count())
+	}
+	if resp.StatusCode != http.StatusOK || resp.NormalizedName != "whoisxmlapi.com" || string(resp.Body) != registrarDomain {
+		t.Errorf("Response got = %d, %q", resp.StatusCode, resp.NormalizedName)
+	}
+
+	checks := []struct {
+		name      string
+		got, want string
+	}{
+		{"DomainName", rec.DomainName, "whoisxmlapi.com"},
+		{"DomainNameExt", rec.DomainNameExt, ".com"},
+		{"DomainAvailability", rec.DomainAvailability, "UNAVAILABLE"},
+		{"Status", rec.Status, "ok"},
+		{"ContactEmail", rec.ContactEmail, "support@whoisxmlapi.com"},
+		{"RawText", rec.RawText, registrarDomain},
+		{"Registrant.Name", rec.Registrant.Name, "Registration Private"},
+		{"Registrant.Organization", rec.Registrant.Organization, "Whois API, Inc."},
+		{"Registrant.Street1", rec.Registrant.Street1, "340 S Lemon Ave"},
+		{"Registrant.Street2", rec.Registrant.Street2, "#1717"},
+		{"Registrant.City", rec.Registrant.City, "Walnut"},
+		{"Registrant.State", rec.Registrant.State, "California"},
+		{"Registrant.PostalCode", rec.Registrant.PostalCode, "91789"},
+		{"Registrant.Country", rec.Registrant.Country, "United States"},
+		{"Registrant.CountryCode", rec.Registrant.CountryCode, "US"},
+		{"Registrant.Telephone", rec.Registrant.Telephone, "+1.8003102051"},
+		{"Registrant.TelephoneExt", rec.Registrant.TelephoneExt, "12"},
+		{"Registrant.Fax", rec.Registrant.Fax, "+1.8003102052"},
+		{"TechnicalContact.Organization", rec.TechnicalContact.Organization, "Whois API"},
+		{"AdministrativeContact.Organization", rec.AdministrativeContact.Organization, "Whois API"},
+		{"RegistryData.DomainName", rec.RegistryData.DomainName, "whoisxmlapi.com"},
+		{"RegistryData.RegistrarName", rec.RegistryData.RegistrarName, "GoDaddy.com, LLC"},
+		{"RegistryData.RegistrarIANAID", rec.RegistryData.RegistrarIANAID, "146"},
+		{"RegistryData.Status", rec.RegistryData.Status, "clientTransferProhibited clientUpdateProhibited"},
+		{"RegistryData.NameServers", strings.Join(rec.RegistryData.NameServers.HostNames, ","), "ns1.whoisxmlapi.com,ns2.whoisxmlapi.com"},
+		{"RegistryData.NameServers.Ips", strings.Join(rec.RegistryData.NameServers.Ips, ","), "192.0.2.2,2001:db8::2"},
+		{"RegistryData.WhoisServer", rec.RegistryData.WhoisServer, "whois.verisign-grs.com"},
+		{"RegistryData.ReferralURL", rec.RegistryData.ReferralURL, registrar.URL + "/rdap/domain/WHOISXMLAPI.COM"},
+		{"RegistryData.UpdatedDate", rec.RegistryData.UpdatedDate, "2023-01-29T17:44:23Z"},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s got = %q, want %q", c.name, c.got, c.want)
+		}
+	}
+
+	want := time.Date(2027, 2, 28, 18, 42, 12, 0, time.UTC)
+	if got := time.Time(rec.ExpiresDateNormalized); !got.Equal(want) || got.Location() != time.UTC {
+		t.Errorf("ExpiresDateNormalized got = %v, want %v", got, want)
+	}
+}
+
+// TestDataFallbacks tests thin lookups, unregistered names and unavailable servers
+func TestDataFallbacks(t *testing.T) {
+	t.Run("thin Whois", func(t *testing.T) {
+		client, _, registrar := newTestClient(t, true)
+		rec, resp, err := client.Data(context.Background(), "whoisxmlapi.com",
+			whoisapi.OptionThinWhois(1), whoisapi.OptionIgnoreRawTexts(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if registrar.count() != 0 || rec.RegistrarName != "GoDaddy.com, LLC" || rec.RawText != "" || rec.RegistryData.RawText != "" {
+			t.Errorf("thin Whois got = %d requests, %+v", registrar.count(), rec)
+		}
+		if !strings.Contains(string(resp.Body), "1781014932_DOMAIN_COM-VRSN") {
+			t.Errorf("Body got = %s", resp.Body)
+		}
+	})
+
+	t.Run("registrar is down", func(t *testing.T) {
+		client, _, _ := newTestClient(t, false)
+		rec, resp, err := client.Data(context.Background(), "whoisxmlapi.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rec.RegistrarName != "GoDaddy.com, LLC" || rec.ContactEmail != "abuse@godaddy.com" || resp.StatusCode != http.StatusOK {
+			t.Errorf("registry record got = %+v", rec)
+		}
+	})
+
+	t.Run("not registered", func(t *testing.T) {
+		client, _, _ := newTestClient(t, true)
+		rec, resp, err := client.Data(context.Background(), "available-domain.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rec.DomainAvailability != "AVAILABLE" || rec.DomainName != "available-domain.com" || resp.StatusCode != http.StatusNotFound {
+			t.Errorf("Data() got = %+v", rec)
+		}
+	})
+
+	t.Run("no server", func(t *testing.T) {
+		client, _, _ := newTestClient(t, true)
+		_, _, err := client.Data(context.Background(), "whoisxmlapi.example")
+		if !errors.Is(err, ErrNoServer) {
+			t.Errorf("Data() error = %v, want %v", err, ErrNoServer)
+		}
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		bootstrap, _ := LoadBootstrap(strings.NewReader(`{"services": [[["com"], ["` + server.URL + `"]]]}`))
+		_, _, err := New(Params{Bootstrap: bootstrap}).Data(context.Background(), "whoisxmlapi.com")
+		var rdapErr *Error
+		if !errors.As(err, &rdapErr) || err.Error() != "RDAP error: [429] Too Many Requests" {
+			t.Errorf("Data() error = %v", err)
+		}
+	})
+
+	t.Run("next server after server error", func(t *testing.T) {
+		client, registry, _ := newTestClient(t, true)
+		failing := newStub(t, map[string]string{"/com/v1/domain/whoisxmlapi.com": ""})
+
+		bootstrap, _ := LoadBootstrap(strings.NewReader(
+			`{"services": [[["com"], ["` + failing.URL + `/com/v1", "` + registry.URL + `/com/v1"]]]}`))
+		client.params.Bootstrap = bootstrap
+
+		rec, _, err := client.Data(context.Background(), "whoisxmlapi.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rec.RegistryData.RegistrarName != "GoDaddy.com, LLC" || failing.count() != 1 {
+			t.Errorf("Data() got = %d failing requests, %+v", failing.count(), rec)
+		}
+	})
+
+	t.Run("not found is definitive", func(t *testing.T) {
+		client, registry, _ := newTestClient(t, true)
+		first := newStub(t, map[string]string{})
+
+		bootstrap, _ := LoadBootstrap(strings.NewReader(
+			`{"services": [[["com"], ["` + first.URL + `/com/v1", "` + registry.URL + `/com/v1"]]]}`))
+		client.params.Bootstrap = bootstrap
+
+		rec, _, err := client.Data(context.Background(), "whoisxmlapi.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rec.DomainAvailability != "AVAILABLE" || registry.count() != 0 {
+			t.Errorf("Data() got = %d registry requests, %+v", registry.count(), rec)
+		}
+	})
+}
+
+// TestIPData tests the lookup of the IP network
+func TestIPData(t *testing.T) {
+	server := newStub(t, map[string]string{"/ip/193.0.6.139": ipNetwork})
+
+	// the address is not in the bootstrap, IPServer is used
+	client := New(Params{Bootstrap: &Bootstrap{}, IPServer: server.URL})
+
+	rec, resp, err := client.IPData(context.Background(), netip.MustParseAddr("::ffff:193.0.6.139"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.Range.String() != "193.0.0.0 - 193.0.7.255" || len(rec.Prefixes) != 1 || rec.Prefixes[0].String() != "193.0.0.0/21" {
+		t.Errorf("Range got = %v, %v", rec.Range, rec.Prefixes)
+	}
+	if rec.ASN != 3333 || rec.NetworkName != "RIPE-NCC" || rec.RIR != whoisapi.RIRRIPE {
+		t.Errorf("IPData() got = %d, %q, %q", rec.ASN, rec.NetworkName, rec.RIR)
+	}
+	if rec.Registrant.Organization != "RIPE Network Coordination Centre" {
+		t.Errorf("Registrant got = %+v", rec.Registrant)
+	}
+	if len(rec.AbuseContacts) != 1 || rec.AbuseContacts[0].Email != "abuse@ripe.net" {
+		t.Errorf("AbuseContacts got = %+v", rec.AbuseContacts)
+	}
+	if resp.NormalizedName != "193.0.6.139" || rec.Record.RawText != ipNetwork {
+		t.Errorf("Response got = %q", resp.NormalizedName)
+	}
+
+	whoisRec, _, err := client.Data(context.Background(), "193.0.6.139")
+	if err != nil || whoisRec.Registrant.Organization != "RIPE Network Coordination Centre" {
+		t.Errorf("Data() got = %+v, %v", whoisRec, err)
+	}
+
+	_, _, err = client.IPData(context.Background(), netip.MustParseAddr("192.168.1.1"))
+	var argErr *whoisapi.ArgError
+	if !errors.As(err, &argErr) {
+		t.Errorf("IPData() error = %v, want ArgError", err)
+	}
+}
+
+// TestPrefixData tests the lookup of the IP network containing the prefix
+func TestPrefixData(t *testing.T) {
+	server := newStub(t, map[string]string{"/ip/193.0.4.0/22": ipNetwork, "/ip/193.0.0.0/16": ipNetwork})
+	client := New(Params{Bootstrap: &Bootstrap{}, IPServer: server.URL})
+
+	rec, resp, err := client.PrefixData(context.Background(), netip.MustParsePrefix("193.0.6.139/22"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Prefix.String() != "193.0.4.0/22" || rec.Record.DomainName != "193.0.4.0/22" || resp.NormalizedName != "193.0.4.0/22" {
+		t.Errorf("PrefixData() got = %v, %q, %q", rec.Prefix, rec.Record.DomainName, resp.NormalizedName)
+	}
+	if rec.Range.String() != "193.0.0.0 - 193.0.7.255" || rec.NetworkName != "RIPE-NCC" {
+		t.Errorf("PrefixData() got = %v, %q", rec.Range, rec.NetworkName)
+	}
+
+	// the network of the response is smaller than the prefix
+	_, _, err = client.PrefixData(context.Background(), netip.MustParsePrefix("193.0.0.0/16"))
+	if !errors.Is(err, whoisapi.ErrPrefixNotCovered) {
+		t.Errorf("PrefixData() error = %v, want %v", err, whoisapi.ErrPrefixNotCovered)
+	}
+
+	_, _, err = client.PrefixData(context.Background(), netip.MustParsePrefix("192.168.0.0/16"))
+	var argErr *whoisapi.ArgError
+	if !errors.As(err, &argErr) {
+		t.Errorf("PrefixData() error = %v, want ArgError", err)
+	}
+}
+
+// TestStatusToEPP tests the StatusToEPP function
+func TestStatusToEPP(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"active", "ok"},
+		{"associated", "linked"},
+		{"client transfer prohibited", "clientTransferProhibited"},
+		{"Server Hold", "serverHold"},
+		{"auto renew period", "autoRenewPeriod"},
+		{"pending delete", "pendingDelete"},
+		{"inactive", "inactive"},
+	}
+	for _, tt := range tests {
+		if got := StatusToEPP(tt.in); got != tt.want {
+			t.Errorf("StatusToEPP(%q) got = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,416 @@
+package rdap
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Link is the link to another resource, RFC 9083 section 4.2
+type Link struct {
+	// Value is the context URI
+	Value string `json:"value,omitempty"`
+
+	// Rel is the relation type, e.g. "self" or "related"
+	Rel string `json:"rel,omitempty"`
+
+	// Href is the target URI
+	Href string `json:"href"`
+
+	// Type is the media type of the target, e.g. "application/rdap+json"
+	Type string `json:"type,omitempty"`
+}
+
+// Notice is the notice or the remark, RFC 9083 section 4.3
+type Notice struct {
+	// Title is the title of the notice
+	Title string `json:"title,omitempty"`
+
+	// Type is the type of the notice, e.g. "object redacted due to authorization"
+	Type string `json:"type,omitempty"`
+
+	// Description is the text of the notice by paragraphs
+	Description []string `json:"description,omitempty"`
+
+	// Links are the links of the notice
+	Links []Link `json:"links,omitempty"`
+}
+
+// Event is the event of the object, RFC 9083 section 4.5
+type Event struct {
+	// EventAction is the event type, e.g. "registration", "expiration" or "last changed"
+	EventAction string `json:"eventAction"`
+
+	// EventActor is the handle of the entity responsible for the event
+	EventActor string `json:"eventActor,omitempty"`
+
+	// EventDate is the date of the event in RFC 3339 format
+	EventDate string `json:"eventDate,omitempty"`
+
+	// Links are the links of the event
+	Links []Link `json:"links,omitempty"`
+}
+
+// PublicID is the public identifier of the object, RFC 9083 section 4.8
+type PublicID struct {
+	// Type is the type of the identifier, e.g. "IANA Registrar ID"
+	Type string `json:"type"`
+
+	// Identifier is the identifier
+	Identifier string `json:"identifier"`
+}
+
+// Entity is the person or organization, RFC 9083 section 5.1
+type Entity struct {
+	// ObjectClassName is always "entity"
+	ObjectClassName string `json:"objectClassName"`
+
+	// Handle is the registry unique identifier of the entity
+	Handle string `json:"handle,omitempty"`
+
+	// VCardArray is the contact information of the entity
+	VCardArray VCard `json:"vcardArray,omitempty"`
+
+	// Roles are the relationships of the entity to the containing object, e.g. "registrant" or "abuse"
+	Roles []string `json:"roles,omitempty"`
+
+	// PublicIDs are the public identifiers of the entity
+	PublicIDs []PublicID `json:"publicIds,omitempty"`
+
+	// Entities are the entities related to the entity, e.g. the abuse contact of the registrar
+	Entities []Entity `json:"entities,omitempty"`
+
+	// Remarks are the remarks of the entity
+	Remarks []Notice `json:"remarks,omitempty"`
+
+	// Links are the links of the entity
+	Links []Link `json:"links,omitempty"`
+
+	// Events are the events of the entity
+	Events []Event `json:"events,omitempty"`
+
+	// Status is the status of the entity
+	Status []string `json:"status,omitempty"`
+
+	// Port43 is the host of the WHOIS server with the same data
+	Port43 string `json:"port43,omitempty"`
+}
+
+// HasRole reports whether the entity has the role
+func (e *Entity) HasRole(role string) bool {
+	for _, r := range e.Roles {
+		if strings.EqualFold(r, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// IPAddresses are the addresses of the nameserver
+type IPAddresses struct {
+	// V4 are IPv4 addresses
+	V4 []string `json:"v4,omitempty"`
+
+	// V6 are IPv6 addresses
+	V6 []string `json:"v6,omitempty"`
+}
+
+// Nameserver is the DNS server of the domain, RFC 9083 section 5.2
+type Nameserver struct {
+	// ObjectClassName is always "nameserver"
+	ObjectClassName string `json:"objectClassName"`
+
+	// Handle is the registry unique identifier of the nameserver
+	Handle string `json:"handle,omitempty"`
+
+	// LDHName is the host name in the letters, digits and hyphen format
+	LDHName string `json:"ldhName,omitempty"`
+
+	// UnicodeName is the host name with U-labels
+	UnicodeName string `json:"unicodeName,omitempty"`
+
+	// IPAddresses are the glue records of the nameserver
+	IPAddresses *IPAddresses `json:"ipAddresses,omitempty"`
+
+	// Status is the status of the nameserver
+	Status []string `json:"status,omitempty"`
+
+	// Events are the events of the nameserver
+	Events []Event `json:"events,omitempty"`
+
+	// Links are the links of the nameserver
+	Links []Link `json:"links,omitempty"`
+}
+
+// Domain is the domain name registration, RFC 9083 section 5.3
+type Domain struct {
+	// Conformance are the specifications the response conforms to
+	Conformance []string `json:"rdapConformance,omitempty"`
+
+	// ObjectClassName is always "domain"
+	ObjectClassName string `json:"objectClassName"`
+
+	// Handle is the registry unique identifier of the domain
+	Handle string `json:"handle,omitempty"`
+
+	// LDHName is the domain name in the letters, digits and hyphen format
+	LDHName string `json:"ldhName,omitempty"`
+
+	// UnicodeName is the domain name with U-labels
+	UnicodeName string `json:"unicodeName,omitempty"`
+
+	// Nameservers are the nameservers of the domain
+	Nameservers []Nameserver `json:"nameservers,omitempty"`
+
+	// Entities are the registrar and the contacts of the domain
+	Entities []Entity `json:"entities,omitempty"`
+
+	// Status is the status of the domain, e.g. "client transfer prohibited"
+	Status []string `json:"status,omitempty"`
+
+	// Events are the registration, expiration and other events of the domain
+	Events []Event `json:"events,omitempty"`
+
+	// Links are the links of the domain, the registrar's RDAP record is the "related" one
+	Links []Link `json:"links,omitempty"`
+
+	// Notices are the notices of the response, e.g. the terms of use
+	Notices []Notice `json:"notices,omitempty"`
+
+	// Remarks are the remarks of the domain
+	Remarks []Notice `json:"remarks,omitempty"`
+
+	// Port43 is the host of the WHOIS server with the same data
+	Port43 string `json:"port43,omitempty"`
+}
+
+// CIDR is the prefix of the IP network, the cidr0 extension
+type CIDR struct {
+	// V4Prefix is the IPv4 network address
+	V4Prefix string `json:"v4prefix,omitempty"`
+
+	// V6Prefix is the IPv6 network address
+	V6Prefix string `json:"v6prefix,omitempty"`
+
+	// Length is the prefix length
+	Length int `json:"length"`
+}
+
+// IPNetwork is the IP address block, RFC 9083 section 5.4
+type IPNetwork struct {
+	// ObjectClassName is always "ip network"
+	ObjectClassName string `json:"objectClassName"`
+
+	// Handle is the registry unique identifier of the network
+	Handle string `json:"handle,omitempty"`
+
+	// StartAddress is the first address of the network
+	StartAddress string `json:"startAddress,omitempty"`
+
+	// EndAddress is the last address of the network
+	EndAddress string `json:"endAddress,omitempty"`
+
+	// IPVersion is "v4" or "v6"
+	IPVersion string `json:"ipVersion,omitempty"`
+
+	// Name is the name of the network
+	Name string `json:"name,omitempty"`
+
+	// Type is the RIR-specific type of the network, e.g. "ASSIGNED PA"
+	Type string `json:"type,omitempty"`
+
+	// Country is the two-letter country code of the network
+	Country string `json:"country,omitempty"`
+
+	// ParentHandle is the handle of the parent network
+	ParentHandle string `json:"parentHandle,omitempty"`
+
+	// CIDRs are the prefixes of the network, the cidr0 extension
+	CIDRs []CIDR `json:"cidr0_cidrs,omitempty"`
+
+	// OriginAutnums are the ASNs originating the network, the ARIN originas0 extension
+	OriginAutnums []uint32 `json:"arin_originas0_originautnums,omitempty"`
+
+	// Entities are the registrant and the contacts of the network
+	Entities []Entity `json:"entities,omitempty"`
+
+	// Status is the status of the network
+	Status []string `json:"status,omitempty"`
+
+	// Events are the events of the network
+	Events []Event `json:"events,omitempty"`
+
+	// Links are the links of the network
+	Links []Link `json:"links,omitempty"`
+
+	// Remarks are the remarks of the network
+	Remarks []Notice `json:"remarks,omitempty"`
+
+	// Port43 is the host of the WHOIS server with the same data
+	Port43 string `json:"port43,omitempty"`
+}
+
+// Error is the error response, RFC 9083 section 6
+type Error struct {
+	// ErrorCode is the HTTP status code
+	ErrorCode int `json:"errorCode"`
+
+	// Title is the short description of the error
+	Title string `json:"title,omitempty"`
+
+	// Description is the text of the error by paragraphs
+	Description []string `json:"description,omitempty"`
+}
+
+// Error returns error message as a string
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("RDAP error: [%d] %s", e.ErrorCode, e.Title)
+	if len(e.Description) > 0 {
+		msg += ": " + strings.Join(e.Description, " ")
+	}
+	return msg
+}
+
+// VCardProperty is the jCard property, RFC 7095: ["name", {params}, "type", values...]
+type VCardProperty struct {
+	// Name is the lowercase property name, e.g. "fn", "adr" or "tel"
+	Name string
+
+	// Params are the property parameters, e.g. {"type": ["voice"]}
+	Params map[string]interface{}
+
+	// Type is the value type, e.g. "text" or "uri"
+	Type string
+
+	// Values are the property values, structured values like "adr" are arrays
+	Values []interface{}
+}
+
+// Text returns the first value of the property as a string, structured values are joined with spaces
+func (p VCardProperty) Text() string {
+	if len(p.Values) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(flatten(p.Values[0]))
+}
+
+// Param returns the values of the parameter
+func (p VCardProperty) Param(name string) []string {
+	switch v := p.Params[name].(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, s := range v {
+			if s, ok := s.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// flatten returns the string value or joins the array values with spaces
+func flatten(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, s := range v {
+			if s := flatten(s); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, " ")
+	default:
+		return ""
+	}
+}
+
+// VCard is the jCard of the entity encoded as ["vcard", [properties...]]
+type VCard []VCardProperty
+
+// Get returns the first property with the name
+func (v VCard) Get(name string) (VCardProperty, bool) {
+	for _, p := range v {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return VCardProperty{}, false
+}
+
+// UnmarshalJSON decodes the jCard
+func (v *VCard) UnmarshalJSON(b []byte) error {
+	var card []json.RawMessage
+	if err := json.Unmarshal(b, &card); err != nil {
+		return err
+	}
+	if card == nil {
+		*v = nil
+		return nil
+	}
+
+	var kind string
+	if len(card) != 2 || json.Unmarshal(card[0], &kind) != nil || kind != "vcard" {
+		return fmt.Errorf("cannot decode jCard: %s", b)
+	}
+
+	var props [][]json.RawMessage
+	if err := json.Unmarshal(card[1], &props); err != nil {
+		return fmt.Errorf("cannot decode jCard properties: %w", err)
+	}
+
+	result := make(VCard, 0, len(props))
+	for _, raw := range props {
+		if len(raw) < 4 {
+			return fmt.Errorf("cannot decode jCard property of %d elements", len(raw))
+		}
+
+		var p VCardProperty
+		if err := json.Unmarshal(raw[0], &p.Name); err != nil {
+			return fmt.Errorf("cannot decode jCard property name: %w", err)
+		}
+		if err := json.Unmarshal(raw[1], &p.Params); err != nil {
+			return fmt.Errorf("cannot decode jCard parameters of %q: %w", p.Name, err)
+		}
+		if err := json.Unmarshal(raw[2], &p.Type); err != nil {
+			return fmt.Errorf("cannot decode jCard type of %q: %w", p.Name, err)
+		}
+		for _, rv := range raw[3:] {
+			var value interface{}
+			if err := json.Unmarshal(rv, &value); err != nil {
+				return fmt.Errorf("cannot decode jCard value of %q: %w", p.Name, err)
+			}
+			p.Values = append(p.Values, value)
+		}
+		p.Name = strings.ToLower(p.Name)
+
+		result = append(result, p)
+	}
+
+	*v = result
+	return nil
+}
+
+// MarshalJSON encodes the jCard
+func (v VCard) MarshalJSON() ([]byte, error) {
+	if v == nil {
+		return []byte("null"), nil
+	}
+
+	props := make([][]interface{}, 0, len(v))
+	for _, p := range v {
+		params := p.Params
+		if params == nil {
+			params = map[string]interface{}{}
+		}
+		prop := []interface{}{p.Name, params, p.Type}
+		props = append(props, append(prop, p.Values...))
+	}
+
+	return json.Marshal([]interface{}{"vcard", props})
+}
@@ -0,0 +1,51 @@
+package rdap
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// TestVCard tests the jCard encoding and decoding
+func TestVCard(t *testing.T) {
+	const card = `["vcard",[["version",{},"text","4.0"],["fn",{},"text","Whois API"],` +
+		`["adr",{"cc":"US"},"text",["","",["340 S Lemon Ave","#1717"],"Walnut","CA","91789",""]],` +
+		`["tel",{"type":["voice","work"]},"uri","tel:+1.8003102051"],["categories",{},"text","a","b"]]]`
+
+	var v VCard
+	if err := json.Unmarshal([]byte(card), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if p, _ := v.Get("fn"); p.Text() != "Whois API" {
+		t.Errorf("fn got = %q", p.Text())
+	}
+	if p, _ := v.Get("adr"); p.Text() != "340 S Lemon Ave #1717 Walnut CA 91789" ||
+		!reflect.DeepEqual(p.Param("cc"), []string{"US"}) {
+		t.Errorf("adr got = %q, %v", p.Text(), p.Param("cc"))
+	}
+	if p, _ := v.Get("tel"); !reflect.DeepEqual(p.Param("type"), []string{"voice", "work"}) {
+		t.Errorf("tel type got = %v", p.Param("type"))
+	}
+	if p, _ := v.Get("categories"); len(p.Values) != 2 {
+		t.Errorf("categories got = %v", p.Values)
+	}
+	if _, ok := v.Get("email"); ok {
+		t.Error("Get(email) got = true")
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != card {
+		t.Errorf("MarshalJSON() got = %s, want %s", b, card)
+	}
+
+	invalid := []string{`["vcard"]`, `["jcard",[]]`, `["vcard",[["fn",{},"text"]]]`, `{"fn":"Whois API"}`}
+	for _, s := range invalid {
+		if err := json.Unmarshal([]byte(s), &v); err == nil {
+			t.Errorf("UnmarshalJSON(%s) error = nil", s)
+		}
+	}
+}
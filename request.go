@@ -0,0 +1,177 @@
+package whoisapi
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DAMode is the domain availability check mode
+type DAMode int
+
+const (
+	// DAModeNone skips the domain availability check
+	DAModeNone DAMode = iota
+
+	// DAModeQuick adds the quick domain availability check to the response
+	DAModeQuick
+
+	// DAModeAccurate adds the slower but more accurate domain availability check to the response
+	DAModeAccurate
+)
+
+// OutputFormat is the response format
+type OutputFormat string
+
+const (
+	// OutputFormatDefault leaves the format to the API, which uses XML
+	OutputFormatDefault OutputFormat = ""
+
+	// OutputFormatJSON is the JSON response format
+	OutputFormatJSON OutputFormat = "JSON"
+
+	// OutputFormatXML is the XML response format
+	OutputFormatXML OutputFormat = "XML"
+)
+
+// WhoisRequest is the typed set of Whois API request parameters.
+// The zero value of every field leaves the parameter to the API default
+type WhoisRequest struct {
+	// DomainName is the domain name or IPv4/IPv6 address to look up.
+	// With the default DomainNormalizer an email address looks up the domain after "@"
+	DomainName string
+
+	// OutputFormat is the response format
+	OutputFormat OutputFormat
+
+	// DA is the domain availability check mode
+	DA DAMode
+
+	// IP returns IPs for the domain name
+	IP bool
+
+	// IPWhois returns the Whois record for the hosting IP if the TLD of the domain is not supported
+	IPWhois bool
+
+	// CheckProxyData fetches proxy/Whois guard data, if it exists
+	CheckProxyData bool
+
+	// ThinWhois returns Whois data from the registry only, without fetching data from the registrar
+	ThinWhois bool
+
+	// PreferFresh returns the latest Whois record even if it's incomplete
+	PreferFresh bool
+
+	// IgnoreRawTexts strips all raw texts from the response
+	IgnoreRawTexts bool
+}
+
+// requestFlag is the boolean parameter of WhoisRequest with its query name and option
+type requestFlag struct {
+	name   string
+	value  *bool
+	option func(value int) Option
+}
+
+// flags are the boolean parameters of WhoisRequest
+func (r *WhoisRequest) flags() []requestFlag {
+	return []requestFlag{
+		{"ip", &r.IP, OptionIP},
+		{"ipWhois", &r.IPWhois, OptionIPWhois},
+		{"checkProxyData", &r.CheckProxyData, OptionCheckProxyData},
+		{"thinWhois", &r.ThinWhois, OptionThinWhois},
+		{"preferFresh", &r.PreferFresh, OptionPreferFresh},
+		{"ignoreRawTexts", &r.IgnoreRawTexts, OptionIgnoreRawTexts},
+	}
+}
+
+// Validate checks the request parameters
+func (r WhoisRequest) Validate() error {
+	if r.DomainName == "" {
+		return &ArgError{"domainName", "cannot be empty"}
+	}
+	if r.DA < DAModeNone || r.DA > DAModeAccurate {
+		return &ArgError{"da", "must be 0, 1 or 2, got " + strconv.Itoa(int(r.DA))}
+	}
+	switch r.OutputFormat {
+	case OutputFormatDefault, OutputFormatJSON, OutputFormatXML:
+	default:
+		return &ArgError{"outputFormat", `must be "JSON" or "XML", got "` + string(r.OutputFormat) + `"`}
+	}
+	return nil
+}
+
+// Options returns the request parameters as options. Only non-zero fields are included,
+// the DomainName is passed to Data and RawData separately
+func (r WhoisRequest) Options() []Option {
+	var opts []Option
+	if r.OutputFormat != OutputFormatDefault {
+		opts = append(opts, OptionOutputFormat(string(r.OutputFormat)))
+	}
+	if r.DA != DAModeNone {
+		opts = append(opts, OptionDA(int(r.DA)))
+	}
+	for _, f := range r.flags() {
+		if *f.value {
+			opts = append(opts, f.option(1))
+		}
+	}
+	return opts
+}
+
+// WhoisRequestFromOptions creates WhoisRequest from the domain name and options
+func WhoisRequestFromOptions(name string, opts ...Option) (WhoisRequest, error) {
+	q := url.Values{}
+	for _, opt := range opts {
+		opt(q)
+	}
+
+	r := WhoisRequest{
+		DomainName:   name,
+		OutputFormat: OutputFormat(strings.ToUpper(q.Get("outputFormat"))),
+	}
+
+	if v := q.Get("da"); v != "" {
+		da, err := strconv.Atoi(v)
+		if err != nil {
+			return WhoisRequest{}, &ArgError{"da", "must be an integer, got " + strconv.Quote(v)}
+		}
+		r.DA = DAMode(da)
+	}
+
+	for _, f := range r.flags() {
+		v := q.Get(f.name)
+		switch v {
+		case "", "0":
+		case "1":
+			*f.value = true
+		default:
+			return WhoisRequest{}, &ArgError{f.name, "must be 0 or 1, got " + strconv.Quote(v)}
+		}
+	}
+
+	return r, r.Validate()
+}
+
+// DataRequest validates the request and returns parsed Whois record. Only JSON responses are parsed,
+// OutputFormatXML is rejected, use RawDataRequest instead. The opts change how the request is sent,
+// e.g. to override the API key
+func (c *Client) DataRequest(ctx context.Context, r WhoisRequest, opts ...RequestOption) (*WhoisRecord, *Response, error) {
+	if err := r.Validate(); err != nil {
+		return nil, nil, err
+	}
+	if r.OutputFormat == OutputFormatXML {
+		return nil, nil, &ArgError{"outputFormat", `"XML" cannot be parsed, use RawDataRequest`}
+	}
+	return c.With(opts...).Data(ctx, r.DomainName, r.Options()...)
+}
+
+// RawDataRequest validates the request and returns raw Whois API response.
+// The opts change how the request is sent, e.g. to override the API key
+func (c *Client) RawDataRequest(ctx context.Context, r WhoisRequest, opts ...RequestOption) (*Response, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return c.With(opts...).RawData(ctx, r.DomainName, r.Options()...)
+}
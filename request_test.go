@@ -0,0 +1,180 @@
+package whoisapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+// TestWhoisRequestValidate tests the Validate method
+func TestWhoisRequestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request WhoisRequest
+		wantErr string
+	}{
+		{
+			name:    "valid",
+			request: WhoisRequest{DomainName: "whoisxmlapi.com", DA: DAModeAccurate, OutputFormat: OutputFormatXML},
+		},
+		{
+			name:    "empty domain name",
+			request: WhoisRequest{},
+			wantErr: `invalid argument: "domainName" cannot be empty`,
+		},
+		{
+			name:    "invalid da",
+			request: WhoisRequest{DomainName: "whoisxmlapi.com", DA: 7},
+			wantErr: `invalid argument: "da" must be 0, 1 or 2, got 7`,
+		},
+		{
+			name:    "invalid output format",
+			request: WhoisRequest{DomainName: "whoisxmlapi.com", OutputFormat: "YAML"},
+			wantErr: `invalid argument: "outputFormat" must be "JSON" or "XML", got "YAML"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkErr(t, tt.request.Validate(), tt.wantErr)
+		})
+	}
+}
+
+// TestWhoisRequestOptions tests the conversion from and to options
+func TestWhoisRequestOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		want    WhoisRequest
+		query   string
+		wantErr string
+	}{
+		{
+			name:  "no options",
+			want:  WhoisRequest{DomainName: "whoisxmlapi.com"},
+			query: "",
+		},
+		{
+			name: "all options",
+			opts: []Option{
+				OptionOutputFormat("json"),
+				OptionDA(1),
+				OptionIP(1),
+				OptionIPWhois(1),
+				OptionCheckProxyData(1),
+				OptionThinWhois(1),
+				OptionPreferFresh(1),
+				OptionIgnoreRawTexts(1),
+			},
+			want: WhoisRequest{
+				DomainName:     "whoisxmlapi.com",
+				OutputFormat:   OutputFormatJSON,
+				DA:             DAModeQuick,
+				IP:             true,
+				IPWhois:        true,
+				CheckProxyData: true,
+				ThinWhois:      true,
+				PreferFresh:    true,
+				IgnoreRawTexts: true,
+			},
+			query: "checkProxyData=1&da=1&ignoreRawTexts=1&ip=1&ipWhois=1&outputFormat=JSON&preferFresh=1&thinWhois=1",
+		},
+		{
+			name:  "zero values are omitted",
+			opts:  []Option{OptionDA(0), OptionIP(0)},
+			want:  WhoisRequest{DomainName: "whoisxmlapi.com"},
+			query: "",
+		},
+		{
+			name:    "invalid da",
+			opts:    []Option{OptionDA(7)},
+			wantErr: `invalid argument: "da" must be 0, 1 or 2, got 7`,
+		},
+		{
+			name:    "invalid flag",
+			opts:    []Option{OptionThinWhois(2)},
+			wantErr: `invalid argument: "thinWhois" must be 0 or 1, got "2"`,
+		},
+		{
+			name:    "invalid output format",
+			opts:    []Option{OptionOutputFormat("yaml")},
+			wantErr: `invalid argument: "outputFormat" must be "JSON" or "XML", got "YAML"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WhoisRequestFromOptions("whoisxmlapi.com", tt.opts...)
+			checkErr(t, err, tt.wantErr)
+			if tt.wantErr != "" {
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WhoisRequestFromOptions() got = %+v, want %+v", got, tt.want)
+			}
+
+			q := url.Values{}
+			for _, opt := range got.Options() {
+				opt(q)
+			}
+			if q.Encode() != tt.query {
+				t.Errorf("Options() got = %v, want %v", q.Encode(), tt.query)
+			}
+		})
+	}
+}
+
+// TestDataRequest tests the DataRequest and RawDataRequest methods
+func TestDataRequest(t *testing.T) {
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		queries = append(queries, req.URL.Query())
+		_, _ = w.Write([]byte(`{"WhoisRecord": {"domainName": "whoisxmlapi.com"}}`))
+	}))
+	defer server.Close()
+
+	apiURL, _ := url.Parse(server.URL)
+	client := NewClient(apiKey, ClientParams{HTTPClient: server.Client(), WhoisBaseURL: apiURL})
+
+	r := WhoisRequest{DomainName: "whoisxmlapi.com", DA: DAModeAccurate, ThinWhois: true}
+	rec, _, err := client.DataRequest(context.Background(), r, OptionAPIKey("at_billing_key"))
+	if err != nil || rec.DomainName != "whoisxmlapi.com" {
+		t.Fatalf("DataRequest() got = %v, %v", rec, err)
+	}
+
+	if _, err := client.RawDataRequest(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+
+	xml := r
+	xml.OutputFormat = OutputFormatXML
+	_, _, err = client.DataRequest(context.Background(), xml)
+	checkErr(t, err, `invalid argument: "outputFormat" "XML" cannot be parsed, use RawDataRequest`)
+
+	r.DA = 3
+	_, err = client.RawDataRequest(context.Background(), r)
+	checkErr(t, err, `invalid argument: "da" must be 0, 1 or 2, got 3`)
+
+	// the email is not looked up as is, the default normalizer keeps its domain
+	email := WhoisRequest{DomainName: "Support@WhoisXMLAPI.com"}
+	_, resp, err := client.DataRequest(context.Background(), email)
+	if err != nil || resp.Name != "Support@WhoisXMLAPI.com" || resp.NormalizedName != "whoisxmlapi.com" {
+		t.Errorf("DataRequest() got = %+v, %v", resp, err)
+	}
+
+	if len(queries) != 3 {
+		t.Fatalf("requests got = %v, want 3", len(queries))
+	}
+	if q := queries[0]; q.Get("da") != "2" || q.Get("thinWhois") != "1" || q.Get("apiKey") != "at_billing_key" {
+		t.Errorf("query got = %v", q)
+	}
+	if q := queries[1]; q.Get("apiKey") != apiKey {
+		t.Errorf("query got = %v", q)
+	}
+	if q := queries[2]; q.Get("domainName") != "whoisxmlapi.com" {
+		t.Errorf("query got = %v", q)
+	}
+}
@@ -0,0 +1,91 @@
+package whoisapi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// flight is the request shared by concurrent identical lookups
+type flight struct {
+	done   chan struct{}
+	cancel context.CancelFunc
+
+	// waiters is the number of callers still waiting for the result
+	waiters int
+
+	resp *Response
+	err  error
+}
+
+// flightGroup collapses concurrent identical requests into one
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+// newFlightGroup creates an empty flightGroup
+func newFlightGroup() *flightGroup {
+	return &flightGroup{flights: make(map[string]*flight)}
+}
+
+// do calls send once for all concurrent callers with the same key and returns a copy of the Response to each of them.
+// The shared call doesn't inherit the cancellation of any caller, it's cancelled when all callers are gone.
+// It keeps the deadline of the caller that started it, callers joining it get its timeout error.
+// joined reports whether the caller got the result of the call started by another one
+func (g *flightGroup) do(
+	ctx context.Context,
+	key string,
+	send func(ctx context.Context) (*Response, error),
+) (resp *Response, joined bool, err error) {
+
+	g.mu.Lock()
+	f, joined := g.flights[key]
+	if !joined {
+		sendCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		if deadline, ok := ctx.Deadline(); ok {
+			sendCtx, cancel = context.WithDeadline(context.WithoutCancel(ctx), deadline)
+		}
+		f = &flight{done: make(chan struct{}), cancel: cancel}
+		g.flights[key] = f
+
+		go func() {
+			f.resp, f.err = send(sendCtx)
+
+			g.mu.Lock()
+			if g.flights[key] == f {
+				delete(g.flights, key)
+			}
+			g.mu.Unlock()
+
+			cancel()
+			close(f.done)
+		}()
+	}
+	f.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+		if f.resp == nil {
+			return nil, joined, f.err
+		}
+		// every caller sets its own name and decoding results
+		shared := *f.resp
+		return &shared, joined, f.err
+
+	case <-ctx.Done():
+		g.mu.Lock()
+		f.waiters--
+		if f.waiters == 0 {
+			// nobody waits for the result, new callers start another request
+			f.cancel()
+			if g.flights[key] == f {
+				delete(g.flights, key)
+			}
+		}
+		g.mu.Unlock()
+
+		return nil, joined, fmt.Errorf("cannot execute request: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,334 @@
+package whoisapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// blockingServer is the API server stub answering the requests when release is closed
+type blockingServer struct {
+	*httptest.Server
+
+	calls    int32
+	release  chan struct{}
+	canceled chan struct{}
+}
+
+// newBlockingServer creates blockingServer
+func newBlockingServer() *blockingServer {
+	s := &blockingServer{release: make(chan struct{}), canceled: make(chan struct{}, 10)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&s.calls, 1)
+		select {
+		case <-s.release:
+			_, _ = w.Write([]byte(`{"WhoisRecord": {"domainName": "whoisxmlapi.com"}}`))
+		case <-req.Context().Done():
+			s.canceled <- struct{}{}
+		}
+	}))
+	return s
+}
+
+// newDedupAPI returns the client of the server
+func newDedupAPI(server *blockingServer, disable bool) *Client {
+	return newDedupAPIWithMeter(server, disable, nil)
+}
+
+// newDedupAPIWithMeter returns the client of the server recording metrics with the provider
+func newDedupAPIWithMeter(server *blockingServer, disable bool, mp metric.MeterProvider) *Client {
+	apiURL, err := url.Parse(server.URL)
+	if err != nil {
+		panic(err)
+	}
+	return NewClient(apiKey, ClientParams{
+		HTTPClient:           server.Client(),
+		WhoisBaseURL:         apiURL,
+		MeterProvider:        mp,
+		DisableDeduplication: disable,
+	})
+}
+
+// waitWaiters waits until n callers wait for the requests
+func waitWaiters(t *testing.T, client *Client, n int) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		client.flights.mu.Lock()
+		waiters := 0
+		for _, f := range client.flights.flights {
+			waiters += f.waiters
+		}
+		client.flights.mu.Unlock()
+
+		if waiters == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("waiters didn't reach %d", n)
+}
+
+// TestDeduplication tests that concurrent identical lookups share one request
+func TestDeduplication(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      [][]Option
+		reqOpts   [][]RequestOption
+		names     []string
+		wantCalls int32
+	}{
+		{
+			name:      "identical",
+			names:     []string{"whoisxmlapi.com", "whoisxmlapi.com", "WhoisXMLAPI.com", "whoisxmlapi.com."},
+			opts:      [][]Option{nil, nil, nil, nil},
+			wantCalls: 1,
+		},
+		{
+			name:      "different options",
+			names:     []string{"whoisxmlapi.com", "whoisxmlapi.com", "whoisxmlapi.com"},
+			opts:      [][]Option{nil, {OptionDA(1)}, {OptionDA(1)}},
+			wantCalls: 2,
+		},
+		{
+			name:      "different names",
+			names:     []string{"whoisxmlapi.com", "example.com"},
+			opts:      [][]Option{nil, nil},
+			wantCalls: 2,
+		},
+		{
+			name:      "per-call API keys",
+			names:     []string{"whoisxmlapi.com", "whoisxmlapi.com", "whoisxmlapi.com"},
+			opts:      [][]Option{nil, nil, nil},
+			reqOpts:   [][]RequestOption{{OptionAPIKey("at_first")}, {OptionAPIKey("at_second")}, {OptionAPIKey("at_first")}},
+			wantCalls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newBlockingServer()
+			defer server.Close()
+			client := newDedupAPI(server, false)
+
+			responses := make([]*Response, len(tt.names))
+			var wg sync.WaitGroup
+			for i := range tt.names {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					var reqOpts []RequestOption
+					if tt.reqOpts != nil {
+						reqOpts = tt.reqOpts[i]
+					}
+					rec, resp, err := client.With(reqOpts...).Data(context.Background(), tt.names[i], tt.opts[i]...)
+					if err != nil || rec == nil || rec.DomainName != "whoisxmlapi.com" {
+						t.Errorf("Data() got = %v, %v", rec, err)
+					}
+					responses[i] = resp
+				}(i)
+			}
+			waitWaiters(t, client, len(tt.names))
+			close(server.release)
+			wg.Wait()
+
+			if server.calls != tt.wantCalls {
+				t.Errorf("calls got = %d, want %d", server.calls, tt.wantCalls)
+			}
+			for i, resp := range responses {
+				if resp == nil || resp.Name != tt.names[i] || len(resp.Body) == 0 || resp.StatusCode != http.StatusOK {
+					t.Errorf("response %d got = %+v", i, resp)
+				}
+				for _, other := range responses[:i] {
+					if resp == other {
+						t.Errorf("response %d is shared by pointer", i)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestDeduplicationCancel tests that a cancelled caller doesn't cancel the shared request
+func TestDeduplicationCancel(t *testing.T) {
+	server := newBlockingServer()
+	defer server.Close()
+	client := newDedupAPI(server, false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := client.RawData(ctx, "whoisxmlapi.com")
+		firstErr <- err
+	}()
+	waitWaiters(t, client, 1)
+
+	secondErr := make(chan error, 1)
+	go func() {
+		_, err := client.RawData(context.Background(), "whoisxmlapi.com")
+		secondErr <- err
+	}()
+	waitWaiters(t, client, 2)
+
+	cancel()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled RawData() error = %v, want context.Canceled", err)
+	}
+
+	close(server.release)
+	if err := <-secondErr; err != nil {
+		t.Errorf("RawData() error = %v", err)
+	}
+	if server.calls != 1 || len(server.canceled) != 0 {
+		t.Errorf("calls, canceled got = %d, %d, want 1, 0", server.calls, len(server.canceled))
+	}
+}
+
+// TestDeduplicationCancelAll tests that the shared request is cancelled when all callers are gone
+func TestDeduplicationCancelAll(t *testing.T) {
+	server := newBlockingServer()
+	defer server.Close()
+	defer close(server.release)
+	client := newDedupAPI(server, false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := client.RawData(ctx, "whoisxmlapi.com")
+			errs <- err
+		}()
+	}
+	waitWaiters(t, client, 2)
+
+	cancel()
+	for i := 0; i < 2; i++ {
+		if err := <-errs; !errors.Is(err, context.Canceled) {
+			t.Errorf("RawData() error = %v, want context.Canceled", err)
+		}
+	}
+
+	select {
+	case <-server.canceled:
+	case <-time.After(5 * time.Second):
+		t.Error("the shared request wasn't cancelled")
+	}
+}
+
+// TestDeduplicationDeadline tests that the shared request keeps the deadline of the caller that started it
+func TestDeduplicationDeadline(t *testing.T) {
+	server := newBlockingServer()
+	defer server.Close()
+	defer close(server.release)
+	client := newDedupAPI(server, false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	errs := make(chan error, 2)
+	go func() {
+		_, err := client.RawData(ctx, "whoisxmlapi.com")
+		errs <- err
+	}()
+	waitWaiters(t, client, 1)
+
+	// the caller without a deadline doesn't wait longer than the shared request
+	go func() {
+		_, err := client.RawData(context.Background(), "whoisxmlapi.com")
+		errs <- err
+	}()
+	waitWaiters(t, client, 2)
+
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-errs:
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("RawData() error = %v, want context.DeadlineExceeded", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("the shared request didn't time out")
+		}
+	}
+	if calls := atomic.LoadInt32(&server.calls); calls != 1 {
+		t.Errorf("calls got = %d, want 1", calls)
+	}
+}
+
+// TestDisableDeduplication tests that every lookup sends its own request
+func TestDisableDeduplication(t *testing.T) {
+	server := newBlockingServer()
+	defer server.Close()
+	client := newDedupAPI(server, true)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, err := client.Data(context.Background(), "whoisxmlapi.com"); err != nil {
+				t.Errorf("Data() error = %v", err)
+			}
+		}()
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&server.calls) < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	close(server.release)
+	wg.Wait()
+
+	if server.calls != 3 {
+		t.Errorf("calls got = %d, want 3", server.calls)
+	}
+}
+
+// TestDeduplicationMetrics tests that the shared lookups are counted as cache hits
+func TestDeduplicationMetrics(t *testing.T) {
+	server := newBlockingServer()
+	defer server.Close()
+	reader := sdkmetric.NewManualReader()
+	client := newDedupAPIWithMeter(server, false, sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.RawData(context.Background(), "whoisxmlapi.com"); err != nil {
+				t.Errorf("RawData() error = %v", err)
+			}
+		}()
+	}
+	waitWaiters(t, client, 3)
+	close(server.release)
+	wg.Wait()
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if data, ok := m.Data.(metricdata.Sum[int64]); ok {
+				for _, dp := range data.DataPoints {
+					got[m.Name] += dp.Value
+				}
+			}
+		}
+	}
+	if got["whoisapi.client.cache.hits"] != 2 || got["whoisapi.client.cache.misses"] != 1 {
+		t.Errorf("hits, misses got = %d, %d, want 2, 1", got["whoisapi.client.cache.hits"], got["whoisapi.client.cache.misses"])
+	}
+}
@@ -0,0 +1,188 @@
+package whoisapi
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"strings"
+	"sync"
+)
+
+// publicSuffixList is the ICANN section of the Public Suffix List, https://publicsuffix.org
+//
+//go:embed public_suffix_list.dat
+var publicSuffixList []byte
+
+// suffixRule is the set of Public Suffix List rule kinds for the name
+type suffixRule int
+
+const (
+	suffixRuleNormal suffixRule = 1 << iota
+	suffixRuleWildcard
+	suffixRuleException
+)
+
+// SuffixList is the Public Suffix List used to find registrable domains.
+// Only rules of the ICANN section are used because private suffixes like github.io
+// are not registered with registrars and have no Whois records of their own
+type SuffixList struct {
+	rules map[string]suffixRule
+}
+
+// defaultSuffixList is the embedded list parsed on first use
+var defaultSuffixList = sync.OnceValue(func() *SuffixList {
+	list, err := LoadSuffixList(bytes.NewReader(publicSuffixList))
+	if err != nil {
+		panic(err)
+	}
+	return list
+})
+
+// DefaultSuffixList returns the Public Suffix List embedded in the package
+func DefaultSuffixList() *SuffixList {
+	return defaultSuffixList()
+}
+
+// LoadSuffixListFile loads the Public Suffix List from the file in the public_suffix_list.dat format
+func LoadSuffixListFile(path string) (*SuffixList, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open suffix list: %w", err)
+	}
+	defer f.Close()
+
+	return LoadSuffixList(f)
+}
+
+// LoadSuffixList loads the Public Suffix List in the public_suffix_list.dat format.
+// If the list has section markers then only the ICANN section is loaded
+func LoadSuffixList(r io.Reader) (*SuffixList, error) {
+	list := &SuffixList{rules: make(map[string]suffixRule)}
+
+	var sections, icann bool
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case strings.HasPrefix(line, "// ===BEGIN ICANN DOMAINS==="):
+			sections, icann = true, true
+			continue
+		case strings.HasPrefix(line, "// ===END ICANN DOMAINS==="):
+			icann = false
+			continue
+		case line == "" || strings.HasPrefix(line, "//"):
+			continue
+		case sections && !icann:
+			continue
+		}
+
+		// rules end at the first whitespace
+		if i := strings.IndexAny(line, " \t"); i >= 0 {
+			line = line[:i]
+		}
+
+		kind := suffixRuleNormal
+		if strings.HasPrefix(line, "!") {
+			kind = suffixRuleException
+			line = line[1:]
+		} else if strings.HasPrefix(line, "*.") {
+			kind = suffixRuleWildcard
+			line = line[2:]
+		}
+
+		suffix, err := normalizeSuffix(line)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse suffix list: line %d: %w", lineNo, err)
+		}
+		list.rules[suffix] |= kind
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read suffix list: %w", err)
+	}
+
+	if len(list.rules) == 0 {
+		return nil, fmt.Errorf("cannot parse suffix list: no rules")
+	}
+
+	return list, nil
+}
+
+// normalizeSuffix converts the rule to lowercase A-labels
+func normalizeSuffix(rule string) (string, error) {
+	labels := strings.Split(strings.ToLower(rule), ".")
+	for i, label := range labels {
+		ascii, err := toASCII(label)
+		if err != nil {
+			return "", err
+		}
+		labels[i] = ascii
+	}
+	return strings.Join(labels, "."), nil
+}
+
+// PublicSuffix returns the public suffix of the normalized host, e.g. "co.uk" for "mail.example.co.uk".
+// The last label is the public suffix if no rule matches
+func (l *SuffixList) PublicSuffix(host string) string {
+	labels := strings.Split(host, ".")
+
+	// the longest matching rule wins, exceptions mean their parent is the suffix
+	for i := range labels {
+		name := strings.Join(labels[i:], ".")
+		rule := l.rules[name]
+		switch {
+		case rule&suffixRuleException != 0:
+			return strings.Join(labels[i+1:], ".")
+		case rule&suffixRuleNormal != 0:
+			return name
+		case i+1 < len(labels) && l.rules[strings.Join(labels[i+1:], ".")]&suffixRuleWildcard != 0:
+			return name
+		}
+	}
+
+	return labels[len(labels)-1]
+}
+
+// SplitDomain normalizes the host and splits it into the subdomain and the registrable domain,
+// e.g. "mail.eu" and "example.co.uk" for "mail.eu.example.co.uk".
+// It returns ArgError if the host is an IP address or a public suffix itself
+func (l *SuffixList) SplitDomain(host string) (subdomain, domain string, err error) {
+	name, err := NormalizeDomain(host)
+	if err != nil {
+		return "", "", err
+	}
+	if _, err := netip.ParseAddr(name); err == nil {
+		return "", "", &ArgError{"name", `"` + name + `" is an IP address`}
+	}
+
+	suffix := l.PublicSuffix(name)
+	if suffix == name {
+		return "", "", &ArgError{"name", `"` + name + `" is a public suffix`}
+	}
+
+	rest := strings.TrimSuffix(name, "."+suffix)
+	i := strings.LastIndex(rest, ".")
+
+	return rest[:max(i, 0)], rest[i+1:] + "." + suffix, nil
+}
+
+// RegistrableDomain returns the normalized registrable domain of the host, e.g. "example.co.uk"
+// for "mail.eu.example.co.uk". It returns ArgError if the host is an IP address or a public suffix itself
+func (l *SuffixList) RegistrableDomain(host string) (string, error) {
+	_, domain, err := l.SplitDomain(host)
+	return domain, err
+}
+
+// SplitDomain splits the host into the subdomain and the registrable domain using the embedded list
+func SplitDomain(host string) (subdomain, domain string, err error) {
+	return DefaultSuffixList().SplitDomain(host)
+}
+
+// RegistrableDomain returns the registrable domain of the host using the embedded list
+func RegistrableDomain(host string) (string, error) {
+	return DefaultSuffixList().RegistrableDomain(host)
+}
@@ -0,0 +1,137 @@
+package whoisapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSplitDomain tests the SplitDomain function with the embedded list
+func TestSplitDomain(t *testing.T) {
+	tests := []struct {
+		host      string
+		subdomain string
+		domain    string
+		wantErr   string
+	}{
+		{"whoisxmlapi.com", "", "whoisxmlapi.com", ""},
+		{"mail.eu.example.co.uk", "mail.eu", "example.co.uk", ""},
+		{"https://WWW.Example.COM.au/path", "www", "example.com.au", ""},
+		{"user.github.io", "user", "github.io", ""},
+		{"a.b.example.unknowntld", "a.b", "example.unknowntld", ""},
+		{"www.bücher.de", "www", "xn--bcher-kva.de", ""},
+		{"shop.example.公司.cn", "shop", "example.xn--55qx5d.cn", ""},
+		// *.ck with the !www.ck exception
+		{"www.ck", "", "www.ck", ""},
+		{"a.b.ck", "", "a.b.ck", ""},
+		{"co.uk", "", "", `invalid argument: "name" "co.uk" is a public suffix`},
+		{"b.ck", "", "", `invalid argument: "name" "b.ck" is a public suffix`},
+		{"8.8.8.8", "", "", `invalid argument: "name" "8.8.8.8" is an IP address`},
+		{"", "", "", `invalid argument: "name" cannot be empty`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			subdomain, domain, err := SplitDomain(tt.host)
+			checkErr(t, err, tt.wantErr)
+			if subdomain != tt.subdomain || domain != tt.domain {
+				t.Errorf("SplitDomain() got = %q, %q, want %q, %q", subdomain, domain, tt.subdomain, tt.domain)
+			}
+		})
+	}
+
+	if got, err := RegistrableDomain("mail.eu.example.co.uk"); err != nil || got != "example.co.uk" {
+		t.Errorf("RegistrableDomain() got = %v, %v", got, err)
+	}
+}
+
+// TestLoadSuffixListFile tests loading the list from a file
+func TestLoadSuffixListFile(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "valid.dat")
+	data := strings.Join([]string{
+		"// ===BEGIN ICANN DOMAINS===",
+		"uk",
+		"co.uk",
+		"*.test",
+		"!www.test",
+		"// ===END ICANN DOMAINS===",
+		"// ===BEGIN PRIVATE DOMAINS===",
+		"blogspot.co.uk",
+		"// ===END PRIVATE DOMAINS===",
+	}, "\n")
+	if err := os.WriteFile(valid, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := LoadSuffixListFile(valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		host   string
+		suffix string
+	}{
+		{"a.example.co.uk", "co.uk"},
+		{"a.blogspot.co.uk", "co.uk"},
+		{"a.example.test", "example.test"},
+		{"www.test", "test"},
+		{"a.test", "a.test"},
+		{"example.com", "com"},
+	}
+	for _, tt := range tests {
+		if got := list.PublicSuffix(tt.host); got != tt.suffix {
+			t.Errorf("PublicSuffix(%v) got = %v, want %v", tt.host, got, tt.suffix)
+		}
+	}
+
+	_, err = LoadSuffixListFile(filepath.Join(dir, "missing.dat"))
+	if err == nil || !strings.HasPrefix(err.Error(), "cannot open suffix list:") {
+		t.Errorf("LoadSuffixListFile() error = %v", err)
+	}
+
+	invalid := filepath.Join(dir, "invalid.dat")
+	if err := os.WriteFile(invalid, []byte("uk\nbad_rule.uk\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, err = LoadSuffixListFile(invalid)
+	checkErr(t, err, `cannot parse suffix list: line 2: invalid argument: "name" label "bad_rule" contains invalid character '_'`)
+}
+
+// TestOptionRegistrableDomain tests that Data looks up the registrable domain
+func TestOptionRegistrableDomain(t *testing.T) {
+	var names []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		names = append(names, req.URL.Query().Get("domainName"))
+		_, _ = w.Write([]byte(`{"WhoisRecord": {"domainName": "example.co.uk"}}`))
+	}))
+	defer server.Close()
+
+	apiURL, _ := url.Parse(server.URL)
+	client := NewClient(apiKey, ClientParams{HTTPClient: server.Client(), WhoisBaseURL: apiURL})
+
+	_, resp, err := client.With(OptionRegistrableDomain()).Data(context.Background(), "mail.eu.example.co.uk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Name != "mail.eu.example.co.uk" || resp.NormalizedName != "example.co.uk" || resp.Subdomain != "mail.eu" {
+		t.Errorf("Response got = %q, %q, %q", resp.Name, resp.NormalizedName, resp.Subdomain)
+	}
+
+	if _, err := client.With(OptionRegistrableDomain()).RawData(context.Background(), "8.8.8.8"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.With(OptionRegistrableDomain()).RawData(context.Background(), "co.uk")
+	checkErr(t, err, `invalid argument: "name" "co.uk" is a public suffix`)
+
+	if strings.Join(names, ",") != "example.co.uk,8.8.8.8" {
+		t.Errorf("names sent got = %v", names)
+	}
+}
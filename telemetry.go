@@ -0,0 +1,225 @@
+package whoisapi
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+// instrumentationName is the name of the OpenTelemetry tracer and meter
+const instrumentationName = "github.com/whois-api-llc/whois-api-go"
+
+// Attribute keys used in spans and metrics
+const (
+	attrDomain     = attribute.Key("whoisapi.domain")
+	attrOptions    = attribute.Key("whoisapi.options")
+	attrErrorCode  = attribute.Key("whoisapi.error_code")
+	attrMethod     = attribute.Key("http.request.method")
+	attrStatusCode = attribute.Key("http.response.status_code")
+	attrServer     = attribute.Key("server.address")
+	attrPath       = attribute.Key("url.path")
+	attrState      = attribute.Key("whoisapi.circuit.state")
+)
+
+// Error codes recorded for failures that are not Whois API error messages
+const (
+	errorCodeTransport = "transport"
+	errorCodeHTTP      = "http"
+	errorCodeParse     = "parse"
+	errorCodeArgument  = "argument"
+	errorCodeNoKeys    = "no_keys"
+	errorCodeCircuit   = "circuit_open"
+)
+
+// telemetry holds OpenTelemetry tracer and instruments of the Client
+type telemetry struct {
+	tracer trace.Tracer
+
+	// redact removes secrets from error messages recorded in spans
+	redact func(s string) string
+
+	requests    metric.Int64Counter
+	retries     metric.Int64Counter
+	cacheHits   metric.Int64Counter
+	cacheMisses metric.Int64Counter
+	circuit     metric.Int64Counter
+	rejected    metric.Int64Counter
+	errors      metric.Int64Counter
+	bytesRead   metric.Int64Counter
+	duration    metric.Float64Histogram
+}
+
+// newTelemetry creates telemetry with the providers, nil providers are replaced with no-op ones
+func newTelemetry(tp trace.TracerProvider, mp metric.MeterProvider, redact func(s string) string) *telemetry {
+	if tp == nil {
+		tp = tracenoop.NewTracerProvider()
+	}
+	if mp == nil {
+		mp = metricnoop.NewMeterProvider()
+	}
+
+	meter := mp.Meter(instrumentationName, metric.WithInstrumentationVersion(libraryVersion))
+	t := &telemetry{
+		tracer: tp.Tracer(instrumentationName, trace.WithInstrumentationVersion(libraryVersion)),
+		redact: redact,
+	}
+
+	// errors are only returned for invalid instrument names, and the names are valid constants
+	t.requests, _ = meter.Int64Counter("whoisapi.client.requests",
+		metric.WithDescription("Number of HTTP requests sent to the API"))
+	t.retries, _ = meter.Int64Counter("whoisapi.client.retries",
+		metric.WithDescription("Number of requests retried with another API key"))
+	t.cacheHits, _ = meter.Int64Counter("whoisapi.client.cache.hits",
+		metric.WithDescription("Number of lookups that shared the request of a concurrent identical lookup"))
+	t.cacheMisses, _ = meter.Int64Counter("whoisapi.client.cache.misses",
+		metric.WithDescription("Number of shareable lookups that sent their own request"))
+	t.circuit, _ = meter.Int64Counter("whoisapi.client.circuit.state_changes",
+		metric.WithDescription("Number of circuit breaker state changes by the new state"))
+	t.rejected, _ = meter.Int64Counter("whoisapi.client.circuit.rejected",
+		metric.WithDescription("Number of requests failed with ErrCircuitOpen without being sent"))
+	t.errors, _ = meter.Int64Counter("whoisapi.client.errors",
+		metric.WithDescription("Number of failed lookups by error code"))
+	t.bytesRead, _ = meter.Int64Counter("whoisapi.client.bytes_read",
+		metric.WithDescription("Number of response body bytes read"), metric.WithUnit("By"))
+	t.duration, _ = meter.Float64Histogram("whoisapi.client.request.duration",
+		metric.WithDescription("Duration of HTTP requests sent to the API"), metric.WithUnit("s"))
+
+	return t
+}
+
+// startLookup starts the span of the service method call
+func (t *telemetry) startLookup(ctx context.Context, method, name string, opts []Option) (context.Context, trace.Span) {
+	return t.tracer.Start(ctx, "WhoisService."+method, trace.WithAttributes(
+		attrDomain.String(name),
+		attrOptions.String(optionsString(opts)),
+	))
+}
+
+// setStatusCode adds the HTTP status code of the response to the lookup span
+func (t *telemetry) setStatusCode(span trace.Span, resp *Response) {
+	if resp != nil && resp.Response != nil {
+		span.SetAttributes(attrStatusCode.Int(resp.StatusCode))
+	}
+}
+
+// endLookup records the lookup error, if any, and ends the span
+func (t *telemetry) endLookup(ctx context.Context, span trace.Span, err error) {
+	if err != nil {
+		code := errorCode(err)
+		t.errors.Add(ctx, 1, metric.WithAttributes(attrErrorCode.String(code)))
+		span.SetAttributes(attrErrorCode.String(code))
+		span.SetStatus(codes.Error, t.redact(err.Error()))
+	}
+
+	span.End()
+}
+
+// retry records the request retried with another API key
+func (t *telemetry) retry(ctx context.Context) {
+	t.retries.Add(ctx, 1)
+}
+
+// cacheLookup records the lookup that shared the request of a concurrent identical lookup as a hit,
+// and the one that sent its own request as a miss
+func (t *telemetry) cacheLookup(ctx context.Context, hit bool) {
+	if hit {
+		t.cacheHits.Add(ctx, 1)
+	} else {
+		t.cacheMisses.Add(ctx, 1)
+	}
+}
+
+// circuitChange records the state change of the circuit breaker
+func (t *telemetry) circuitChange(ctx context.Context, change *circuitChange) {
+	if change != nil {
+		t.circuit.Add(ctx, 1, metric.WithAttributes(attrState.String(change.to.String())))
+	}
+}
+
+// circuitRejected records the request failed by the open circuit breaker
+func (t *telemetry) circuitRejected(ctx context.Context) {
+	t.rejected.Add(ctx, 1)
+}
+
+// startRequest starts the span of the HTTP request. The query is not recorded as it contains the API key
+func (t *telemetry) startRequest(ctx context.Context, method string, u *url.URL) (context.Context, trace.Span, time.Time) {
+	ctx, span := t.tracer.Start(ctx, "HTTP "+method, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attrMethod.String(method),
+		attrServer.String(u.Host),
+		attrPath.String(u.Path),
+	))
+	return ctx, span, time.Now()
+}
+
+// endRequest records the HTTP request metrics and ends the span
+func (t *telemetry) endRequest(ctx context.Context, span trace.Span, start time.Time, status int, n int64, err error) {
+	attrs := []attribute.KeyValue{attrStatusCode.Int(status)}
+
+	t.requests.Add(ctx, 1, metric.WithAttributes(attrs...))
+	t.duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(attrs...))
+	t.bytesRead.Add(ctx, n)
+
+	span.SetAttributes(attrs...)
+	if err != nil {
+		span.SetStatus(codes.Error, t.redact(err.Error()))
+	}
+	span.End()
+}
+
+// errorCode returns the code of the lookup error
+func errorCode(err error) string {
+	var msgErr ErrorMessage
+	var respErr ErrorResponse
+	var argErr *ArgError
+	var parseErr *parseError
+
+	switch {
+	case errors.Is(err, ErrNoAvailableKeys):
+		return errorCodeNoKeys
+	case errors.Is(err, ErrCircuitOpen):
+		return errorCodeCircuit
+	case errors.As(err, &msgErr):
+		return msgErr.ErrorCode
+	case errors.As(err, &respErr):
+		return errorCodeHTTP
+	case errors.As(err, &argErr):
+		return errorCodeArgument
+	case errors.As(err, &parseErr):
+		return errorCodeParse
+	}
+	return errorCodeTransport
+}
+
+// optionsString returns the options as a sorted query string
+func optionsString(opts []Option) string {
+	v := url.Values{}
+	for _, opt := range opts {
+		opt(v)
+	}
+	v.Del("apiKey")
+
+	keys := make([]string, 0, len(v))
+	for k := range v {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		if b.Len() > 0 {
+			b.WriteByte('&')
+		}
+		b.WriteString(k + "=" + strings.Join(v[k], ","))
+	}
+	return b.String()
+}
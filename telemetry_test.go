@@ -0,0 +1,206 @@
+package whoisapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// newInstrumentedAPI returns new Whois API client with in-memory exporters for testing
+func newInstrumentedAPI(link string) (*Client, *tracetest.InMemoryExporter, *sdkmetric.ManualReader, func()) {
+	const resp = `{"WhoisRecord": {"domainName": "whoisxmlapi.com"}}`
+	const errResp = `{"ErrorMessage": {"errorCode": "WHOIS_00", "msg": "test error message"}}`
+
+	server := whoisServer(resp, "<xml/>", errResp)
+
+	apiURL, err := url.Parse(server.URL)
+	if err != nil {
+		panic(err)
+	}
+	apiURL.Path = link
+
+	exporter := tracetest.NewInMemoryExporter()
+	reader := sdkmetric.NewManualReader()
+
+	client := NewClient(apiKey, ClientParams{
+		HTTPClient:     server.Client(),
+		WhoisBaseURL:   apiURL,
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)),
+		MeterProvider:  sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	})
+
+	return client, exporter, reader, server.Close
+}
+
+// TestTelemetrySpans tests the lookup and HTTP request spans
+func TestTelemetrySpans(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int64
+		wantCode   string
+	}{
+		{
+			name:       "successful request",
+			path:       pathWhoisResponseOK,
+			wantStatus: 200,
+		},
+		{
+			name:       "error message",
+			path:       pathWhoisResponseOKwError,
+			wantStatus: 200,
+			wantCode:   "WHOIS_00",
+		},
+		{
+			name:       "unparsable response",
+			path:       pathWhoisResponse500,
+			wantStatus: 500,
+			wantCode:   errorCodeParse,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, exporter, _, closeServer := newInstrumentedAPI(tt.path)
+			defer closeServer()
+
+			_, _, _ = client.Data(context.Background(), "whoisxmlapi.com", OptionDA(2))
+
+			spans := exporter.GetSpans()
+			if len(spans) != 2 {
+				t.Fatalf("spans got = %v, want 2", len(spans))
+			}
+
+			httpSpan, lookupSpan := spans[0], spans[1]
+			if lookupSpan.Name != "WhoisService.Data" || httpSpan.Name != "HTTP GET" {
+				t.Errorf("span names got = %v, %v", lookupSpan.Name, httpSpan.Name)
+			}
+			if httpSpan.Parent.SpanID() != lookupSpan.SpanContext.SpanID() {
+				t.Errorf("HTTP span is not a child of the lookup span")
+			}
+
+			attrs := attribute.NewSet(lookupSpan.Attributes...)
+			if v, _ := attrs.Value(attrDomain); v.AsString() != "whoisxmlapi.com" {
+				t.Errorf("domain got = %v", v.AsString())
+			}
+			if v, _ := attrs.Value(attrOptions); v.AsString() != "da=2&outputFormat=JSON" {
+				t.Errorf("options got = %v", v.AsString())
+			}
+			if v, _ := attrs.Value(attrStatusCode); v.AsInt64() != tt.wantStatus {
+				t.Errorf("status code got = %v, want %v", v.AsInt64(), tt.wantStatus)
+			}
+			if v, _ := attrs.Value(attrErrorCode); v.AsString() != tt.wantCode {
+				t.Errorf("error code got = %v, want %v", v.AsString(), tt.wantCode)
+			}
+			if (lookupSpan.Status.Code == codes.Error) != (tt.wantCode != "") {
+				t.Errorf("span status got = %v", lookupSpan.Status)
+			}
+
+			for _, span := range spans {
+				for _, attr := range span.Attributes {
+					if strings.Contains(attr.Value.Emit(), apiKey) {
+						t.Errorf("span %v attribute %v contains the api key", span.Name, attr.Key)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestTelemetryRedaction tests that transport errors do not leak the API key to spans
+func TestTelemetryRedaction(t *testing.T) {
+	client, exporter, _, closeServer := newInstrumentedAPI(pathWhoisResponseOK)
+	closeServer()
+
+	_, err := client.RawData(context.Background(), "whoisxmlapi.com")
+	if err == nil {
+		t.Fatal("expected transport error")
+	}
+
+	for _, span := range exporter.GetSpans() {
+		if span.Status.Code != codes.Error {
+			t.Errorf("span %v status got = %v", span.Name, span.Status)
+		}
+		if strings.Contains(span.Status.Description, apiKey) {
+			t.Errorf("span %v status contains the api key: %v", span.Name, span.Status.Description)
+		}
+	}
+}
+
+// TestTelemetryMetrics tests the request, error and size metrics
+func TestTelemetryMetrics(t *testing.T) {
+	client, _, reader, closeServer := newInstrumentedAPI(pathWhoisResponseOKwError)
+	defer closeServer()
+
+	for i := 0; i < 2; i++ {
+		_, _, _ = client.Data(context.Background(), "whoisxmlapi.com")
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, dp := range data.DataPoints {
+					key := m.Name
+					if v, ok := dp.Attributes.Value(attrErrorCode); ok {
+						key += "/" + v.AsString()
+					}
+					got[key] += dp.Value
+				}
+			case metricdata.Histogram[float64]:
+				for _, dp := range data.DataPoints {
+					got[m.Name] += int64(dp.Count)
+				}
+			}
+		}
+	}
+
+	want := map[string]int64{
+		"whoisapi.client.requests":         2,
+		"whoisapi.client.errors/WHOIS_00":  2,
+		"whoisapi.client.request.duration": 2,
+		"whoisapi.client.cache.misses":     2,
+		"whoisapi.client.cache.hits":       0,
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%v got = %v, want %v", k, got[k], v)
+		}
+	}
+	if got["whoisapi.client.bytes_read"] == 0 {
+		t.Errorf("whoisapi.client.bytes_read got = 0")
+	}
+}
+
+// TestErrorCode tests the error code classification
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{ErrorMessage{ErrorCode: "WHOIS_01"}, "WHOIS_01"},
+		{&ArgError{"name", "cannot be empty"}, errorCodeArgument},
+		{&parseError{context.Canceled}, errorCodeParse},
+		{context.Canceled, errorCodeTransport},
+		{fmt.Errorf("cannot execute request: %w", ErrCircuitOpen), errorCodeCircuit},
+	}
+	for _, tt := range tests {
+		if got := errorCode(tt.err); got != tt.want {
+			t.Errorf("errorCode(%v) got = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package whois43
+
+import (
+	"net/netip"
+	"strings"
+
+	whoisapi "github.com/whois-api-llc/whois-api-go"
+	"github.com/whois-api-llc/whois-api-go/whoisparser"
+)
+
+// NotFoundPhrases are lowercase phrases of server responses for names that are not registered.
+// Append to it before making requests to support more servers
+var NotFoundPhrases = []string{
+	"no match for",
+	"not found",
+	"no data found",
+	"no entries found",
+	"no matching record",
+	"status: free",
+	"status: available",
+	"is available for registration",
+	"object does not exist",
+}
+
+// fields are the values of the "key: value" lines of the response by lowercase key
+type fields map[string][]string
+
+// get returns the first value of the first key present
+func (f fields) get(keys ...string) string {
+	for _, k := range keys {
+		if v := f[k]; len(v) > 0 {
+			return v[0]
+		}
+	}
+	return ""
+}
+
+// parseFields collects the "key: value" lines until the footer of the response
+func parseFields(text string) fields {
+	f := make(fields)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, ">>>") {
+			break
+		}
+		if line == "" || line[0] == '%' || line[0] == '#' {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if key == "" || value == "" {
+			continue
+		}
+		f[key] = append(f[key], value)
+	}
+	return f
+}
+
+var (
+	// whoisServerKeys are the keys referring to the next WHOIS server
+	whoisServerKeys = []string{"refer", "whois", "registrar whois server", "whois server"}
+
+	// referralURLKeys are the keys with the URL of the next server
+	referralURLKeys = []string{"referralserver", "referral url", "registrar url"}
+)
+
+// referral returns the next server to query, empty if there is none
+func referral(text string) string {
+	f := parseFields(text)
+	if server := serverHost(f.get(whoisServerKeys...)); server != "" {
+		return server
+	}
+	return serverHost(f.get(referralURLKeys...))
+}
+
+// serverHost returns the host[:port] of the "whois://host:port" or "host" referral, empty for other URLs
+func serverHost(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if scheme, rest, ok := strings.Cut(s, "://"); ok {
+		if scheme != "whois" {
+			return ""
+		}
+		s = rest
+	}
+	s, _, _ = strings.Cut(s, "/")
+	if s == "" || strings.ContainsAny(s, " \t") || !strings.Contains(s, ".") {
+		return ""
+	}
+	return s
+}
+
+// isNotFound reports whether the response says the name is not registered, the footer is not checked
+func isNotFound(text string) bool {
+	text, _, _ = strings.Cut(strings.ToLower(text), ">>>")
+	for _, phrase := range NotFoundPhrases {
+		if strings.Contains(text, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// newRecord builds the Whois record from the responses. The root server response is skipped
+// if it referred to another server, the first referred server is the registry and the last is the registrar
+func newRecord(parser *whoisparser.Parser, name string, hops []hop, ignoreRawTexts bool) *whoisapi.WhoisRecord {
+	registry := hops[0]
+	if len(hops) > 1 {
+		registry = hops[1]
+	}
+	last := hops[len(hops)-1]
+
+	rec := parser.Parse(name, last.text)
+	rec.RegistryData = parser.ParseRegistryData(name, registry.text)
+
+	// the servers are referred by host, not by URL
+	f := parseFields(registry.text)
+	rec.RegistryData.WhoisServer = serverHost(f.get(whoisServerKeys...))
+	rec.RegistryData.ReferralURL = f.get(referralURLKeys...)
+
+	if rec.RegistryData.DomainName == "" && !isIP(name) {
+		rec.RegistryData.DomainName = name
+	}
+	if rec.DomainName == "" {
+		rec.DomainName = name
+	}
+	if !isIP(name) {
+		rec.DomainAvailability = "UNAVAILABLE"
+		if isNotFound(registry.text) {
+			rec.DomainAvailability = "AVAILABLE"
+		}
+	}
+
+	if ignoreRawTexts {
+		rec.RawText = ""
+		rec.NameServers.RawText = ""
+		rec.RegistryData.RawText = ""
+		rec.RegistryData.NameServers.RawText = ""
+	}
+
+	return rec
+}
+
+// isIP reports whether the name is an IP address
+func isIP(name string) bool {
+	_, err := netip.ParseAddr(name)
+	return err == nil
+}
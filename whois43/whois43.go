@@ -0,0 +1,364 @@
+// Package whois43 implements whoisapi.WhoisService by querying WHOIS servers directly over TCP port 43.
+// It can be used as a fallback when Whois API is not available
+package whois43
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	whoisapi "github.com/whois-api-llc/whois-api-go"
+	"github.com/whois-api-llc/whois-api-go/whoisparser"
+)
+
+const (
+	// DefaultRootServer is the server queried first, it refers to the registry of the TLD or the IP block
+	DefaultRootServer = "whois.iana.org"
+
+	// DefaultTimeout is the default timeout of a single server query
+	DefaultTimeout = 15 * time.Second
+
+	// DefaultMaxReferrals is the default maximum number of referrals followed from the root server
+	DefaultMaxReferrals = 3
+
+	// DefaultMaxResponseSize is the default limit of a single server response in bytes
+	DefaultMaxResponseSize = 1 << 20
+
+	// port is the WHOIS port
+	port = "43"
+)
+
+// DefaultQueryFormats are the query formats of servers that need more than the bare name
+var DefaultQueryFormats = map[string]string{
+	"whois.verisign-grs.com": "domain %s",
+	"whois.denic.de":         "-T dn,ace %s",
+	"whois.arin.net":         "n + %s",
+}
+
+// Params is used to create Client. All fields are optional
+type Params struct {
+	// RootServer is the host or host:port queried first. DefaultRootServer is used if empty
+	RootServer string
+
+	// Timeout is the timeout of a single server query. DefaultTimeout is used if zero
+	Timeout time.Duration
+
+	// ServerTimeouts override Timeout for the server hosts
+	ServerTimeouts map[string]time.Duration
+
+	// RateLimit is the minimum interval between queries to the same server, no limit if zero
+	RateLimit time.Duration
+
+	// ServerRateLimits override RateLimit for the server hosts
+	ServerRateLimits map[string]time.Duration
+
+	// MaxReferrals is the maximum number of referrals followed from the root server.
+	// DefaultMaxReferrals is used if zero, negative value disables referrals
+	MaxReferrals int
+
+	// MaxResponseSize is the limit of a single server response in bytes. DefaultMaxResponseSize is used if zero
+	MaxResponseSize int64
+
+	// QueryFormats are fmt formats of the queries by server host, e.g. "domain %s".
+	// They are added to DefaultQueryFormats, the bare name is sent to other servers
+	QueryFormats map[string]string
+
+	// Dial connects to the servers. net.Dialer is used if nil
+	Dial func(ctx context.Context, network, address string) (net.Conn, error)
+
+	// Parser parses the responses. whoisparser.Default is used if nil
+	Parser *whoisparser.Parser
+}
+
+// Client is the WhoisService querying WHOIS servers over port 43
+type Client struct {
+	params       Params
+	queryFormats map[string]string
+
+	mu   sync.Mutex
+	next map[string]time.Time
+}
+
+var _ whoisapi.WhoisService = &Client{}
+var _ whoisapi.PrefixService = &Client{}
+
+// New creates Client with specified parameters
+func New(params Params) *Client {
+	if params.RootServer == "" {
+		params.RootServer = DefaultRootServer
+	}
+	if params.Timeout <= 0 {
+		params.Timeout = DefaultTimeout
+	}
+	if params.MaxReferrals == 0 {
+		params.MaxReferrals = DefaultMaxReferrals
+	}
+	if params.MaxResponseSize <= 0 {
+		params.MaxResponseSize = DefaultMaxResponseSize
+	}
+	if params.Dial == nil {
+		params.Dial = (&net.Dialer{}).DialContext
+	}
+	if params.Parser == nil {
+		params.Parser = whoisparser.Default()
+	}
+
+	formats := make(map[string]string, len(DefaultQueryFormats)+len(params.QueryFormats))
+	for host, format := range DefaultQueryFormats {
+		formats[host] = format
+	}
+	for host, format := range params.QueryFormats {
+		formats[strings.ToLower(host)] = format
+	}
+
+	return &Client{
+		params:       params,
+		queryFormats: formats,
+		next:         make(map[string]time.Time),
+	}
+}
+
+// Data returns the Whois record parsed from the responses of the registry and the registrar servers.
+// The Response has no http.Response, its Body is the raw text of the last server
+func (c *Client) Data(
+	ctx context.Context,
+	name string,
+	opts ...whoisapi.Option,
+) (*whoisapi.WhoisRecord, *whoisapi.Response, error) {
+
+	r, resp, hops, err := c.lookup(ctx, name, opts)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return newRecord(c.params.Parser, resp.NormalizedName, hops, r.IgnoreRawTexts), resp, nil
+}
+
+// RawData returns the raw text of the last server in Response.Body
+func (c *Client) RawData(ctx context.Context, name string, opts ...whoisapi.Option) (*whoisapi.Response, error) {
+	_, resp, _, err := c.lookup(ctx, name, opts)
+	return resp, err
+}
+
+// IPData returns the Whois record of the IP address parsed from the responses of the RIRs
+func (c *Client) IPData(
+	ctx context.Context,
+	ip netip.Addr,
+	opts ...whoisapi.Option,
+) (*whoisapi.IPWhoisRecord, *whoisapi.Response, error) {
+
+	if !ip.IsValid() {
+		return nil, nil, &whoisapi.ArgError{Name: "ip", Message: "is not valid"}
+	}
+	ip = ip.Unmap().WithZone("")
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return nil, nil, &whoisapi.ArgError{Name: "ip", Message: ip.String() + " is not a public address"}
+	}
+
+	rec, resp, err := c.Data(ctx, ip.String(), opts...)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return whoisapi.NewIPWhoisRecord(ip, rec), resp, nil
+}
+
+// PrefixData returns the Whois record of the most specific netblock containing the whole CIDR prefix.
+// The first address of the prefix is looked up, whoisapi.ErrPrefixNotCovered is returned
+// if none of the netblocks in the responses contains the whole prefix
+func (c *Client) PrefixData(
+	ctx context.Context,
+	prefix netip.Prefix,
+	opts ...whoisapi.Option,
+) (*whoisapi.IPWhoisRecord, *whoisapi.Response, error) {
+
+	prefix, err := whoisapi.NormalizePrefix(prefix)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rec, resp, err := c.Data(ctx, prefix.Addr().String(), opts...)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	ipRec := whoisapi.NewPrefixWhoisRecord(prefix, rec)
+	if !ipRec.Range.ContainsPrefix(prefix) {
+		return nil, resp, fmt.Errorf("cannot look up %s: %w", prefix, whoisapi.ErrPrefixNotCovered)
+	}
+	return ipRec, resp, nil
+}
+
+// hop is the response of a single server
+type hop struct {
+	server string
+	text   string
+}
+
+// lookup queries the root server and follows referrals
+func (c *Client) lookup(
+	ctx context.Context,
+	name string,
+	opts []whoisapi.Option,
+) (whoisapi.WhoisRequest, *whoisapi.Response, []hop, error) {
+
+	r, err := whoisapi.WhoisRequestFromOptions(name, opts...)
+	if err != nil {
+		return r, nil, nil, err
+	}
+
+	normalized, err := whoisapi.NormalizeDomain(name)
+	if err != nil {
+		return r, nil, nil, err
+	}
+
+	resp := &whoisapi.Response{Name: name, NormalizedName: normalized}
+
+	// the root server and the registry are enough for thin Whois
+	maxHops := c.params.MaxReferrals + 1
+	if maxHops < 1 {
+		maxHops = 1
+	}
+	if r.ThinWhois && maxHops > 2 {
+		maxHops = 2
+	}
+
+	var hops []hop
+	visited := make(map[string]bool)
+	server := c.params.RootServer
+	for server != "" && len(hops) < maxHops && !visited[server] {
+		visited[server] = true
+
+		text, err := c.query(ctx, server, normalized)
+		if err != nil {
+			if len(hops) > 0 && ctx.Err() == nil {
+				// the referred server is down, return what we have
+				break
+			}
+			return r, resp, nil, err
+		}
+		hops = append(hops, hop{server: server, text: text})
+
+		server = referral(text)
+	}
+
+	resp.Body = []byte(hops[len(hops)-1].text)
+
+	return r, resp, hops, nil
+}
+
+// query sends the name to the server and reads the response
+func (c *Client) query(ctx context.Context, server, name string) (string, error) {
+	host, address := splitServer(server)
+
+	if err := c.wait(ctx, host); err != nil {
+		return "", err
+	}
+
+	timeout := c.params.Timeout
+	if t, ok := c.params.ServerTimeouts[host]; ok && t > 0 {
+		timeout = t
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	conn, err := c.params.Dial(ctx, "tcp", address)
+	if err != nil {
+		return "", fmt.Errorf("cannot connect to %s: %w", server, err)
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return "", fmt.Errorf("cannot set deadline: %w", err)
+	}
+
+	// close the connection if the context is cancelled before the deadline
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	q := name
+	if format, ok := c.queryFormats[host]; ok {
+		q = fmt.Sprintf(format, name)
+	}
+	if _, err := io.WriteString(conn, q+"\r\n"); err != nil {
+		return "", fmt.Errorf("cannot query %s: %w", server, contextErr(ctx, err))
+	}
+
+	b, err := io.ReadAll(io.LimitReader(conn, c.params.MaxResponseSize))
+	if err != nil {
+		return "", fmt.Errorf("cannot read response of %s: %w", server, contextErr(ctx, err))
+	}
+
+	text := string(b)
+	if !utf8.ValidString(text) {
+		text = strings.ToValidUTF8(text, "�")
+	}
+
+	return strings.ReplaceAll(text, "\r\n", "\n"), nil
+}
+
+// contextErr returns the context error instead of the connection one if the query is cancelled or timed out
+func contextErr(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		return context.DeadlineExceeded
+	}
+	return err
+}
+
+// wait blocks until the next query to the host is allowed by the rate limit
+func (c *Client) wait(ctx context.Context, host string) error {
+	interval := c.params.RateLimit
+	if l, ok := c.params.ServerRateLimits[host]; ok {
+		interval = l
+	}
+	if interval <= 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	now := time.Now()
+	at := c.next[host]
+	if at.Before(now) {
+		at = now
+	}
+	c.next[host] = at.Add(interval)
+	c.mu.Unlock()
+
+	delay := at.Sub(now)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// splitServer returns the lowercase host of the server and its address with the port
+func splitServer(server string) (host, address string) {
+	server = strings.ToLower(server)
+	if h, _, err := net.SplitHostPort(server); err == nil {
+		return h, server
+	}
+	return server, net.JoinHostPort(server, port)
+}
@@ -0,0 +1,395 @@
+package whois43
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	whoisapi "github.com/whois-api-llc/whois-api-go"
+)
+
+const ianaResponse = `% IANA WHOIS server
+% for more information on IANA, visit http://www.iana.org
+
+refer:        whois.verisign-grs.com
+
+domain:       COM
+whois:        whois.verisign-grs.com
+status:       ACTIVE
+`
+
+const verisignResponse = `   Domain Name: WHOISXMLAPI.COM
+   Registry Domain ID: 1781014932_DOMAIN_COM-VRSN
+   Registrar WHOIS Server: whois.godaddy.com
+   Registrar URL: http://www.godaddy.com
+   Updated Date: 2023-01-29T17:44:23Z
+   Creation Date: 2013-02-28T18:42:12Z
+   Registry Expiry Date: 2027-02-28T18:42:12Z
+   Registrar: GoDaddy.com, LLC
+   Registrar IANA ID: 146
+   Domain Status: clientTransferProhibited https://icann.org/epp#clientTransferProhibited
+   Domain Status: clientUpdateProhibited https://icann.org/epp#clientUpdateProhibited
+   Name Server: NS1.WHOISXMLAPI.COM
+   Name Server: NS2.WHOISXMLAPI.COM
+>>> Last update of whois database: 2026-10-18T10:00:00Z <<<
+
+The data in VeriSign's WHOIS database is not found to be inaccurate...
+`
+
+const godaddyResponse = `Domain Name: whoisxmlapi.com
+Registrar WHOIS Server: whois.godaddy.com
+Updated Date: 2023-01-29T11:44:23Z
+Creation Date: 2013-02-28T12:42:12Z
+Registrar Registration Expiration Date: 2027-02-28T12:42:12Z
+Registrar: GoDaddy.com, LLC
+Registrar IANA ID: 146
+Domain Status: clientTransferProhibited http://www.icann.org/epp#clientTransferProhibited
+Registrant Name: Registration Private
+Registrant Organization: Whois API, Inc.
+Registrant Street: 340 S Lemon Ave
+Registrant Street: #1717
+Registrant City: Walnut
+Registrant State/Province: California
+Registrant Postal Code: 91789
+Registrant Country: US
+Registrant Phone: +1.8003102051
+Registrant Email: support@whoisxmlapi.com
+Tech Email: tech@whoisxmlapi.com
+Name Server: NS1.WHOISXMLAPI.COM
+Name Server: NS2.WHOISXMLAPI.COM
+`
+
+// stub is the local WHOIS server
+type stub struct {
+	mu      sync.Mutex
+	queries []string
+	respond func(query string) string
+	delay   time.Duration
+}
+
+// start serves the stub on a random local port and returns its address
+func (s *stub) start(t *testing.T) string {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				query, err := bufio.NewReader(conn).ReadString('\n')
+				if err != nil {
+					return
+				}
+				query = strings.TrimSpace(query)
+
+				s.mu.Lock()
+				s.queries = append(s.queries, query)
+				s.mu.Unlock()
+
+				time.Sleep(s.delay)
+				_, _ = conn.Write([]byte(strings.ReplaceAll(s.respond(query), "\n", "\r\n")))
+			}()
+		}
+	}()
+
+	return l.Addr().String()
+}
+
+// received returns the queries received by the stub
+func (s *stub) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.queries...)
+}
+
+// static returns the stub responding with the text
+func static(text string) *stub {
+	return &stub{respond: func(string) string { return text }}
+}
+
+// dialStubs returns the Dial function connecting to the stubs instead of the servers
+func dialStubs(t *testing.T, stubs map[string]*stub) func(ctx context.Context, network, address string) (net.Conn, error) {
+	addresses := make(map[string]string, len(stubs))
+	for host, s := range stubs {
+		addresses[net.JoinHostPort(host, port)] = s.start(t)
+	}
+
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		a, ok := addresses[address]
+		if !ok {
+			return nil, errors.New("unknown server " + address)
+		}
+		return (&net.Dialer{}).DialContext(ctx, network, a)
+	}
+}
+
+// TestData tests following of the referrals and parsing of the responses
+func TestData(t *testing.T) {
+	iana, verisign, godaddy := static(ianaResponse), static(verisignResponse), static(godaddyResponse)
+	client := New(Params{Dial: dialStubs(t, map[string]*stub{
+		"whois.iana.org":         iana,
+		"whois.verisign-grs.com": verisign,
+		"whois.godaddy.com":      godaddy,
+	})})
+
+	rec, resp, err := client.Data(context.Background(), "WhoisXMLAPI.com.")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		s    *stub
+		want string
+	}{
+		{"iana", iana, "whoisxmlapi.com"},
+		{"verisign", verisign, "domain whoisxmlapi.com"},
+		{"godaddy", godaddy, "whoisxmlapi.com"},
+	} {
+		if got := tt.s.received(); len(got) != 1 || got[0] != tt.want {
+			t.Errorf("%s queries got = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	if resp.NormalizedName != "whoisxmlapi.com" || string(resp.Body) != godaddyResponse {
+		t.Errorf("Response got = %q, %q", resp.NormalizedName, resp.Body)
+	}
+
+	checks := []struct {
+		name      string
+		got, want string
+	}{
+		{"DomainName", rec.DomainName, "whoisxmlapi.com"},
+		{"DomainNameExt", rec.DomainNameExt, ".com"},
+		{"DomainAvailability", rec.DomainAvailability, "UNAVAILABLE"},
+		{"RegistrarName", rec.RegistrarName, "GoDaddy.com, LLC"},
+		{"Status", rec.Status, "clientTransferProhibited"},
+		{"NameServers", strings.Join(rec.NameServers.HostNames, ","), "ns1.whoisxmlapi.com,ns2.whoisxmlapi.com"},
+		{"Registrant.Organization", rec.Registrant.Organization, "Whois API, Inc."},
+		{"Registrant.Street2", rec.Registrant.Street2, "#1717"},
+		{"ContactEmail", rec.ContactEmail, "support@whoisxmlapi.com"},
+		{"RawText", rec.RawText, godaddyResponse},
+		{"RegistryData.DomainName", rec.RegistryData.DomainName, "whoisxmlapi.com"},
+		{"RegistryData.Status", rec.RegistryData.Status, "clientTransferProhibited clientUpdateProhibited"},
+		{"RegistryData.WhoisServer", rec.RegistryData.WhoisServer, "whois.godaddy.com"},
+		{"RegistryData.ReferralURL", rec.RegistryData.ReferralURL, "http://www.godaddy.com"},
+		{"RegistryData.RawText", rec.RegistryData.RawText, verisignResponse},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s got = %q, want %q", c.name, c.got, c.want)
+		}
+	}
+
+	expires, source := rec.Expires()
+	if want := time.Date(2027, 2, 28, 12, 42, 12, 0, time.UTC); !expires.Equal(want) || source != whoisapi.DateSourceNormalized {
+		t.Errorf("Expires() got = %v, %v, want %v", expires, source, want)
+	}
+}
+
+// TestDataOptions tests the options and the responses for unregistered names
+func TestDataOptions(t *testing.T) {
+	registry := &stub{respond: func(query string) string {
+		if strings.Contains(query, "whoisxmlapi.com") {
+			return verisignResponse
+		}
+		return "No match for \"" + strings.ToUpper(query) + "\".\n>>> Last update of whois database <<<\n"
+	}}
+	godaddy := static(godaddyResponse)
+	client := New(Params{Dial: dialStubs(t, map[string]*stub{
+		"whois.iana.org":         static(ianaResponse),
+		"whois.verisign-grs.com": registry,
+		"whois.godaddy.com":      godaddy,
+	})})
+
+	rec, _, err := client.Data(context.Background(), "whoisxmlapi.com",
+		whoisapi.OptionThinWhois(1), whoisapi.OptionIgnoreRawTexts(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(godaddy.received()) != 0 || rec.RegistrarName != "GoDaddy.com, LLC" || rec.RawText != "" {
+		t.Errorf("thin Whois got = %+v", rec)
+	}
+
+	rec, _, err = client.Data(context.Background(), "available-domain.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.DomainAvailability != "AVAILABLE" || rec.DomainName != "available-domain.com" {
+		t.Errorf("DomainAvailability got = %q", rec.DomainAvailability)
+	}
+
+	_, _, err = client.Data(context.Background(), "bad_name!.com")
+	var argErr *whoisapi.ArgError
+	if !errors.As(err, &argErr) {
+		t.Errorf("Data() error = %v, want ArgError", err)
+	}
+
+	_, _, err = client.Data(context.Background(), "whoisxmlapi.com", whoisapi.OptionThinWhois(2))
+	if !errors.As(err, &argErr) {
+		t.Errorf("Data() error = %v, want ArgError", err)
+	}
+}
+
+// TestTimeouts tests the per-server timeouts
+func TestTimeouts(t *testing.T) {
+	slow := static(godaddyResponse)
+	slow.delay = time.Second
+
+	client := New(Params{
+		Dial: dialStubs(t, map[string]*stub{
+			"whois.iana.org":         static(ianaResponse),
+			"whois.verisign-grs.com": static(verisignResponse),
+			"whois.godaddy.com":      slow,
+		}),
+		ServerTimeouts: map[string]time.Duration{"whois.godaddy.com": 50 * time.Millisecond},
+	})
+
+	start := time.Now()
+	rec, _, err := client.Data(context.Background(), "whoisxmlapi.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Data() took %v", elapsed)
+	}
+	if rec.RawText != verisignResponse {
+		t.Errorf("registry data is not returned when the registrar is down: %q", rec.RawText)
+	}
+
+	slowRoot := static(ianaResponse)
+	slowRoot.delay = time.Second
+	client = New(Params{
+		Dial:    dialStubs(t, map[string]*stub{"whois.iana.org": slowRoot}),
+		Timeout: 50 * time.Millisecond,
+	})
+	_, _, err = client.Data(context.Background(), "whoisxmlapi.com")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Data() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+// TestRateLimit tests the per-server rate limits
+func TestRateLimit(t *testing.T) {
+	const interval = 100 * time.Millisecond
+
+	client := New(Params{
+		Dial: dialStubs(t, map[string]*stub{
+			"whois.iana.org":         static(ianaResponse),
+			"whois.verisign-grs.com": static(verisignResponse),
+		}),
+		MaxReferrals:     1,
+		ServerRateLimits: map[string]time.Duration{"whois.iana.org": interval},
+	})
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := client.RawData(context.Background(), "whoisxmlapi.com"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 2*interval {
+		t.Errorf("3 lookups took %v, want at least %v", elapsed, 2*interval)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), interval/4)
+	defer cancel()
+	_, err := client.RawData(ctx, "whoisxmlapi.com")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("RawData() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+// TestIPData tests the lookups of IP addresses
+func TestIPData(t *testing.T) {
+	arin := static(`NetRange:       193.0.0.0 - 193.255.255.255
+CIDR:           193.0.0.0/8
+NetName:        RIPE-CBLK
+OrgName:        RIPE Network Coordination Centre
+ReferralServer: whois://whois.ripe.net
+`)
+	ripe := static(`% This is the RIPE Database query service.
+
+inetnum:        193.0.0.0 - 193.0.7.255
+netname:        RIPE-NCC
+country:        NL
+source:         RIPE
+
+route:          193.0.0.0/21
+origin:         AS3333
+source:         RIPE
+`)
+	client := New(Params{Dial: dialStubs(t, map[string]*stub{
+		"whois.iana.org": static("refer:        whois.arin.net\n\ninetnum:      193.0.0.0 - 193.255.255.255\n"),
+		"whois.arin.net": arin,
+		"whois.ripe.net": ripe,
+	})})
+
+	ip := netip.MustParseAddr("193.0.6.139")
+	rec, _, err := client.IPData(context.Background(), ip)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := arin.received(); len(got) != 1 || got[0] != "n + 193.0.6.139" {
+		t.Errorf("arin queries got = %q", got)
+	}
+	if rec.Range.String() != "193.0.0.0 - 193.0.7.255" || rec.ASN != 3333 || rec.NetworkName != "RIPE-NCC" {
+		t.Errorf("IPData() got = %v, %d, %q", rec.Range, rec.ASN, rec.NetworkName)
+	}
+	if rec.Record.DomainAvailability != "" {
+		t.Errorf("DomainAvailability got = %q, want empty", rec.Record.DomainAvailability)
+	}
+
+	// the RIPE netblock is smaller than the prefix, the ARIN one contains it
+	rec, _, err = client.PrefixData(context.Background(), netip.MustParsePrefix("193.0.0.0/16"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Range.String() != "193.0.0.0 - 193.255.255.255" || rec.NetworkName != "RIPE-CBLK" {
+		t.Errorf("PrefixData() got = %v, %q", rec.Range, rec.NetworkName)
+	}
+
+	_, _, err = client.IPData(context.Background(), netip.MustParseAddr("10.0.0.1"))
+	var argErr *whoisapi.ArgError
+	if !errors.As(err, &argErr) {
+		t.Errorf("IPData() error = %v, want ArgError", err)
+	}
+}
+
+// TestServerHost tests the serverHost function
+func TestServerHost(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"whois.godaddy.com", "whois.godaddy.com"},
+		{"WHOIS.GODADDY.COM", "whois.godaddy.com"},
+		{"whois://whois.ripe.net", "whois.ripe.net"},
+		{"whois://whois.lacnic.net:43/", "whois.lacnic.net:43"},
+		{"rwhois://rwhois.example.net:4321", ""},
+		{"http://www.godaddy.com", ""},
+		{"see the website", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := serverHost(tt.in); got != tt.want {
+			t.Errorf("serverHost(%q) got = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -4,9 +4,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
+	"net/netip"
 	"net/url"
+	"strconv"
 )
 
 // defaultWhoisApiURL is the default Whois API URL
@@ -19,6 +20,16 @@ type WhoisService interface {
 
 	// RawData returns raw Whois API response as Response struct with Body saved as a byte slice
 	RawData(ctx context.Context, name string, opts ...Option) (*Response, error)
+
+	// IPData returns the Whois record of the IP address
+	IPData(ctx context.Context, ip netip.Addr, opts ...Option) (*IPWhoisRecord, *Response, error)
+}
+
+// PrefixService is implemented by the services that look up CIDR prefixes,
+// e.g. the WhoisService of Client and the rdap and whois43 clients
+type PrefixService interface {
+	// PrefixData returns the Whois record of the most specific netblock containing the whole CIDR prefix
+	PrefixData(ctx context.Context, prefix netip.Prefix, opts ...Option) (*IPWhoisRecord, *Response, error)
 }
 
 // Response is the http.Response wrapper with Body saved as a byte slice
@@ -27,28 +38,92 @@ type Response struct {
 
 	//Body is the byte slice representation of http.Response Body
 	Body []byte
+
+	// Name is the name passed to Data or RawData
+	Name string
+
+	// NormalizedName is the name sent to the API after normalization
+	NormalizedName string
+
+	// Subdomain is the part of the name stripped by OptionRegistrableDomain
+	Subdomain string
+
+	// DecodeWarnings are the values of the response skipped by Data, the fields are left zero
+	DecodeWarnings DecodeWarnings
+
+	// SchemaDrift are the keys of the response that differ from the model, set by Data with DetectSchemaDrift
+	SchemaDrift *SchemaDrift
 }
 
 // whoisApiServiceOp is the type implementing the WhoisService interface
 type whoisApiServiceOp struct {
 	client  *Client
 	baseURL *url.URL
+
+	// requestOpts are applied to every call, they are set by Client.With
+	requestOpts []RequestOption
 }
 
 var _ WhoisService = &whoisApiServiceOp{}
+var _ PrefixService = &whoisApiServiceOp{}
+var _ PrefixService = &Client{}
 
-// newRequest creates the API request with default parameters and the specified apiKey
-func (service *whoisApiServiceOp) newRequest() (*http.Request, error) {
+// newRequest creates the API request with the query parameters and the apiKey
+// passed according to the client's AuthMode, and applies per-call overrides
+func (service *whoisApiServiceOp) newRequest(params *requestValues, apiKey string) (*http.Request, error) {
 
-	req, err := service.client.NewRequest(http.MethodGet, service.baseURL, nil)
-	if err != nil {
-		return nil, err
+	baseURL := service.baseURL
+	if params.baseURL != nil {
+		baseURL = params.baseURL
 	}
 
-	query := url.Values{}
-	query.Set("apiKey", service.client.apiKey)
+	var req *http.Request
+	var err error
+
+	query := params.Values
+	if service.client.authMode == AuthModeBody {
+		payload := make(map[string]interface{}, len(query)+1)
+		for k := range query {
+			v := query.Get(k)
+			if n, err := strconv.Atoi(v); err == nil {
+				payload[k] = n
+			} else {
+				payload[k] = v
+			}
+		}
+		payload["apiKey"] = apiKey
+
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+
+		req, err = service.client.NewRequest(http.MethodPost, baseURL, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		req, err = service.client.NewRequest(http.MethodGet, baseURL, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if service.client.authMode == AuthModeHeader {
+			req.Header.Set(authHeader, apiKey)
+			req.URL.RawQuery = query.Encode()
+		} else {
+			withKey := make(url.Values, len(query)+1)
+			for k, v := range query {
+				withKey[k] = v
+			}
+			withKey.Set("apiKey", apiKey)
+			req.URL.RawQuery = withKey.Encode()
+		}
+	}
 
-	req.URL.RawQuery = query.Encode()
+	for _, modify := range params.modifiers {
+		modify(req)
+	}
 
 	return req, nil
 }
@@ -65,19 +140,102 @@ func (service *whoisApiServiceOp) request(ctx context.Context, name string, opts
 		return nil, &ArgError{"name", "cannot be empty"}
 	}
 
-	req, err := service.newRequest()
+	normalized, err := service.client.normalize(name)
 	if err != nil {
 		return nil, err
 	}
+	if normalized == "" {
+		return nil, &ArgError{"name", "cannot be empty"}
+	}
 
-	q := req.URL.Query()
-	q.Set("domainName", name)
+	q := &requestValues{Values: url.Values{}}
+	q.Set("domainName", normalized)
 
 	for _, opt := range opts {
+		opt(q.Values)
+	}
+	for _, opt := range service.requestOpts {
 		opt(q)
 	}
 
-	req.URL.RawQuery = q.Encode()
+	var subdomain string
+	if _, ipErr := netip.ParseAddr(normalized); q.registrable && ipErr != nil {
+		suffixes := service.client.suffixes
+		if suffixes == nil {
+			suffixes = DefaultSuffixList()
+		}
+
+		subdomain, normalized, err = suffixes.SplitDomain(normalized)
+		if err != nil {
+			return nil, err
+		}
+		q.Set("domainName", normalized)
+	}
+
+	resp, err := service.share(ctx, q)
+	if resp != nil {
+		resp.Name = name
+		resp.NormalizedName = normalized
+		resp.Subdomain = subdomain
+	}
+
+	return resp, err
+}
+
+// share makes the request, concurrent identical requests share the Response of the one made first.
+// Requests with OptionRequest or OptionHeader modifiers are never shared as they cannot be compared
+func (service *whoisApiServiceOp) share(ctx context.Context, q *requestValues) (*Response, error) {
+	flights := service.client.flights
+	if flights == nil || len(q.modifiers) > 0 {
+		return service.send(ctx, q)
+	}
+
+	baseURL := service.baseURL
+	if q.baseURL != nil {
+		baseURL = q.baseURL
+	}
+	key := baseURL.String() + "\n" + q.apiKey + "\n" + q.Encode()
+
+	resp, joined, err := flights.do(ctx, key, func(ctx context.Context) (*Response, error) {
+		return service.send(ctx, q)
+	})
+	service.client.telemetry.cacheLookup(ctx, joined)
+
+	return resp, err
+}
+
+// send makes the request with the API key chosen for it
+func (service *whoisApiServiceOp) send(ctx context.Context, q *requestValues) (*Response, error) {
+	if q.apiKey != "" {
+		return service.do(ctx, q, q.apiKey)
+	}
+	if service.client.keys == nil {
+		return service.do(ctx, q, service.client.apiKey)
+	}
+
+	// every key is tried at most once if keys are exhausted or rate limited
+	pool := service.client.keys
+	for attempt := 1; ; attempt++ {
+		key, err := pool.pick()
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := service.do(ctx, q, key.Key)
+		if err != nil || !pool.check(key, resp) || attempt >= pool.size() {
+			return resp, err
+		}
+
+		service.client.telemetry.retry(ctx)
+	}
+}
+
+// do makes the request with the query parameters and the apiKey
+func (service *whoisApiServiceOp) do(ctx context.Context, params *requestValues, apiKey string) (*Response, error) {
+	req, err := service.newRequest(params, apiKey)
+	if err != nil {
+		return nil, err
+	}
 
 	var b bytes.Buffer
 	resp, err := service.client.Do(ctx, req, &b)
@@ -94,17 +252,41 @@ func (service *whoisApiServiceOp) request(ctx context.Context, name string, opts
 	}, nil
 }
 
-// parse parses raw Whois API response
-func parse(raw []byte) (*whoisApiResponse, error) {
+// parse parses raw Whois API response. Unless strict, values that cannot be decoded are skipped
+// and returned as warnings
+func parse(raw []byte, strict bool) (*whoisApiResponse, DecodeWarnings, error) {
 
 	var response whoisApiResponse
 
-	err := json.NewDecoder(bytes.NewReader(raw)).Decode(&response)
+	if strict {
+		err := json.NewDecoder(bytes.NewReader(raw)).Decode(&response)
+		if err != nil {
+			return nil, nil, &parseError{err}
+		}
+		return &response, nil, nil
+	}
+
+	warnings, err := UnmarshalLenient(raw, &response)
 	if err != nil {
-		return nil, fmt.Errorf("cannot parse response: %w", err)
+		return nil, warnings, &parseError{err}
 	}
 
-	return &response, nil
+	return &response, warnings, nil
+}
+
+// parseError is returned when the API response cannot be parsed
+type parseError struct {
+	err error
+}
+
+// Error returns error message as a string
+func (e *parseError) Error() string {
+	return "cannot parse response: " + e.err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *parseError) Unwrap() error {
+	return e.err
 }
 
 // Data returns parsed Whois record
@@ -118,16 +300,30 @@ func (service whoisApiServiceOp) Data(
 	optsJson = append(optsJson, opts...)
 	optsJson = append(optsJson, OptionOutputFormat("JSON"))
 
+	ctx, span := service.client.telemetry.startLookup(ctx, "Data", name, optsJson)
+	defer func() {
+		service.client.telemetry.endLookup(ctx, span, err)
+	}()
+
 	resp, err = service.request(ctx, name, optsJson...)
+	service.client.telemetry.setStatusCode(span, resp)
 	if err != nil {
 		return nil, resp, err
 	}
 
-	whoisResp, err := parse(resp.Body)
+	whoisResp, warnings, err := parse(resp.Body, service.client.strictDecoding)
+	resp.DecodeWarnings = warnings
 	if err != nil {
 		return nil, resp, err
 	}
 
+	if service.client.drift != nil && whoisResp.WhoisRecord != nil {
+		resp.SchemaDrift, err = service.client.drift.Detect(resp.Body, whoisResp)
+		if err != nil {
+			return nil, resp, &parseError{err}
+		}
+	}
+
 	if whoisResp.ErrorMessage != nil {
 		return nil, nil, ErrorMessage{
 			ErrorCode: whoisResp.ErrorMessage.ErrorCode,
@@ -145,7 +341,13 @@ func (service whoisApiServiceOp) RawData(
 	opts ...Option,
 ) (resp *Response, err error) {
 
+	ctx, span := service.client.telemetry.startLookup(ctx, "RawData", name, opts)
+	defer func() {
+		service.client.telemetry.endLookup(ctx, span, err)
+	}()
+
 	resp, err = service.request(ctx, name, opts...)
+	service.client.telemetry.setStatusCode(span, resp)
 	if err != nil {
 		return resp, err
 	}
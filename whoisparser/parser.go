@@ -0,0 +1,392 @@
+// Package whoisparser parses raw WHOIS text into whoisapi.WhoisRecord with templates describing
+// the formats of the registries. Built-in templates cover the ICANN format used by gTLD registries
+// and registrars and the formats of the major ccTLD registries, custom templates can be added
+package whoisparser
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/netip"
+	"strings"
+	"sync"
+
+	whoisapi "github.com/whois-api-llc/whois-api-go"
+)
+
+// Field is the record field a value is stored in, e.g. "createdDate". Contact fields are the role
+// and the contact field joined with a dot, e.g. "registrant.email". A bare role, e.g. "registrant",
+// stores the handle of the contact object to take the contact fields from, see Template.HandleKey
+type Field string
+
+// Fields of the record
+const (
+	FieldDomainName      Field = "domainName"
+	FieldCreatedDate     Field = "createdDate"
+	FieldUpdatedDate     Field = "updatedDate"
+	FieldExpiresDate     Field = "expiresDate"
+	FieldRegistrarName   Field = "registrarName"
+	FieldRegistrarIANAID Field = "registrarIANAID"
+	FieldStatus          Field = "status"
+	FieldNameServers     Field = "nameServers"
+	FieldContactEmail    Field = "contactEmail"
+
+	// FieldWhoisServer and FieldReferralURL are set in RegistryData only
+	FieldWhoisServer Field = "whoisServer"
+	FieldReferralURL Field = "referralURL"
+
+	// FieldIgnore marks the key as known without storing its value, so it's not reported as unparsable
+	FieldIgnore Field = "-"
+)
+
+// Roles of the contacts
+const (
+	RoleRegistrant     = "registrant"
+	RoleAdministrative = "administrativeContact"
+	RoleTechnical      = "technicalContact"
+	RoleBilling        = "billingContact"
+	RoleZone           = "zoneContact"
+)
+
+// roles are the contact roles in the order of the record
+var roles = []string{RoleRegistrant, RoleAdministrative, RoleTechnical, RoleBilling, RoleZone}
+
+// contactFields are the fields of the contacts. Values of "street" fill Street1 to Street4,
+// a two-letter "country" sets CountryCode too
+var contactFields = map[string]bool{
+	"name":         true,
+	"organization": true,
+	"street":       true,
+	"city":         true,
+	"state":        true,
+	"postalCode":   true,
+	"country":      true,
+	"countryCode":  true,
+	"email":        true,
+	"telephone":    true,
+	"telephoneExt": true,
+	"fax":          true,
+	"faxExt":       true,
+}
+
+// ParseCode bits of the parsed fields in the order used by the API
+const (
+	ParseCodeCreatedDate = 1 << iota
+	ParseCodeExpiresDate
+	ParseCodeReferralURL
+	ParseCodeRegistrarName
+	ParseCodeStatus
+	ParseCodeUpdatedDate
+	ParseCodeWhoisServer
+	ParseCodeNameServers
+	ParseCodeAdministrativeContact
+	ParseCodeBillingContact
+	ParseCodeRegistrant
+	ParseCodeTechnicalContact
+	ParseCodeZoneContact
+)
+
+// Template describes the format of the responses of a WHOIS server.
+//
+// The keys of "Key: value" and "[Key] value" lines are lowercase with inner whitespace collapsed
+// and trailing dots removed. Lines of a section, i.e. the indented lines after a "Section:" or "Section"
+// line and the lines after a "[Section]" line until a blank line, have the keys prefixed with
+// the section and "/", e.g. "relevant dates/registered on". Lines of a section without a key
+// have the key of the section, indented lines after a "Key: value" line continue its value
+type Template struct {
+	// Name identifies the template, e.g. "nominet"
+	Name string `json:"name"`
+
+	// TLDs are the extensions the template is used for without the leading dot, e.g. "uk" or "co.uk".
+	// The template without TLDs is used for the names not covered by other templates
+	TLDs []string `json:"tlds,omitempty"`
+
+	// Fields map the keys to the record fields
+	Fields map[string]Field `json:"fields"`
+
+	// HandleKey is the key identifying the contact objects referenced by handle, e.g. "nic-hdl".
+	// The objects are separated by blank lines
+	HandleKey string `json:"handleKey,omitempty"`
+
+	// HandleFields map the keys of the contact objects to the contact fields, e.g. "e-mail": "email"
+	HandleFields map[string]string `json:"handleFields,omitempty"`
+
+	// prefixes are the sections and the first words of the contact keys by role,
+	// unknown keys with them are unparsable parts of the contact
+	prefixes map[string]string
+}
+
+// normalized returns the copy of the template with normalized keys, or an error for unknown fields
+func (t *Template) normalized() (*Template, error) {
+	n := &Template{
+		Name:         t.Name,
+		Fields:       make(map[string]Field, len(t.Fields)),
+		HandleKey:    normalizeKey(t.HandleKey),
+		HandleFields: make(map[string]string, len(t.HandleFields)),
+		prefixes:     make(map[string]string),
+	}
+	for _, tld := range t.TLDs {
+		n.TLDs = append(n.TLDs, strings.ToLower(strings.Trim(tld, ".")))
+	}
+
+	for key, f := range t.Fields {
+		if !isValidField(f) {
+			return nil, fmt.Errorf("template %s: unknown field %q of %q", t.Name, f, key)
+		}
+		if isRole(string(f)) && n.HandleKey == "" {
+			return nil, fmt.Errorf("template %s: contact %q is referenced by handle without handleKey", t.Name, f)
+		}
+		n.Fields[normalizeKey(key)] = f
+	}
+
+	ambiguous := make(map[string]bool)
+	for key, f := range n.Fields {
+		role, _, ok := strings.Cut(string(f), ".")
+		if !ok || !isRole(role) {
+			continue
+		}
+		prefix := keyPrefix(key)
+		if r, ok := n.prefixes[prefix]; ok && r != role {
+			ambiguous[prefix] = true
+		}
+		n.prefixes[prefix] = role
+	}
+	for prefix := range ambiguous {
+		delete(n.prefixes, prefix)
+	}
+	for key, f := range t.HandleFields {
+		if f != string(FieldIgnore) && !contactFields[f] {
+			return nil, fmt.Errorf("template %s: unknown contact field %q of %q", t.Name, f, key)
+		}
+		n.HandleFields[normalizeKey(key)] = f
+	}
+
+	return n, nil
+}
+
+// keyPrefix returns the section of the key, or its first word
+func keyPrefix(key string) string {
+	if section, _, ok := strings.Cut(key, "/"); ok {
+		return section
+	}
+	word, _, _ := strings.Cut(key, " ")
+	return word
+}
+
+// isValidField reports whether the field is known
+func isValidField(f Field) bool {
+	switch f {
+	case FieldDomainName, FieldCreatedDate, FieldUpdatedDate, FieldExpiresDate, FieldRegistrarName,
+		FieldRegistrarIANAID, FieldStatus, FieldNameServers, FieldContactEmail, FieldWhoisServer,
+		FieldReferralURL, FieldIgnore:
+		return true
+	}
+	role, field, ok := strings.Cut(string(f), ".")
+	if !ok {
+		return isRole(role)
+	}
+	return isRole(role) && contactFields[field]
+}
+
+// isRole reports whether the string is one of the contact roles
+func isRole(s string) bool {
+	for _, role := range roles {
+		if s == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Parser parses raw WHOIS text with templates
+type Parser struct {
+	byTLD    map[string]*Template
+	fallback *Template
+}
+
+// New creates Parser with the built-in templates and the custom ones.
+// Custom templates replace the built-in ones for their TLDs
+func New(templates ...*Template) (*Parser, error) {
+	p := &Parser{byTLD: make(map[string]*Template)}
+	for _, t := range append(Templates(), templates...) {
+		n, err := t.normalized()
+		if err != nil {
+			return nil, err
+		}
+		if len(n.TLDs) == 0 {
+			p.fallback = n
+		}
+		for _, tld := range n.TLDs {
+			p.byTLD[tld] = n
+		}
+	}
+	return p, nil
+}
+
+// defaultParser is the parser with the built-in templates
+var defaultParser = sync.OnceValue(func() *Parser {
+	p, err := New()
+	if err != nil {
+		panic(err)
+	}
+	return p
+})
+
+// Default returns Parser with the built-in templates
+func Default() *Parser {
+	return defaultParser()
+}
+
+// LoadTemplates reads the JSON array of templates
+func LoadTemplates(r io.Reader) ([]*Template, error) {
+	var templates []*Template
+	if err := json.NewDecoder(r).Decode(&templates); err != nil {
+		return nil, fmt.Errorf("cannot read templates: %w", err)
+	}
+	return templates, nil
+}
+
+// Template returns the template used for the domain name, the longest matching TLD wins
+func (p *Parser) Template(name string) *Template {
+	name = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(name), "."))
+	if ascii, err := whoisapi.NormalizeDomain(name); err == nil {
+		name = ascii
+	}
+	for {
+		if t, ok := p.byTLD[name]; ok {
+			return t
+		}
+		i := strings.IndexByte(name, '.')
+		if i < 0 {
+			return p.fallback
+		}
+		name = name[i+1:]
+	}
+}
+
+// Result is the parsed raw text
+type Result struct {
+	// Record is the parsed record
+	Record *whoisapi.WhoisRecord
+
+	// WhoisServer is the referred WHOIS server
+	WhoisServer string
+
+	// ReferralURL is the referral URL
+	ReferralURL string
+
+	// Unparsable are the lines between the first and the last parsed ones that are not parsed and
+	// don't belong to a contact. Unparsable lines of the contacts are in their Unparsable fields
+	Unparsable string
+
+	// Template is the name of the template used
+	Template string
+}
+
+// ParseResult parses the raw text of the domain name's WHOIS record
+func (p *Parser) ParseResult(name, text string) *Result {
+	return p.parse(name, text)
+}
+
+// Parse parses the raw text of the domain name's WHOIS record
+func (p *Parser) Parse(name, text string) *whoisapi.WhoisRecord {
+	return p.parse(name, text).Record
+}
+
+// ParseRegistryData parses the raw text of the registry's response
+func (p *Parser) ParseRegistryData(name, text string) whoisapi.RegistryData {
+	res := p.parse(name, text)
+	d := res.Record.AsRegistryData()
+	d.WhoisServer = res.WhoisServer
+	d.ReferralURL = res.ReferralURL
+	return d
+}
+
+// Populate replaces the parsed fields of the record and its RegistryData with the ones parsed from their
+// raw texts. Records without raw text are left as is, other fields like Audit are kept
+func (p *Parser) Populate(rec *whoisapi.WhoisRecord) {
+	if rec.RawText != "" {
+		copyParsed(rec, p.Parse(rec.DomainName, rec.RawText))
+	}
+
+	if rec.RegistryData.RawText != "" {
+		name := rec.RegistryData.DomainName
+		if name == "" {
+			name = rec.DomainName
+		}
+		parsed := p.ParseRegistryData(name, rec.RegistryData.RawText)
+
+		r := rec.RegistryData.AsWhoisRecord()
+		parsedRec := parsed.AsWhoisRecord()
+		copyParsed(&r, &parsedRec)
+
+		d := r.AsRegistryData()
+		d.WhoisServer, d.ReferralURL = parsed.WhoisServer, parsed.ReferralURL
+		d.Extra = rec.RegistryData.Extra
+		rec.RegistryData = d
+	}
+}
+
+// copyParsed copies the fields set by the parser
+func copyParsed(dst, src *whoisapi.WhoisRecord) {
+	dst.DomainName = src.DomainName
+	dst.CreatedDate, dst.CreatedDateNormalized = src.CreatedDate, src.CreatedDateNormalized
+	dst.UpdatedDate, dst.UpdatedDateNormalized = src.UpdatedDate, src.UpdatedDateNormalized
+	dst.ExpiresDate, dst.ExpiresDateNormalized = src.ExpiresDate, src.ExpiresDateNormalized
+	dst.NameServers = src.NameServers
+	dst.RegistrarName, dst.RegistrarIANAID = src.RegistrarName, src.RegistrarIANAID
+	dst.Status = src.Status
+	dst.Registrant = src.Registrant
+	dst.AdministrativeContact = src.AdministrativeContact
+	dst.TechnicalContact = src.TechnicalContact
+	dst.BillingContact = src.BillingContact
+	dst.ZoneContact = src.ZoneContact
+	dst.Header, dst.Footer, dst.StrippedText = src.Header, src.Footer, src.StrippedText
+	dst.ParseCode = src.ParseCode
+	if src.ContactEmail != "" {
+		dst.ContactEmail = src.ContactEmail
+	}
+	if src.DomainNameExt != "" {
+		dst.DomainNameExt = src.DomainNameExt
+	}
+}
+
+// Parse parses the raw text of the domain name's WHOIS record with the built-in templates
+func Parse(name, text string) *whoisapi.WhoisRecord {
+	return Default().Parse(name, text)
+}
+
+// ComputeParseCode returns the ParseCode bits of the fields of the registry data that are set.
+// Records are passed with AsRegistryData and the whois server and referral URL of the Result
+func ComputeParseCode(d *whoisapi.RegistryData) int {
+	code := 0
+	for _, part := range []struct {
+		bit int
+		set bool
+	}{
+		{ParseCodeCreatedDate, d.CreatedDate != ""},
+		{ParseCodeExpiresDate, d.ExpiresDate != ""},
+		{ParseCodeReferralURL, d.ReferralURL != ""},
+		{ParseCodeRegistrarName, d.RegistrarName != ""},
+		{ParseCodeStatus, d.Status != ""},
+		{ParseCodeUpdatedDate, d.UpdatedDate != ""},
+		{ParseCodeWhoisServer, d.WhoisServer != ""},
+		{ParseCodeNameServers, len(d.NameServers.HostNames) > 0},
+		{ParseCodeAdministrativeContact, !d.AdministrativeContact.IsEmpty()},
+		{ParseCodeBillingContact, !d.BillingContact.IsEmpty()},
+		{ParseCodeRegistrant, !d.Registrant.IsEmpty()},
+		{ParseCodeTechnicalContact, !d.TechnicalContact.IsEmpty()},
+		{ParseCodeZoneContact, !d.ZoneContact.IsEmpty()},
+	} {
+		if part.set {
+			code |= part.bit
+		}
+	}
+	return code
+}
+
+// isIP reports whether the name is an IP address
+func isIP(name string) bool {
+	_, err := netip.ParseAddr(name)
+	return err == nil
+}
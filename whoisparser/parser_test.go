@@ -0,0 +1,595 @@
+package whoisparser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	whoisapi "github.com/whois-api-llc/whois-api-go"
+)
+
+const icannText = `Domain Name: whoisxmlapi.com
+Registry Domain ID: 1781014932_DOMAIN_COM-VRSN
+Registrar WHOIS Server: whois.godaddy.com
+Registrar URL: https://www.godaddy.com
+Updated Date: 2023-01-29T11:44:23Z
+Creation Date: 2013-02-28T12:42:12Z
+Registrar Registration Expiration Date: 2027-02-28T12:42:12Z
+Registrar: GoDaddy.com, LLC
+Registrar IANA ID: 146
+Registrar Abuse Contact Email: abuse@godaddy.com
+Registrar Abuse Contact Phone: +1.4806242505
+Domain Status: clientTransferProhibited https://icann.org/epp#clientTransferProhibited
+Domain Status: clientUpdateProhibited https://icann.org/epp#clientUpdateProhibited
+Registrant Name: Registration Private
+Registrant Organization: Whois API, Inc.
+Registrant Street: 340 S Lemon Ave
+Registrant Street: #1717
+Registrant City: Walnut
+Registrant State/Province: California
+Registrant Postal Code: 91789
+Registrant Country: US
+Registrant Phone: +1.8003102051
+Registrant Phone Ext: 12
+Registrant Email: support@whoisxmlapi.com
+Registrant Language: en
+Admin Name: Registration Private
+Admin Email: admin@whoisxmlapi.com
+Tech Email: tech@whoisxmlapi.com
+Data collected under a special agreement
+Name Server: NS1.WHOISXMLAPI.COM
+Name Server: NS2.WHOISXMLAPI.COM
+DNSSEC: unsigned
+URL of the ICANN WHOIS Data Problem Reporting System: http://wdprs.internic.net/
+>>> Last update of WHOIS database: 2026-10-18T10:00:00Z <<<
+
+For more information on Whois status codes, please visit https://icann.org/epp
+`
+
+const nominetText = `
+    Domain name:
+        nominet.uk
+
+    Data validation:
+        Nominet was able to match the registrant's name and address against a 3rd party data source on 10-Dec-2012
+
+    Registrar:
+        Nominet UK [Tag = NOMINET]
+        URL: https://www.nominet.uk
+
+    Relevant dates:
+        Registered on: 10-Dec-2012
+        Expiry date:  10-Dec-2030
+        Last updated:  09-Nov-2028
+
+    Registration status:
+        Registered until expiry date.
+
+    Name servers:
+        dns1.nic.uk               213.248.216.1  2a01:618:400::1
+        dns4.nic.uk
+
+    WHOIS lookup made at 10:00:00 18-Oct-2026
+
+--
+This WHOIS information is provided for free by Nominet UK the central registry
+for .uk domain names.
+`
+
+const afnicText = `%%
+%% This is the AFNIC Whois server.
+%%
+
+domain:                        afnic.fr
+status:                        ACTIVE
+hold:                          NO
+holder-c:                      A1967-FRNIC
+admin-c:                       NFC1-FRNIC
+tech-c:                        GR283-FRNIC
+registrar:                     AFNIC
+Expiry Date:                   2026-12-31T23:00:00Z
+created:                       1995-01-01T00:00:00Z
+last-update:                   2023-12-12T18:17:36.812058Z
+source:                        FRNIC
+
+nserver:                       ns1.nic.fr
+nserver:                       ns2.nic.fr
+source:                        FRNIC
+
+nic-hdl:                       A1967-FRNIC
+type:                          ORGANIZATION
+contact:                       AFNIC
+address:                       immeuble le Stephenson
+address:                       1, rue Stephenson
+address:                       78180 Montigny-Le-Bretonneux
+country:                       FR
+phone:                         +33.139308300
+e-mail:                        hostmaster@nic.fr
+registrar:                     AFNIC
+birth-place:                   Paris
+source:                        FRNIC
+
+nic-hdl:                       NFC1-FRNIC
+type:                          ORGANIZATION
+contact:                       NIC France Contact
+e-mail:                        hostmaster@nic.fr
+source:                        FRNIC
+`
+
+const jprsText = `[ JPRS database provides information on network administration. ]
+
+Domain Information:
+[Domain Name]                   JPRS.JP
+
+[Registrant]                    Japan Registry Services Co.,Ltd.
+
+[Name Server]                   ns1.jprs.co.jp
+[Name Server]                   ns2.jprs.co.jp
+[Signing Key]
+
+[Created on]                    2001/02/02
+[Expires on]                    2027/02/28
+[Status]                        Active
+[Last Updated]                  2026/03/01 01:05:04 (JST)
+
+[Contact Information]
+[Name]                          Japan Registry Services Co.,Ltd.
+[Email]                         hostmaster@jprs.co.jp
+[Postal code]                   101-0065
+[Postal Address]                Chiyoda-ku
+                                Chiyoda First Bldg. East 13F
+[Phone]                         03-5215-8451
+`
+
+const nicITText = `Domain:             nic.it
+Status:             ok
+Signed:             yes
+Created:            1996-01-29 00:00:00
+Last Update:        2026-02-15 00:53:33
+Expire Date:        2027-01-29
+
+Registrant
+  Organization:     Consiglio Nazionale delle Ricerche
+  Address:          Via Moruzzi, 1
+                    Pisa
+                    56124
+                    PI
+                    IT
+  Created:          2007-03-01 10:28:08
+
+Registrar
+  Organization:     Registro .it
+  Name:             SYSTEM-REG
+  Web:              http://www.nic.it
+
+Nameservers
+  dns.nic.it
+  m.dns.it
+`
+
+// TestParse tests the built-in templates
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		domain   string
+		text     string
+		template string
+		check    func(t *testing.T, res *Result)
+	}{
+		{
+			name:     "icann",
+			domain:   "whoisxmlapi.com",
+			text:     icannText,
+			template: "icann",
+			check: func(t *testing.T, res *Result) {
+				rec := res.Record
+				want := time.Date(2027, 2, 28, 12, 42, 12, 0, time.UTC)
+				if !time.Time(rec.ExpiresDateNormalized).Equal(want) {
+					t.Errorf("ExpiresDateNormalized got = %v", time.Time(rec.ExpiresDateNormalized))
+				}
+				if rec.Status != "clientTransferProhibited clientUpdateProhibited" {
+					t.Errorf("Status got = %q", rec.Status)
+				}
+				wantRegistrant := whoisapi.Contact{
+					Name:         "Registration Private",
+					Organization: "Whois API, Inc.",
+					Street1:      "340 S Lemon Ave",
+					Street2:      "#1717",
+					City:         "Walnut",
+					State:        "California",
+					PostalCode:   "91789",
+					Country:      "US",
+					CountryCode:  "US",
+					Email:        "support@whoisxmlapi.com",
+					Telephone:    "+1.8003102051",
+					TelephoneExt: "12",
+					RawText:      strings.Join(strings.Split(icannText, "\n")[13:24], "\n") + "\n",
+					Unparsable:   "Registrant Language: en\n",
+				}
+				if !reflect.DeepEqual(rec.Registrant, wantRegistrant) {
+					t.Errorf("Registrant got = %+v, want %+v", rec.Registrant, wantRegistrant)
+				}
+				if rec.TechnicalContact.Email != "tech@whoisxmlapi.com" || rec.ContactEmail != "abuse@godaddy.com" {
+					t.Errorf("emails got = %q, %q", rec.TechnicalContact.Email, rec.ContactEmail)
+				}
+				if res.WhoisServer != "whois.godaddy.com" || res.ReferralURL != "https://www.godaddy.com" {
+					t.Errorf("referrals got = %q, %q", res.WhoisServer, res.ReferralURL)
+				}
+				if res.Unparsable != "Data collected under a special agreement\n" {
+					t.Errorf("Unparsable got = %q", res.Unparsable)
+				}
+				if rec.Header != "" || !strings.HasPrefix(rec.Footer, "URL of the ICANN WHOIS Data Problem") {
+					t.Errorf("Header, Footer got = %q, %q", rec.Header, rec.Footer)
+				}
+				wantCode := ParseCodeCreatedDate | ParseCodeExpiresDate | ParseCodeReferralURL | ParseCodeRegistrarName |
+					ParseCodeStatus | ParseCodeUpdatedDate | ParseCodeWhoisServer | ParseCodeNameServers |
+					ParseCodeAdministrativeContact | ParseCodeRegistrant | ParseCodeTechnicalContact
+				if rec.ParseCode != wantCode || wantCode != 3583 {
+					t.Errorf("ParseCode got = %d, want %d", rec.ParseCode, wantCode)
+				}
+			},
+		},
+		{
+			name:     "nominet",
+			domain:   "nominet.uk",
+			text:     nominetText,
+			template: "nominet",
+			check: func(t *testing.T, res *Result) {
+				rec := res.Record
+				if rec.DomainName != "nominet.uk" || rec.CreatedDate != "10-Dec-2012" || rec.UpdatedDate != "09-Nov-2028" {
+					t.Errorf("record got = %q, %q, %q", rec.DomainName, rec.CreatedDate, rec.UpdatedDate)
+				}
+				if rec.RegistrarName != "Nominet UK [Tag = NOMINET]" || res.ReferralURL != "https://www.nominet.uk" {
+					t.Errorf("registrar got = %q, %q", rec.RegistrarName, res.ReferralURL)
+				}
+				if rec.Status != "Registered until expiry date." {
+					t.Errorf("Status got = %q", rec.Status)
+				}
+				if !reflect.DeepEqual(rec.NameServers.HostNames, []string{"dns1.nic.uk", "dns4.nic.uk"}) ||
+					!reflect.DeepEqual(rec.NameServers.Ips, []string{"213.248.216.1", "2a01:618:400::1"}) {
+					t.Errorf("NameServers got = %+v", rec.NameServers)
+				}
+				if res.Unparsable != "" || !strings.HasPrefix(rec.Footer, "WHOIS lookup made at") {
+					t.Errorf("Unparsable, Footer got = %q, %q", res.Unparsable, rec.Footer)
+				}
+			},
+		},
+		{
+			name:     "afnic",
+			domain:   "afnic.fr",
+			text:     afnicText,
+			template: "afnic",
+			check: func(t *testing.T, res *Result) {
+				rec := res.Record
+				wantRegistrant := whoisapi.Contact{
+					Name:        "AFNIC",
+					Street1:     "immeuble le Stephenson",
+					Street2:     "1, rue Stephenson",
+					Street3:     "78180 Montigny-Le-Bretonneux",
+					CountryCode: "FR",
+					Email:       "hostmaster@nic.fr",
+					Telephone:   "+33.139308300",
+					RawText: "contact:                       AFNIC\n" +
+						"address:                       immeuble le Stephenson\n" +
+						"address:                       1, rue Stephenson\n" +
+						"address:                       78180 Montigny-Le-Bretonneux\n" +
+						"country:                       FR\n" +
+						"phone:                         +33.139308300\n" +
+						"e-mail:                        hostmaster@nic.fr\n",
+					Unparsable: "birth-place:                   Paris\n",
+				}
+				if !reflect.DeepEqual(rec.Registrant, wantRegistrant) {
+					t.Errorf("Registrant got = %+v, want %+v", rec.Registrant, wantRegistrant)
+				}
+				if rec.AdministrativeContact.Name != "NIC France Contact" || !rec.TechnicalContact.IsEmpty() {
+					t.Errorf("contacts got = %+v, %+v", rec.AdministrativeContact, rec.TechnicalContact)
+				}
+				if rec.RegistrarName != "AFNIC" || rec.Status != "ACTIVE" || len(rec.NameServers.HostNames) != 2 {
+					t.Errorf("record got = %q, %q, %v", rec.RegistrarName, rec.Status, rec.NameServers.HostNames)
+				}
+				if rec.Header != "%%\n%% This is the AFNIC Whois server.\n%%" || res.Unparsable != "" {
+					t.Errorf("Header, Unparsable got = %q, %q", rec.Header, res.Unparsable)
+				}
+			},
+		},
+		{
+			name:     "jprs",
+			domain:   "jprs.jp",
+			text:     jprsText,
+			template: "jprs",
+			check: func(t *testing.T, res *Result) {
+				rec := res.Record
+				if rec.DomainName != "jprs.jp" || rec.Registrant.Organization != "Japan Registry Services Co.,Ltd." {
+					t.Errorf("record got = %q, %+v", rec.DomainName, rec.Registrant)
+				}
+				want := time.Date(2026, 3, 1, 1, 5, 4, 0, time.UTC)
+				if !time.Time(rec.UpdatedDateNormalized).Equal(want) || rec.CreatedDate != "2001/02/02" {
+					t.Errorf("dates got = %v, %q", time.Time(rec.UpdatedDateNormalized), rec.CreatedDate)
+				}
+				admin := rec.AdministrativeContact
+				if admin.Street1 != "Chiyoda-ku" || admin.Street2 != "Chiyoda First Bldg. East 13F" || admin.Telephone != "03-5215-8451" {
+					t.Errorf("AdministrativeContact got = %+v", admin)
+				}
+				if rec.Header != "[ JPRS database provides information on network administration. ]\n\nDomain Information:" {
+					t.Errorf("Header got = %q", rec.Header)
+				}
+			},
+		},
+		{
+			name:     "nic.it",
+			domain:   "nic.it",
+			text:     nicITText,
+			template: "nic.it",
+			check: func(t *testing.T, res *Result) {
+				rec := res.Record
+				r := rec.Registrant
+				if r.Organization != "Consiglio Nazionale delle Ricerche" || r.Street1 != "Via Moruzzi, 1" ||
+					r.Street2 != "Pisa" || r.Street4 != "PI, IT" {
+					t.Errorf("Registrant got = %+v", r)
+				}
+				if rec.RegistrarName != "Registro .it" || res.ReferralURL != "http://www.nic.it" {
+					t.Errorf("registrar got = %q, %q", rec.RegistrarName, res.ReferralURL)
+				}
+				if !reflect.DeepEqual(rec.NameServers.HostNames, []string{"dns.nic.it", "m.dns.it"}) {
+					t.Errorf("NameServers got = %v", rec.NameServers.HostNames)
+				}
+				if rec.ExpiresDate != "2027-01-29" || rec.Status != "ok" || res.Unparsable != "" {
+					t.Errorf("record got = %q, %q, %q", rec.ExpiresDate, rec.Status, res.Unparsable)
+				}
+			},
+		},
+		{
+			name:     "unparsable",
+			domain:   "example.com",
+			text:     "The queried object does not exist\n>>> Last update <<<\n",
+			template: "icann",
+			check: func(t *testing.T, res *Result) {
+				rec := res.Record
+				if rec.ParseCode != 0 || res.Unparsable != "The queried object does not exist" {
+					t.Errorf("ParseCode, Unparsable got = %d, %q", rec.ParseCode, res.Unparsable)
+				}
+				if rec.DomainName != "example.com" || rec.DomainNameExt != ".com" || rec.Footer != ">>> Last update <<<" {
+					t.Errorf("record got = %q, %q, %q", rec.DomainName, rec.DomainNameExt, rec.Footer)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := Default().ParseResult(tt.domain, tt.text)
+			if res.Template != tt.template {
+				t.Errorf("Template got = %q, want %q", res.Template, tt.template)
+			}
+			if res.Record.RawText != tt.text {
+				t.Error("RawText is not the text")
+			}
+			tt.check(t, res)
+		})
+	}
+}
+
+// TestCustomTemplates tests loading and validating custom templates
+func TestCustomTemplates(t *testing.T) {
+	templates, err := LoadTemplates(strings.NewReader(`[{
+		"name": "example",
+		"tlds": [".Example", "co.uk"],
+		"fields": {
+			"Domain.....": "domainName",
+			"Holder": "registrant.organization",
+			"Owner Handle": "registrant",
+			"Paid Until": "expiresDate"
+		},
+		"handleKey": "Handle",
+		"handleFields": {"E-Mail": "email", "Notes": "-"}
+	}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := New(templates...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]string{
+		"whois.example":   "example",
+		"example.co.uk":   "example",
+		"example.org.uk":  "nominet",
+		"example.net":     "icann",
+		"example.com.br":  "registro.br",
+		"пример.рф":       "tcinet",
+		"":                "icann",
+		"EXAMPLE.EXAMPLE": "example",
+	} {
+		if got := p.Template(name).Name; got != want {
+			t.Errorf("Template(%q) got = %q, want %q", name, got, want)
+		}
+	}
+
+	rec := p.Parse("whois.example", "Domain.....: WHOIS.EXAMPLE\nHolder: Whois API\nOwner Handle: WA-1\n"+
+		"Paid Until: 2027-01-01\n\nHandle: wa-1\nE-Mail: admin@whois.example\nNotes: none\n")
+	if rec.DomainName != "whois.example" || rec.Registrant.Organization != "Whois API" ||
+		rec.Registrant.Email != "admin@whois.example" || rec.ExpiresDate != "2027-01-01" {
+		t.Errorf("Parse() got = %+v", rec)
+	}
+	if rec.Registrant.Unparsable != "" {
+		t.Errorf("Registrant.Unparsable got = %q", rec.Registrant.Unparsable)
+	}
+
+	invalid := []*Template{
+		{Name: "unknown field", Fields: map[string]Field{"domain": "domain"}},
+		{Name: "unknown contact field", Fields: map[string]Field{"owner": "registrant.phone"}},
+		{Name: "handle without key", Fields: map[string]Field{"owner": RoleRegistrant}},
+		{Name: "unknown handle field", HandleKey: "nic-hdl", HandleFields: map[string]string{"phone": "phone"}},
+	}
+	for _, tmpl := range invalid {
+		if _, err := New(tmpl); err == nil {
+			t.Errorf("New(%s) error = nil", tmpl.Name)
+		}
+	}
+}
+
+// TestPopulate tests filling the record from its raw texts
+func TestPopulate(t *testing.T) {
+	rec := &whoisapi.WhoisRecord{}
+	rec.DomainName = "whoisxmlapi.com"
+	rec.RawText = icannText
+	rec.EstimatedDomainAge = 4766
+	rec.ParseCode = 3322
+	rec.RegistryData.ParseCode = 251
+	rec.RegistryData.RawText = "Domain Name: WHOISXMLAPI.COM\nRegistrar WHOIS Server: whois.godaddy.com\n" +
+		"Creation Date: 2013-02-28T18:42:12Z\nRegistrar: GoDaddy.com, LLC\n"
+
+	Default().Populate(rec)
+
+	if rec.RegistrarIANAID != "146" || rec.Registrant.Email != "support@whoisxmlapi.com" || rec.EstimatedDomainAge != 4766 ||
+		rec.ParseCode != 3583 {
+		t.Errorf("record got = %+v", rec)
+	}
+	if rec.RegistryData.WhoisServer != "whois.godaddy.com" || rec.RegistryData.CreatedDate != "2013-02-28T18:42:12Z" ||
+		rec.RegistryData.RawText == "" ||
+		rec.RegistryData.ParseCode != ParseCodeCreatedDate|ParseCodeRegistrarName|ParseCodeWhoisServer {
+		t.Errorf("RegistryData got = %+v", rec.RegistryData)
+	}
+
+	empty := &whoisapi.WhoisRecord{}
+	empty.DomainName = "whoisxmlapi.com"
+	Default().Populate(empty)
+	if empty.ParseCode != 0 || empty.DomainNameExt != "" {
+		t.Errorf("record without raw text got = %+v", empty)
+	}
+}
+
+// TestComputeParseCode tests the bits of the fields in the order used by the API
+func TestComputeParseCode(t *testing.T) {
+	tests := []struct {
+		name string
+		set  func(d *whoisapi.RegistryData)
+		want int
+	}{
+		{"empty", func(d *whoisapi.RegistryData) {}, 0},
+		{"createdDate", func(d *whoisapi.RegistryData) { d.CreatedDate = "2013-02-28" }, 1},
+		{"expiresDate", func(d *whoisapi.RegistryData) { d.ExpiresDate = "2027-02-28" }, 2},
+		{"referralURL", func(d *whoisapi.RegistryData) { d.ReferralURL = "https://www.godaddy.com" }, 4},
+		{"registrarName", func(d *whoisapi.RegistryData) { d.RegistrarName = "GoDaddy.com, LLC" }, 8},
+		{"status", func(d *whoisapi.RegistryData) { d.Status = "ok" }, 16},
+		{"updatedDate", func(d *whoisapi.RegistryData) { d.UpdatedDate = "2026-02-28" }, 32},
+		{"whoisServer", func(d *whoisapi.RegistryData) { d.WhoisServer = "whois.godaddy.com" }, 64},
+		{"nameServers", func(d *whoisapi.RegistryData) { d.NameServers.HostNames = []string{"ns1.example.com"} }, 128},
+		{"administrativeContact", func(d *whoisapi.RegistryData) { d.AdministrativeContact.Name = "Jane Doe" }, 256},
+		{"billingContact", func(d *whoisapi.RegistryData) { d.BillingContact.Name = "Jane Doe" }, 512},
+		{"registrant", func(d *whoisapi.RegistryData) { d.Registrant.Name = "Jane Doe" }, 1024},
+		{"technicalContact", func(d *whoisapi.RegistryData) { d.TechnicalContact.Name = "Jane Doe" }, 2048},
+		{"zoneContact", func(d *whoisapi.RegistryData) { d.ZoneContact.Name = "Jane Doe" }, 4096},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d whoisapi.RegistryData
+			tt.set(&d)
+			if got := ComputeParseCode(&d); got != tt.want {
+				t.Errorf("ComputeParseCode() got = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestTemplates tests the other built-in templates with short responses
+func TestTemplates(t *testing.T) {
+	tests := []struct {
+		domain string
+		text   string
+		want   func(rec *whoisapi.WhoisRecord) []string
+		values []string
+	}{
+		{
+			domain: "denic.de",
+			text: "Domain: denic.de\nNserver: ns1.denic.de\nNserver: ns2.denic.net\nStatus: connect\n" +
+				"Changed: 2018-03-12T21:44:25+01:00\n\n[Tech-C]\nType: ROLE\nName: Business Services\n" +
+				"Organisation: DENIC eG\nEmail: info@denic.de\n",
+			want: func(rec *whoisapi.WhoisRecord) []string {
+				return []string{rec.DomainName, rec.UpdatedDate, rec.Status, rec.TechnicalContact.Organization,
+					rec.TechnicalContact.Email, strings.Join(rec.NameServers.HostNames, ",")}
+			},
+			values: []string{"denic.de", "2018-03-12T21:44:25+01:00", "connect", "DENIC eG", "info@denic.de",
+				"ns1.denic.de,ns2.denic.net"},
+		},
+		{
+			domain: "sidn.nl",
+			text: "Domain name: sidn.nl\nStatus:      active\n\nRegistrar:\n   SIDN B.V.\n   Meander 501\n\n" +
+				"Domain nameservers:\n   ns1.sidn.nl\n   ns2.sidn.nl\n\nCreation Date: 2000-05-31\n",
+			want: func(rec *whoisapi.WhoisRecord) []string {
+				return []string{rec.DomainName, rec.RegistrarName, rec.CreatedDate, strings.Join(rec.NameServers.HostNames, ",")}
+			},
+			values: []string{"sidn.nl", "SIDN B.V.", "2000-05-31", "ns1.sidn.nl,ns2.sidn.nl"},
+		},
+		{
+			domain: "registro.br",
+			text: "domain:      registro.br\nowner:       NIC.BR\nowner-c:     FAN\nnserver:     a.dns.br\n" +
+				"created:     19990221 #15318\nexpires:     20270221\nstatus:      published\n\n" +
+				"nic-hdl-br:  FAN\nperson:      Frederico Neves\ne-mail:      fneves@registro.br\n",
+			want: func(rec *whoisapi.WhoisRecord) []string {
+				return []string{rec.Registrant.Organization, rec.Registrant.Name, rec.Registrant.Email,
+					time.Time(rec.CreatedDateNormalized).Format(time.DateOnly), rec.ExpiresDate}
+			},
+			values: []string{"NIC.BR", "Frederico Neves", "fneves@registro.br", "1999-02-21", "20270221"},
+		},
+		{
+			domain: "nic.ru",
+			text: "domain:        NIC.RU\nnserver:       ns4-cloud.nic.ru.\nstate:         REGISTERED, DELEGATED\n" +
+				"org:           JSC 'RU-CENTER'\nregistrar:     RU-CENTER-RU\ncreated:       1997-11-28T12:36:15Z\n" +
+				"paid-till:     2027-12-01T21:00:00Z\nsource:        TCI\n",
+			want: func(rec *whoisapi.WhoisRecord) []string {
+				return []string{rec.DomainName, rec.Status, rec.Registrant.Organization, rec.RegistrarName,
+					rec.ExpiresDate, strings.Join(rec.NameServers.HostNames, ",")}
+			},
+			values: []string{"nic.ru", "REGISTERED, DELEGATED", "JSC 'RU-CENTER'", "RU-CENTER-RU",
+				"2027-12-01T21:00:00Z", "ns4-cloud.nic.ru"},
+		},
+		{
+			domain: "cnnic.cn",
+			text: "Domain Name: cnnic.cn\nROID: 20030310s10001s00012906-cn\nDomain Status: serverDeleteProhibited\n" +
+				"Registrant: 中国互联网络信息中心\nRegistrant Contact Email: servicei@cnnic.cn\n" +
+				"Sponsoring Registrar: 北京新网数码信息技术有限公司\nName Server: a.cnnic.cn\n" +
+				"Registration Time: 2003-03-10 19:05:56\nExpiration Time: 2027-03-10 19:05:56\n",
+			want: func(rec *whoisapi.WhoisRecord) []string {
+				return []string{rec.Registrant.Organization, rec.ContactEmail, rec.RegistrarName, rec.CreatedDate}
+			},
+			values: []string{"中国互联网络信息中心", "servicei@cnnic.cn", "北京新网数码信息技术有限公司", "2003-03-10 19:05:56"},
+		},
+		{
+			domain: "eurid.eu",
+			text: "Domain: eurid.eu\nScript: LATIN\n\nRegistrant:\n        NOT DISCLOSED!\n\nTechnical:\n" +
+				"        Organisation: EURid vzw\n        Email: tech@eurid.eu\n\nRegistrar:\n        Name: EURid vzw\n" +
+				"        Website: https://www.eurid.eu\n\nName servers:\n        ns1.eurid.eu (185.36.4.253)\n" +
+				"        ns1.eurid.eu (2001:67c:9c:3937::253)\n",
+			want: func(rec *whoisapi.WhoisRecord) []string {
+				return []string{rec.Registrant.Name, rec.TechnicalContact.Organization, rec.RegistrarName,
+					strings.Join(rec.NameServers.HostNames, ","), strings.Join(rec.NameServers.Ips, ",")}
+			},
+			values: []string{"NOT DISCLOSED!", "EURid vzw", "EURid vzw", "ns1.eurid.eu", "185.36.4.253,2001:67c:9c:3937::253"},
+		},
+		{
+			domain: "auda.org.au",
+			text: "Domain Name: auda.org.au\nLast Modified: 2026-01-01T00:00:00Z\nRegistrar Name: Example Registrar\n" +
+				"Status: serverRenewProhibited https://identitydigital.au/get-au/whois-status-codes#serverRenewProhibited\n" +
+				"Registrant Contact Name: Domain Administrator\nRegistrant: .au Domain Administration Ltd\n" +
+				"Tech Contact Name: Domain Administrator\nName Server: ns1.auda.org.au\n",
+			want: func(rec *whoisapi.WhoisRecord) []string {
+				return []string{rec.Status, rec.Registrant.Name, rec.Registrant.Organization, rec.TechnicalContact.Name}
+			},
+			values: []string{"serverRenewProhibited", "Domain Administrator", ".au Domain Administration Ltd", "Domain Administrator"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			res := Default().ParseResult(tt.domain, tt.text)
+			if got := tt.want(res.Record); !reflect.DeepEqual(got, tt.values) {
+				t.Errorf("Parse() got = %q, want %q", got, tt.values)
+			}
+			if res.Unparsable != "" {
+				t.Errorf("Unparsable got = %q", res.Unparsable)
+			}
+		})
+	}
+}
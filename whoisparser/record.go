@@ -0,0 +1,334 @@
+package whoisparser
+
+import (
+	"net/netip"
+	"sort"
+	"strings"
+
+	whoisapi "github.com/whois-api-llc/whois-api-go"
+)
+
+// parse parses the text with the template of the name
+func (p *Parser) parse(name, text string) *Result {
+	t := p.Template(name)
+	s := scanText(text)
+
+	values := make(map[Field][]string)
+	contactLines := make(map[string][]int)
+	matched := make(map[int]bool)
+
+	// owners are the roles of the unparsable lines of the contacts
+	owners := make(map[int]string)
+
+	// the contact objects by lowercase handle
+	handles := make(map[string]int)
+	objects := make(map[int]bool)
+	if t.HandleKey != "" {
+		for _, e := range s.entries {
+			if e.key == t.HandleKey {
+				handles[strings.ToLower(e.value)] = e.block
+				objects[e.block] = true
+			}
+		}
+	}
+
+	refs := make(map[string]string)
+	for _, e := range s.entries {
+		if objects[e.block] {
+			if _, ok := t.HandleFields[e.key]; ok || e.key == t.HandleKey {
+				matched[e.line] = true
+			}
+			continue
+		}
+
+		f, ok := t.Fields[e.key]
+		if !ok {
+			if role, ok := t.prefixes[keyPrefix(e.key)]; ok {
+				owners[e.line] = role
+			}
+			continue
+		}
+		matched[e.line] = true
+		switch {
+		case f == FieldIgnore || e.value == "":
+		case isRole(string(f)):
+			if _, ok := refs[string(f)]; !ok {
+				refs[string(f)] = strings.ToLower(e.value)
+			}
+		default:
+			values[f] = append(values[f], e.value)
+			if role, _, ok := strings.Cut(string(f), "."); ok {
+				contactLines[role] = append(contactLines[role], e.line)
+			}
+		}
+	}
+
+	for _, role := range roles {
+		block, ok := handles[refs[role]]
+		if !ok {
+			continue
+		}
+		for _, e := range s.entries {
+			if e.block != block {
+				continue
+			}
+			f, ok := t.HandleFields[e.key]
+			if !ok && e.key != t.HandleKey {
+				owners[e.line] = role
+			}
+			if ok && f != string(FieldIgnore) && e.value != "" {
+				field := Field(role + "." + f)
+				values[field] = append(values[field], e.value)
+				contactLines[role] = append(contactLines[role], e.line)
+			}
+		}
+	}
+
+	rec := &whoisapi.WhoisRecord{}
+	rec.RawText = text
+	setFields(rec, values)
+
+	for _, role := range roles {
+		c := contact(rec, role)
+		c.RawText = s.join(contactLines[role])
+	}
+
+	if rec.DomainName == "" && !isIP(name) {
+		rec.DomainName = strings.ToLower(name)
+	}
+	if !isIP(name) {
+		if i := strings.LastIndexByte(name, '.'); i >= 0 {
+			rec.DomainNameExt = strings.ToLower(name[i:])
+		}
+	}
+	if rec.ContactEmail == "" {
+		for _, c := range []whoisapi.Contact{rec.Registrant, rec.AdministrativeContact, rec.TechnicalContact} {
+			if c.Email != "" {
+				rec.ContactEmail = c.Email
+				break
+			}
+		}
+	}
+
+	res := &Result{
+		Record:      rec,
+		WhoisServer: firstValue(values[FieldWhoisServer]),
+		ReferralURL: firstValue(values[FieldReferralURL]),
+		Template:    t.Name,
+	}
+	s.split(res, matched, owners)
+	d := rec.AsRegistryData()
+	d.WhoisServer, d.ReferralURL = res.WhoisServer, res.ReferralURL
+	rec.ParseCode = ComputeParseCode(&d)
+
+	return res
+}
+
+// setFields sets the record fields from the values
+func setFields(rec *whoisapi.WhoisRecord, values map[Field][]string) {
+	for f, vs := range values {
+		v := vs[0]
+		switch f {
+		case FieldDomainName:
+			rec.DomainName = strings.ToLower(strings.TrimSuffix(v, "."))
+		case FieldCreatedDate:
+			rec.CreatedDate, rec.CreatedDateNormalized = v, normalizeDate(v)
+		case FieldUpdatedDate:
+			rec.UpdatedDate, rec.UpdatedDateNormalized = v, normalizeDate(v)
+		case FieldExpiresDate:
+			rec.ExpiresDate, rec.ExpiresDateNormalized = v, normalizeDate(v)
+		case FieldRegistrarName:
+			rec.RegistrarName = v
+		case FieldRegistrarIANAID:
+			rec.RegistrarIANAID = v
+		case FieldContactEmail:
+			rec.ContactEmail = v
+		case FieldStatus:
+			rec.Status = status(vs)
+		case FieldNameServers:
+			rec.NameServers = nameServers(vs)
+		default:
+			role, field, ok := strings.Cut(string(f), ".")
+			if ok {
+				setContactField(contact(rec, role), field, vs)
+			}
+		}
+	}
+}
+
+// contact returns the contact of the role
+func contact(rec *whoisapi.WhoisRecord, role string) *whoisapi.Contact {
+	switch role {
+	case RoleRegistrant:
+		return &rec.Registrant
+	case RoleAdministrative:
+		return &rec.AdministrativeContact
+	case RoleTechnical:
+		return &rec.TechnicalContact
+	case RoleBilling:
+		return &rec.BillingContact
+	default:
+		return &rec.ZoneContact
+	}
+}
+
+// setContactField sets the contact field from the values
+func setContactField(c *whoisapi.Contact, field string, vs []string) {
+	v := vs[0]
+	switch field {
+	case "name":
+		c.Name = v
+	case "organization":
+		c.Organization = v
+	case "street":
+		streets := []*string{&c.Street1, &c.Street2, &c.Street3, &c.Street4}
+		for i, s := range vs {
+			if i == len(streets)-1 {
+				*streets[i] = strings.Join(vs[i:], ", ")
+				break
+			}
+			*streets[i] = s
+		}
+	case "city":
+		c.City = v
+	case "state":
+		c.State = v
+	case "postalCode":
+		c.PostalCode = v
+	case "country":
+		c.Country = v
+		if len(v) == 2 && c.CountryCode == "" {
+			c.CountryCode = strings.ToUpper(v)
+		}
+	case "countryCode":
+		c.CountryCode = strings.ToUpper(v)
+	case "email":
+		c.Email = v
+	case "telephone":
+		c.Telephone = v
+	case "telephoneExt":
+		c.TelephoneExt = v
+	case "fax":
+		c.Fax = v
+	case "faxExt":
+		c.FaxExt = v
+	}
+}
+
+// status joins the status values, the "https://icann.org/epp#..." links are dropped
+func status(vs []string) string {
+	var result []string
+	for _, v := range vs {
+		var words []string
+		for _, w := range strings.Fields(v) {
+			if !strings.Contains(w, "://") && !strings.HasPrefix(w, "(") {
+				words = append(words, w)
+			}
+		}
+		if len(words) > 0 {
+			result = append(result, strings.Join(words, " "))
+		}
+	}
+	return strings.Join(result, " ")
+}
+
+// nameServers parses the "host [address...]" values
+func nameServers(vs []string) whoisapi.NameServers {
+	var ns whoisapi.NameServers
+	seen := make(map[string]bool)
+	for _, v := range vs {
+		ns.RawText += v + "\n"
+
+		words := strings.FieldsFunc(v, func(r rune) bool {
+			return r == ' ' || r == '\t' || r == ',' || r == '(' || r == ')'
+		})
+		if len(words) == 0 {
+			continue
+		}
+		host := strings.ToLower(strings.TrimSuffix(words[0], "."))
+		if !seen[host] {
+			seen[host] = true
+			ns.HostNames = append(ns.HostNames, host)
+		}
+		for _, w := range words[1:] {
+			if ip, err := netip.ParseAddr(w); err == nil {
+				ns.Ips = append(ns.Ips, ip.String())
+			}
+		}
+	}
+	return ns
+}
+
+// normalizeDate parses the raw date, empty Time if it's not parsable
+func normalizeDate(s string) whoisapi.Time {
+	// registro.br appends the ticket number, e.g. "19990221 #15318"
+	s, _, _ = strings.Cut(s, " #")
+	t, err := whoisapi.ParseDate(s)
+	if err != nil {
+		return whoisapi.Time{}
+	}
+	return whoisapi.Time(t.UTC())
+}
+
+// split sets Header, Footer, StrippedText and Unparsable parts of the result from the matched lines.
+// The lines between the first and the last matched ones that are not matched are unparsable,
+// the ones with an owner are unparsable parts of its contact
+func (s *scan) split(res *Result, matched map[int]bool, owners map[int]string) {
+	rec := res.Record
+	if len(matched) == 0 {
+		res.Unparsable = strings.TrimSpace(strings.Join(s.lines[:s.footer], "\n"))
+		rec.Footer = strings.TrimSpace(strings.Join(s.lines[s.footer:], "\n"))
+		return
+	}
+
+	lines := make([]int, 0, len(matched))
+	for i := range matched {
+		lines = append(lines, i)
+	}
+	sort.Ints(lines)
+	first, last := lines[0], lines[len(lines)-1]
+
+	rec.Header = strings.TrimSpace(strings.Join(s.lines[:first], "\n"))
+	rec.Footer = strings.TrimSpace(strings.Join(s.lines[last+1:], "\n"))
+	rec.StrippedText = s.join(lines)
+
+	var unparsable []int
+	contactUnparsable := make(map[string][]int)
+	for i := first + 1; i < last; i++ {
+		if matched[i] || s.structural[i] || strings.TrimSpace(s.lines[i]) == "" {
+			continue
+		}
+		if role, ok := owners[i]; ok {
+			contactUnparsable[role] = append(contactUnparsable[role], i)
+			continue
+		}
+		unparsable = append(unparsable, i)
+	}
+	res.Unparsable = s.join(unparsable)
+	for role, lines := range contactUnparsable {
+		contact(rec, role).Unparsable = s.join(lines)
+	}
+}
+
+// join returns the trimmed lines in the order of the text joined with new lines
+func (s *scan) join(lines []int) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	lines = append([]int(nil), lines...)
+	sort.Ints(lines)
+
+	trimmed := make([]string, 0, len(lines))
+	for _, i := range lines {
+		trimmed = append(trimmed, strings.TrimSpace(s.lines[i]))
+	}
+	return strings.Join(trimmed, "\n") + "\n"
+}
+
+// firstValue returns the first value, empty if there is none
+func firstValue(vs []string) string {
+	if len(vs) == 0 {
+		return ""
+	}
+	return vs[0]
+}
@@ -0,0 +1,186 @@
+package whoisparser
+
+import (
+	"net/netip"
+	"strings"
+)
+
+// maxKeyLength is the maximum length of a key, longer text before a colon is a sentence
+const maxKeyLength = 48
+
+// entry is the value of a line
+type entry struct {
+	// line is the index of the line
+	line int
+
+	// key is the normalized key, prefixed with the section and "/" for the lines of a section
+	key string
+
+	// value is the trimmed value
+	value string
+
+	// block is the index of the block of lines separated by blank lines
+	block int
+}
+
+// scan is the raw text split into entries
+type scan struct {
+	// lines are the lines of the text without trailing whitespace
+	lines []string
+
+	// entries are the values in the order of the lines
+	entries []entry
+
+	// structural are the section headers and the comments, they are neither parsed nor unparsable
+	structural map[int]bool
+
+	// footer is the index of the ">>> Last update of WHOIS database" line, len(lines) if there is none
+	footer int
+}
+
+// section is the section the lines belong to
+type section struct {
+	name    string
+	indent  int
+	bracket bool
+}
+
+// scanText splits the raw text into entries
+func scanText(text string) *scan {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	s := &scan{
+		lines:      strings.Split(strings.ReplaceAll(text, "\r", "\n"), "\n"),
+		structural: make(map[int]bool),
+	}
+	s.footer = len(s.lines)
+	for i := range s.lines {
+		s.lines[i] = strings.TrimRight(s.lines[i], " \t")
+	}
+
+	var (
+		sec        section
+		lastKey    string
+		lastIndent int
+		block      int
+	)
+	add := func(i int, key, value string) {
+		s.entries = append(s.entries, entry{line: i, key: key, value: value, block: block})
+	}
+	prefixed := func(key string) string {
+		if sec.name == "" {
+			return key
+		}
+		return sec.name + "/" + key
+	}
+
+	for i, line := range s.lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			sec, lastKey = section{}, ""
+			block++
+			continue
+		}
+		if strings.HasPrefix(trimmed, ">>>") {
+			s.footer = i
+			break
+		}
+		if trimmed[0] == '%' || trimmed[0] == '#' {
+			s.structural[i] = true
+			continue
+		}
+
+		indent := indentOf(line)
+		if sec.name != "" && !sec.bracket && indent <= sec.indent {
+			sec, lastKey = section{}, ""
+		}
+
+		// "[Key] value" and "[Section]"
+		if trimmed[0] == '[' {
+			if j := strings.IndexByte(trimmed, ']'); j > 1 {
+				key := normalizeKey(trimmed[1:j])
+				value := strings.TrimSpace(trimmed[j+1:])
+				if value == "" {
+					sec = section{name: key, indent: indent, bracket: true}
+					lastKey = ""
+					s.structural[i] = true
+					continue
+				}
+				lastKey, lastIndent = prefixed(key), indent
+				add(i, lastKey, value)
+				continue
+			}
+		}
+
+		if key, value, ok := splitKey(trimmed); ok {
+			if value == "" && sec.name == "" && s.opensSection(i, indent) {
+				sec = section{name: key, indent: indent}
+				lastKey = ""
+				s.structural[i] = true
+				continue
+			}
+			lastKey, lastIndent = prefixed(key), indent
+			add(i, lastKey, value)
+			continue
+		}
+
+		switch {
+		case lastKey != "" && indent > lastIndent:
+			// the continuation of the previous value
+			add(i, lastKey, trimmed)
+		case sec.name != "":
+			add(i, sec.name, trimmed)
+		case s.opensSection(i, indent):
+			sec = section{name: normalizeKey(trimmed), indent: indent}
+			lastKey = ""
+			s.structural[i] = true
+		}
+	}
+
+	return s
+}
+
+// opensSection reports whether the next line is indented deeper than the line
+func (s *scan) opensSection(i, indent int) bool {
+	if i+1 >= len(s.lines) {
+		return false
+	}
+	next := s.lines[i+1]
+	return strings.TrimSpace(next) != "" && indentOf(next) > indent
+}
+
+// indentOf returns the number of leading spaces and tabs
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+// splitKey splits the "Key: value" line, URLs, IPv6 addresses and sentences are not split
+func splitKey(line string) (key, value string, ok bool) {
+	key, value, ok = strings.Cut(line, ":")
+	if !ok || strings.HasPrefix(value, "//") || len(key) > maxKeyLength || inAddress(line, len(key)) {
+		return "", "", false
+	}
+	key = normalizeKey(key)
+	if key == "" {
+		return "", "", false
+	}
+	return key, strings.TrimSpace(value), true
+}
+
+// normalizeKey lowercases the key, collapses inner whitespace and removes trailing dots
+func normalizeKey(key string) string {
+	key = strings.Join(strings.Fields(strings.ToLower(key)), " ")
+	return strings.TrimRight(key, ". ")
+}
+
+// inAddress reports whether the byte at i is inside an IP address, e.g. "ns1.nic.uk 2a01:618:400::1"
+func inAddress(line string, i int) bool {
+	start := strings.LastIndexAny(line[:i], " \t([,") + 1
+	end := strings.IndexAny(line[i:], " \t)],")
+	if end < 0 {
+		end = len(line)
+	} else {
+		end += i
+	}
+	_, err := netip.ParseAddr(line[start:end])
+	return err == nil
+}
@@ -0,0 +1,60 @@
+package whoisparser
+
+import (
+	"testing"
+)
+
+// TestSplitKey tests the splitKey function
+func TestSplitKey(t *testing.T) {
+	tests := []struct {
+		line      string
+		key       string
+		value     string
+		wantSplit bool
+	}{
+		{"Domain Name: WHOISXMLAPI.COM", "domain name", "WHOISXMLAPI.COM", true},
+		{"Registrant  Street :  340 S Lemon Ave", "registrant street", "340 S Lemon Ave", true},
+		{"Domain Name.........: example.be", "domain name", "example.be", true},
+		{"Updated Date: 2023-01-29T17:44:23Z", "updated date", "2023-01-29T17:44:23Z", true},
+		{"Name servers:", "name servers", "", true},
+		{"https://www.godaddy.com", "", "", false},
+		{"dns1.nic.uk   213.248.216.1  2a01:618:400::1", "", "", false},
+		{"ns1.eurid.eu (2001:67c:9c:3937::253)", "", "", false},
+		{"NOTICE AND TERMS OF USE: You are not authorized to access or query our WHOIS database", "notice and terms of use",
+			"You are not authorized to access or query our WHOIS database", true},
+		{"By submitting a WHOIS query, you agree to abide by the following terms of use: ...", "", "", false},
+		{": no key", "", "", false},
+	}
+	for _, tt := range tests {
+		key, value, ok := splitKey(tt.line)
+		if key != tt.key || value != tt.value || ok != tt.wantSplit {
+			t.Errorf("splitKey(%q) got = %q, %q, %v, want %q, %q, %v", tt.line, key, value, ok, tt.key, tt.value, tt.wantSplit)
+		}
+	}
+}
+
+// TestScanText tests the sections and the continuation lines
+func TestScanText(t *testing.T) {
+	s := scanText("Domain: example.it\r\n\r\nRegistrant\r\n  Address:  Via Moruzzi, 1\r\n" +
+		"            Pisa\r\n  Name:     Example\r\n\r\n[Tech-C]\r\nName: Tech\r\nplain\r\n% comment\r\n>>> footer <<<\r\nDomain: ignored\r\n")
+
+	want := []entry{
+		{line: 0, key: "domain", value: "example.it", block: 0},
+		{line: 3, key: "registrant/address", value: "Via Moruzzi, 1", block: 1},
+		{line: 4, key: "registrant/address", value: "Pisa", block: 1},
+		{line: 5, key: "registrant/name", value: "Example", block: 1},
+		{line: 8, key: "tech-c/name", value: "Tech", block: 2},
+		{line: 9, key: "tech-c", value: "plain", block: 2},
+	}
+	if len(s.entries) != len(want) {
+		t.Fatalf("entries got = %+v, want %+v", s.entries, want)
+	}
+	for i := range want {
+		if s.entries[i] != want[i] {
+			t.Errorf("entry %d got = %+v, want %+v", i, s.entries[i], want[i])
+		}
+	}
+	if !s.structural[2] || !s.structural[7] || !s.structural[10] || s.footer != 11 {
+		t.Errorf("structural, footer got = %v, %d", s.structural, s.footer)
+	}
+}
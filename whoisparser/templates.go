@@ -0,0 +1,432 @@
+package whoisparser
+
+// icannContacts are the ICANN key prefixes of the contacts by role
+var icannContacts = map[string]string{
+	"registrant": RoleRegistrant,
+	"admin":      RoleAdministrative,
+	"tech":       RoleTechnical,
+	"billing":    RoleBilling,
+}
+
+// icannContactFields are the ICANN key suffixes of the contact fields
+var icannContactFields = map[string]string{
+	"name":           "name",
+	"organization":   "organization",
+	"street":         "street",
+	"city":           "city",
+	"state/province": "state",
+	"postal code":    "postalCode",
+	"country":        "country",
+	"phone":          "telephone",
+	"phone ext":      "telephoneExt",
+	"fax":            "fax",
+	"fax ext":        "faxExt",
+	"email":          "email",
+}
+
+// icann is the template of the ICANN registration data format used by gTLD registries and registrars,
+// and by many ccTLDs. It's used for the names without a specific template
+func icann() *Template {
+	t := &Template{
+		Name: "icann",
+		Fields: map[string]Field{
+			"domain name":                            FieldDomainName,
+			"domain":                                 FieldDomainName,
+			"registry domain id":                     FieldIgnore,
+			"registrar whois server":                 FieldWhoisServer,
+			"whois server":                           FieldWhoisServer,
+			"registrar url":                          FieldReferralURL,
+			"referral url":                           FieldReferralURL,
+			"updated date":                           FieldUpdatedDate,
+			"last updated":                           FieldUpdatedDate,
+			"last modified":                          FieldUpdatedDate,
+			"creation date":                          FieldCreatedDate,
+			"created date":                           FieldCreatedDate,
+			"created":                                FieldCreatedDate,
+			"created on":                             FieldCreatedDate,
+			"registered on":                          FieldCreatedDate,
+			"registry expiry date":                   FieldExpiresDate,
+			"registrar registration expiration date": FieldExpiresDate,
+			"expiration date":                        FieldExpiresDate,
+			"expiry date":                            FieldExpiresDate,
+			"expires on":                             FieldExpiresDate,
+			"registrar":                              FieldRegistrarName,
+			"sponsoring registrar":                   FieldRegistrarName,
+			"registrar name":                         FieldRegistrarName,
+			"registrar iana id":                      FieldRegistrarIANAID,
+			"registrar abuse contact email":          FieldContactEmail,
+			"registrar abuse contact phone":          FieldIgnore,
+			"reseller":                               FieldIgnore,
+			"domain status":                          FieldStatus,
+			"status":                                 FieldStatus,
+			"name server":                            FieldNameServers,
+			"nameserver":                             FieldNameServers,
+			"nserver":                                FieldNameServers,
+			"dnssec":                                 FieldIgnore,
+			"registry registrant id":                 FieldIgnore,
+			"registry admin id":                      FieldIgnore,
+			"registry tech id":                       FieldIgnore,
+			"registry billing id":                    FieldIgnore,
+			"url of the icann whois inaccuracy complaint form": FieldIgnore,
+		},
+	}
+	for prefix, role := range icannContacts {
+		for suffix, field := range icannContactFields {
+			t.Fields[prefix+" "+suffix] = Field(role + "." + field)
+		}
+	}
+	return t
+}
+
+// nominet is the template of .uk
+func nominet() *Template {
+	return &Template{
+		Name: "nominet",
+		TLDs: []string{"uk", "co.uk", "org.uk", "me.uk", "ltd.uk", "plc.uk", "net.uk", "sch.uk"},
+		Fields: map[string]Field{
+			"domain name":                  FieldDomainName,
+			"data validation":              FieldIgnore,
+			"registrant":                   "registrant.name",
+			"registrant type":              FieldIgnore,
+			"registrant's address":         "registrant.street",
+			"registrar":                    FieldRegistrarName,
+			"registrar/url":                FieldReferralURL,
+			"relevant dates/registered on": FieldCreatedDate,
+			"relevant dates/expiry date":   FieldExpiresDate,
+			"relevant dates/last updated":  FieldUpdatedDate,
+			"registration status":          FieldStatus,
+			"name servers":                 FieldNameServers,
+			"dnssec":                       FieldIgnore,
+		},
+	}
+}
+
+// denic is the template of .de
+func denic() *Template {
+	t := &Template{
+		Name: "denic",
+		TLDs: []string{"de"},
+		Fields: map[string]Field{
+			"domain":  FieldDomainName,
+			"nserver": FieldNameServers,
+			"dnskey":  FieldIgnore,
+			"status":  FieldStatus,
+			"changed": FieldUpdatedDate,
+		},
+	}
+	for section, role := range map[string]string{
+		"holder":  RoleRegistrant,
+		"admin-c": RoleAdministrative,
+		"tech-c":  RoleTechnical,
+		"zone-c":  RoleZone,
+	} {
+		for key, field := range map[string]Field{
+			"type":         FieldIgnore,
+			"name":         Field(role + ".name"),
+			"organisation": Field(role + ".organization"),
+			"address":      Field(role + ".street"),
+			"postalcode":   Field(role + ".postalCode"),
+			"city":         Field(role + ".city"),
+			"countrycode":  Field(role + ".countryCode"),
+			"phone":        Field(role + ".telephone"),
+			"fax":          Field(role + ".fax"),
+			"email":        Field(role + ".email"),
+			"changed":      FieldIgnore,
+		} {
+			t.Fields[section+"/"+key] = field
+		}
+	}
+	return t
+}
+
+// sidn is the template of .nl
+func sidn() *Template {
+	return &Template{
+		Name: "sidn",
+		TLDs: []string{"nl"},
+		Fields: map[string]Field{
+			"domain name":          FieldDomainName,
+			"status":               FieldStatus,
+			"registrar":            FieldRegistrarName,
+			"reseller":             FieldIgnore,
+			"abuse contact":        FieldIgnore,
+			"dnssec":               FieldIgnore,
+			"domain nameservers":   FieldNameServers,
+			"creation date":        FieldCreatedDate,
+			"updated date":         FieldUpdatedDate,
+			"record maintained by": FieldIgnore,
+		},
+	}
+}
+
+// afnic is the template of .fr and the overseas TLDs run by AFNIC
+func afnic() *Template {
+	return &Template{
+		Name: "afnic",
+		TLDs: []string{"fr", "re", "pm", "tf", "wf", "yt"},
+		Fields: map[string]Field{
+			"domain":      FieldDomainName,
+			"status":      FieldStatus,
+			"eppstatus":   FieldIgnore,
+			"hold":        FieldIgnore,
+			"holder-c":    RoleRegistrant,
+			"admin-c":     RoleAdministrative,
+			"tech-c":      RoleTechnical,
+			"registrar":   FieldRegistrarName,
+			"expiry date": FieldExpiresDate,
+			"created":     FieldCreatedDate,
+			"last-update": FieldUpdatedDate,
+			"nserver":     FieldNameServers,
+			"source":      FieldIgnore,
+			"type":        FieldIgnore,
+			"address":     FieldIgnore,
+			"country":     FieldIgnore,
+			"phone":       FieldIgnore,
+			"fax-no":      FieldIgnore,
+			"e-mail":      FieldIgnore,
+			"website":     FieldIgnore,
+			"anonymous":   FieldIgnore,
+			"registered":  FieldIgnore,
+			"key1-tag":    FieldIgnore,
+			"key1-algo":   FieldIgnore,
+			"key1-dgst-t": FieldIgnore,
+			"key1-dgst":   FieldIgnore,
+		},
+		HandleKey: "nic-hdl",
+		HandleFields: map[string]string{
+			"type":      "-",
+			"contact":   "name",
+			"address":   "street",
+			"country":   "countryCode",
+			"phone":     "telephone",
+			"fax-no":    "fax",
+			"e-mail":    "email",
+			"registrar": "-",
+			"changed":   "-",
+			"anonymous": "-",
+			"obsoleted": "-",
+			"eppid":     "-",
+			"source":    "-",
+		},
+	}
+}
+
+// registroBR is the template of .br
+func registroBR() *Template {
+	return &Template{
+		Name: "registro.br",
+		TLDs: []string{"br", "com.br", "net.br", "org.br"},
+		Fields: map[string]Field{
+			"domain":      FieldDomainName,
+			"owner":       "registrant.organization",
+			"ownerid":     FieldIgnore,
+			"responsible": FieldIgnore,
+			"country":     "registrant.countryCode",
+			"owner-c":     RoleRegistrant,
+			"admin-c":     RoleAdministrative,
+			"tech-c":      RoleTechnical,
+			"billing-c":   RoleBilling,
+			"nserver":     FieldNameServers,
+			"nsstat":      FieldIgnore,
+			"nslastaa":    FieldIgnore,
+			"saci":        FieldIgnore,
+			"dsrecord":    FieldIgnore,
+			"dsstatus":    FieldIgnore,
+			"dslastok":    FieldIgnore,
+			"created":     FieldCreatedDate,
+			"changed":     FieldUpdatedDate,
+			"expires":     FieldExpiresDate,
+			"status":      FieldStatus,
+			"provider":    FieldIgnore,
+		},
+		HandleKey: "nic-hdl-br",
+		HandleFields: map[string]string{
+			"person":   "name",
+			"e-mail":   "email",
+			"country":  "countryCode",
+			"created":  "-",
+			"changed":  "-",
+			"provider": "-",
+		},
+	}
+}
+
+// tcinet is the template of .ru, .su and .рф
+func tcinet() *Template {
+	return &Template{
+		Name: "tcinet",
+		TLDs: []string{"ru", "su", "xn--p1ai"},
+		Fields: map[string]Field{
+			"domain":        FieldDomainName,
+			"nserver":       FieldNameServers,
+			"state":         FieldStatus,
+			"org":           "registrant.organization",
+			"person":        "registrant.name",
+			"taxpayer-id":   FieldIgnore,
+			"registrar":     FieldRegistrarName,
+			"admin-contact": FieldIgnore,
+			"created":       FieldCreatedDate,
+			"paid-till":     FieldExpiresDate,
+			"free-date":     FieldIgnore,
+			"source":        FieldIgnore,
+		},
+	}
+}
+
+// jprs is the template of .jp
+func jprs() *Template {
+	return &Template{
+		Name: "jprs",
+		TLDs: []string{"jp"},
+		Fields: map[string]Field{
+			"domain name":                        FieldDomainName,
+			"registrant":                         "registrant.organization",
+			"name server":                        FieldNameServers,
+			"signing key":                        FieldIgnore,
+			"created on":                         FieldCreatedDate,
+			"expires on":                         FieldExpiresDate,
+			"status":                             FieldStatus,
+			"last updated":                       FieldUpdatedDate,
+			"contact information/name":           "administrativeContact.name",
+			"contact information/email":          "administrativeContact.email",
+			"contact information/web page":       FieldIgnore,
+			"contact information/postal code":    "administrativeContact.postalCode",
+			"contact information/postal address": "administrativeContact.street",
+			"contact information/phone":          "administrativeContact.telephone",
+			"contact information/fax":            "administrativeContact.fax",
+		},
+	}
+}
+
+// cnnic is the template of .cn
+func cnnic() *Template {
+	return &Template{
+		Name: "cnnic",
+		TLDs: []string{"cn"},
+		Fields: map[string]Field{
+			"domain name":              FieldDomainName,
+			"roid":                     FieldIgnore,
+			"domain status":            FieldStatus,
+			"registrant":               "registrant.organization",
+			"registrant contact email": "registrant.email",
+			"sponsoring registrar":     FieldRegistrarName,
+			"name server":              FieldNameServers,
+			"registration time":        FieldCreatedDate,
+			"expiration time":          FieldExpiresDate,
+			"dnssec":                   FieldIgnore,
+		},
+	}
+}
+
+// eurid is the template of .eu
+func eurid() *Template {
+	return &Template{
+		Name: "eurid",
+		TLDs: []string{"eu"},
+		Fields: map[string]Field{
+			"domain":                 FieldDomainName,
+			"script":                 FieldIgnore,
+			"registrant":             "registrant.name",
+			"technical/name":         "technicalContact.name",
+			"technical/organisation": "technicalContact.organization",
+			"technical/language":     FieldIgnore,
+			"technical/phone":        "technicalContact.telephone",
+			"technical/fax":          "technicalContact.fax",
+			"technical/email":        "technicalContact.email",
+			"registrar/name":         FieldRegistrarName,
+			"registrar/website":      FieldReferralURL,
+			"name servers":           FieldNameServers,
+			"keys":                   FieldIgnore,
+		},
+	}
+}
+
+// auda is the template of .au
+func auda() *Template {
+	return &Template{
+		Name: "auda",
+		TLDs: []string{"au", "com.au", "net.au", "org.au", "edu.au", "gov.au", "asn.au", "id.au"},
+		Fields: map[string]Field{
+			"domain name":                   FieldDomainName,
+			"registry domain id":            FieldIgnore,
+			"registrar whois server":        FieldWhoisServer,
+			"registrar url":                 FieldReferralURL,
+			"last modified":                 FieldUpdatedDate,
+			"registrar name":                FieldRegistrarName,
+			"registrar abuse contact email": FieldContactEmail,
+			"registrar abuse contact phone": FieldIgnore,
+			"reseller name":                 FieldIgnore,
+			"status":                        FieldStatus,
+			"status reason":                 FieldIgnore,
+			"registrant contact id":         FieldIgnore,
+			"registrant contact name":       "registrant.name",
+			"registrant":                    "registrant.organization",
+			"registrant id":                 FieldIgnore,
+			"eligibility type":              FieldIgnore,
+			"eligibility name":              FieldIgnore,
+			"eligibility id":                FieldIgnore,
+			"tech contact id":               FieldIgnore,
+			"tech contact name":             "technicalContact.name",
+			"name server":                   FieldNameServers,
+			"dnssec":                        FieldIgnore,
+		},
+	}
+}
+
+// nicIT is the template of .it
+func nicIT() *Template {
+	t := &Template{
+		Name: "nic.it",
+		TLDs: []string{"it"},
+		Fields: map[string]Field{
+			"domain":                 FieldDomainName,
+			"status":                 FieldStatus,
+			"signed":                 FieldIgnore,
+			"created":                FieldCreatedDate,
+			"last update":            FieldUpdatedDate,
+			"expire date":            FieldExpiresDate,
+			"registrar/organization": FieldRegistrarName,
+			"registrar/name":         FieldIgnore,
+			"registrar/web":          FieldReferralURL,
+			"registrar/dnssec":       FieldIgnore,
+			"nameservers":            FieldNameServers,
+		},
+	}
+	for section, role := range map[string]string{
+		"registrant":         RoleRegistrant,
+		"admin contact":      RoleAdministrative,
+		"technical contacts": RoleTechnical,
+	} {
+		for key, field := range map[string]Field{
+			"name":         Field(role + ".name"),
+			"organization": Field(role + ".organization"),
+			"address":      Field(role + ".street"),
+			"contactid":    FieldIgnore,
+			"created":      FieldIgnore,
+			"last update":  FieldIgnore,
+		} {
+			t.Fields[section+"/"+key] = field
+		}
+	}
+	return t
+}
+
+// Templates returns the built-in templates: the ICANN format used for gTLDs and the names without
+// a specific template, .uk, .de, .nl, .fr, .br, .ru, .jp, .cn, .eu, .au and .it.
+// The templates are new copies, they can be modified and passed to New
+func Templates() []*Template {
+	return []*Template{
+		icann(),
+		nominet(),
+		denic(),
+		sidn(),
+		afnic(),
+		registroBR(),
+		tcinet(),
+		jprs(),
+		cnnic(),
+		eurid(),
+		auda(),
+		nicIT(),
+	}
+}